@@ -0,0 +1,149 @@
+// Command backfill_stats_dates recalcula os campos de data (date, week, month
+// e year) das entradas de study_stats no fuso de cada usuário. Deve ser
+// rodado uma vez depois do deploy do suporte a fusos e de novo, com -user,
+// quando um usuário muda de fuso e quer o histórico recontado.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"flashcard-backend/internal/config"
+	"flashcard-backend/internal/domain/entities"
+	"flashcard-backend/internal/infrastructure/database"
+	"flashcard-backend/internal/modules/gamification"
+
+	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func main() {
+	userID := flag.String("user", "", "recalcular só as estatísticas deste usuário")
+	dryRun := flag.Bool("dry-run", false, "só contar as entradas que mudariam")
+	batchSize := flag.Int("batch", 1000, "atualizações por escrita em lote")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system environment variables")
+	}
+	cfg := config.New()
+
+	db, err := database.NewMongoDB(cfg.Database.URI, cfg.Database.Name)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	locations, err := loadUserLocations(ctx, db, *userID)
+	if err != nil {
+		log.Fatal("Failed to load user time zones:", err)
+	}
+
+	filter := bson.M{}
+	if *userID != "" {
+		filter["user_id"] = *userID
+	}
+	opts := options.Find().SetProjection(bson.M{
+		"user_id": 1, "created_at": 1, "date": 1, "week": 1, "month": 1, "year": 1,
+	})
+	collection := db.GetCollection("study_stats")
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		log.Fatal("Failed to read study stats:", err)
+	}
+	defer cursor.Close(ctx)
+
+	var scanned, changed int
+	var updates []mongo.WriteModel
+	flush := func() {
+		if len(updates) == 0 || *dryRun {
+			updates = updates[:0]
+			return
+		}
+		if _, err := collection.BulkWrite(ctx, updates, options.BulkWrite().SetOrdered(false)); err != nil {
+			log.Fatal("Failed to update study stats:", err)
+		}
+		updates = updates[:0]
+	}
+
+	for cursor.Next(ctx) {
+		var stats entities.StudyStats
+		if err := cursor.Decode(&stats); err != nil {
+			log.Fatal("Failed to decode study stats:", err)
+		}
+		scanned++
+
+		loc, ok := locations[stats.UserID]
+		if !ok {
+			loc = time.UTC
+		}
+		before := stats
+		gamification.SetStatsDateFields(&stats, loc)
+		if stats.Date.Equal(before.Date) && stats.Week == before.Week && stats.Month == before.Month && stats.Year == before.Year {
+			continue
+		}
+
+		changed++
+		updates = append(updates, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": stats.ID}).
+			SetUpdate(bson.M{"$set": bson.M{
+				"date":  stats.Date,
+				"week":  stats.Week,
+				"month": stats.Month,
+				"year":  stats.Year,
+			}}))
+		if len(updates) >= *batchSize {
+			flush()
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		log.Fatal("Failed to read study stats:", err)
+	}
+	flush()
+
+	if *dryRun {
+		log.Printf("Scanned %d study stats, %d would change", scanned, changed)
+		return
+	}
+	log.Printf("Scanned %d study stats, updated %d", scanned, changed)
+}
+
+// loadUserLocations carrega o fuso dos usuários que definiram um. Fusos
+// inválidos ficam de fora e contam como UTC.
+func loadUserLocations(ctx context.Context, db *database.MongoDB, userID string) (map[string]*time.Location, error) {
+	filter := bson.M{"time_zone": bson.M{"$exists": true, "$ne": ""}}
+	if userID != "" {
+		id, err := primitive.ObjectIDFromHex(userID)
+		if err != nil {
+			return nil, err
+		}
+		filter["_id"] = id
+	}
+	opts := options.Find().SetProjection(bson.M{"time_zone": 1})
+	cursor, err := db.GetCollection("users").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var users []entities.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	locations := make(map[string]*time.Location, len(users))
+	for _, user := range users {
+		loc, err := time.LoadLocation(user.TimeZone)
+		if err != nil {
+			log.Printf("Ignoring invalid time zone %q of user %s", user.TimeZone, user.ID.Hex())
+			continue
+		}
+		locations[user.ID.Hex()] = loc
+	}
+	return locations, nil
+}
@@ -15,10 +15,13 @@ import (
 	"flashcard-backend/internal/infrastructure/server/middleware"
 	"flashcard-backend/internal/modules/admin"
 	"flashcard-backend/internal/modules/auth"
+	"flashcard-backend/internal/modules/backup"
+	"flashcard-backend/internal/modules/catalog"
 	"flashcard-backend/internal/modules/favorites"
 	"flashcard-backend/internal/modules/flashcards"
 	"flashcard-backend/internal/modules/gamification"
 	plansModule "flashcard-backend/internal/modules/plans"
+	"flashcard-backend/internal/modules/upload"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -51,13 +54,22 @@ func main() {
 	// Initialize modules
 	authModule := auth.NewModule(db, cfg)
 	adminModule := admin.NewModule(db.Database)
-	flashcardsModule := flashcards.NewModule(db, cfg, adminModule.Service, authModule.Service)
+	uploadModule := upload.NewModule(cfg)
+	flashcardsModule := flashcards.NewModule(db, cfg, adminModule.Service, authModule.Service, uploadModule.Service)
 	plansModuleInstance := plansModule.NewModule(db, cfg)
 	gamificationModule := gamification.NewModule(db, cfg)
 	favoriteModule := favorites.NewModule(db)
+	backupModule := backup.NewModule(db, uploadModule.Service, flashcardsModule.Service)
+	catalogModule := catalog.NewModule(db)
 
 	// Setup routes
-	server.SetupRoutes(router, authModule, flashcardsModule, plansModuleInstance, gamificationModule, favoriteModule, adminModule, cfg)
+	server.SetupRoutes(router, authModule, flashcardsModule, plansModuleInstance, gamificationModule, favoriteModule, adminModule, backupModule, catalogModule, cfg)
+
+	// Start background jobs
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go flashcardsModule.Service.StartTrashPurge(jobsCtx)
+	go flashcardsModule.Service.StartStudySessionExpiry(jobsCtx)
 
 	// Create HTTP server
 	srv := &http.Server{
@@ -78,6 +90,7 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Println("Shutting down server...")
+	stopJobs()
 
 	// Give outstanding requests a deadline for completion
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -12,6 +12,8 @@ type Config struct {
 	OAuth    OAuthConfig
 	AWS      AWSConfig
 	Stripe   StripeConfig
+	Trash    TrashConfig
+	Study    StudyConfig
 }
 
 type ServerConfig struct {
@@ -50,6 +52,15 @@ type StripeConfig struct {
 	WebhookSecret string
 }
 
+type TrashConfig struct {
+	RetentionDays        int // dias até a lixeira ser esvaziada
+	PurgeIntervalMinutes int
+}
+
+type StudyConfig struct {
+	SessionTimeoutMinutes int // inatividade até a sessão de estudo ser abandonada
+}
+
 func New() *Config {
 	return &Config{
 		Server: ServerConfig{
@@ -82,6 +93,13 @@ func New() *Config {
 			PublishableKey: getEnv("STRIPE_PUBLISHABLE_KEY", ""),
 			WebhookSecret:  getEnv("STRIPE_WEBHOOK_SECRET", ""),
 		},
+		Trash: TrashConfig{
+			RetentionDays:        getEnvAsInt("TRASH_RETENTION_DAYS", 30),
+			PurgeIntervalMinutes: getEnvAsInt("TRASH_PURGE_INTERVAL_MINUTES", 60),
+		},
+		Study: StudyConfig{
+			SessionTimeoutMinutes: getEnvAsInt("STUDY_SESSION_TIMEOUT_MINUTES", 30),
+		},
 	}
 }
 
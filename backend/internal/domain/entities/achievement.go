@@ -12,7 +12,7 @@ type Achievement struct {
 	Name        string               `bson:"name" json:"name"`
 	Description string               `bson:"description" json:"description"`
 	Icon        string               `bson:"icon" json:"icon"`
-	Category    string               `bson:"category" json:"category"` // study, creation, streak, accuracy, etc.
+	Category    string               `bson:"category" json:"category"` // study, creation, streak, accuracy, goal, etc.
 	Condition   AchievementCondition `bson:"condition" json:"condition"`
 	Unlocked    bool                 `bson:"unlocked" json:"unlocked"`
 	UnlockedAt  *time.Time           `bson:"unlocked_at,omitempty" json:"unlocked_at,omitempty"`
@@ -24,7 +24,7 @@ type Achievement struct {
 }
 
 type AchievementCondition struct {
-	Type      string `bson:"type" json:"type"`         // total_cards, total_decks, study_streak, accuracy, study_time, etc.
+	Type      string `bson:"type" json:"type"`         // total_cards, total_decks, study_streak, accuracy, study_time, goal_days, etc.
 	Operator  string `bson:"operator" json:"operator"` // >=, <=, ==, etc.
 	Value     int    `bson:"value" json:"value"`
 	TimeFrame string `bson:"time_frame" json:"time_frame"` // daily, weekly, monthly, all_time
@@ -162,6 +162,32 @@ var AchievementTemplates = []Achievement{
 		Target: 20,
 		XP:     150,
 	},
+	{
+		Name:        "Meta Cumprida",
+		Description: "Cumpra sua meta diária pela primeira vez",
+		Icon:        "✅",
+		Category:    "goal",
+		Condition: AchievementCondition{
+			Type:     "goal_days",
+			Operator: ">=",
+			Value:    1,
+		},
+		Target: 1,
+		XP:     50,
+	},
+	{
+		Name:        "Disciplinado",
+		Description: "Cumpra sua meta diária em 30 dias",
+		Icon:        "📅",
+		Category:    "goal",
+		Condition: AchievementCondition{
+			Type:     "goal_days",
+			Operator: ">=",
+			Value:    30,
+		},
+		Target: 30,
+		XP:     250,
+	},
 }
 
 type UserAchievement struct {
@@ -0,0 +1,39 @@
+package entities
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Ações que geram uma revisão de card
+const (
+	RevisionOriginal = "original" // conteúdo anterior à primeira alteração registrada
+	RevisionCreate   = "create"
+	RevisionUpdate   = "update"
+	RevisionRestore  = "restore"
+	RevisionBulk     = "bulk"
+	RevisionSync     = "sync"
+	RevisionMerge    = "merge" // tags recebidas de duplicatas juntadas
+)
+
+// CardRevision guarda o conteúdo de um card depois de cada alteração
+type CardRevision struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CardID       string             `bson:"card_id" json:"card_id"`
+	DeckID       string             `bson:"deck_id" json:"deck_id"`
+	AuthorID     string             `bson:"author_id,omitempty" json:"author_id,omitempty"`
+	AuthorName   string             `bson:"author_name,omitempty" json:"author_name,omitempty"`
+	Action       string             `bson:"action" json:"action"`
+	Changes      []FieldChange      `bson:"changes,omitempty" json:"changes,omitempty"`
+	Content      CardContent        `bson:"content" json:"content"`
+	RestoredFrom string             `bson:"restored_from,omitempty" json:"restored_from,omitempty"`
+	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// FieldChange é a alteração de um campo do card
+type FieldChange struct {
+	Field  string      `bson:"field" json:"field"`
+	Before interface{} `bson:"before,omitempty" json:"before,omitempty"`
+	After  interface{} `bson:"after,omitempty" json:"after,omitempty"`
+}
@@ -0,0 +1,54 @@
+package entities
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tipos de meta diária
+const (
+	GoalMinutes       = "minutes"        // minutos estudados
+	GoalCardsReviewed = "cards_reviewed" // revisões feitas
+	GoalNewCards      = "new_cards"      // cards revisados pela primeira vez
+)
+
+// DailyGoal é uma versão da meta diária do usuário. Mudar a meta grava uma
+// nova versão valendo a partir do dia da mudança, para o histórico continuar
+// medido pela meta que valia em cada dia. Target zero desliga a meta.
+type DailyGoal struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"-"`
+	UserID        string             `bson:"user_id" json:"-"`
+	Type          string             `bson:"type,omitempty" json:"type,omitempty"`
+	Target        int                `bson:"target" json:"target"`
+	EffectiveFrom string             `bson:"effective_from" json:"effective_from"` // YYYY-MM-DD no fuso do usuário
+	UpdatedAt     time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// GoalDay é a atividade de um dia e o progresso da meta que valia nele
+type GoalDay struct {
+	Date          string  `json:"date"` // YYYY-MM-DD
+	Minutes       int     `json:"minutes"`
+	CardsReviewed int     `json:"cards_reviewed"`
+	NewCards      int     `json:"new_cards"`
+	Type          string  `json:"type,omitempty"` // vazio se não havia meta
+	Target        int     `json:"target,omitempty"`
+	Progress      int     `json:"progress"`
+	Percent       float64 `json:"percent"` // limitado a 100
+	Met           bool    `json:"met"`
+}
+
+// GoalToday é o progresso da meta hoje
+type GoalToday struct {
+	GoalDay
+	TimeZone string `json:"time_zone"`
+	Streak   int    `json:"streak"` // dias seguidos com a meta cumprida
+}
+
+// GoalHistory é o histórico diário da meta, para o calendário de atividade
+type GoalHistory struct {
+	TimeZone string    `json:"time_zone"`
+	Streak   int       `json:"streak"`
+	DaysMet  int       `json:"days_met"`
+	Days     []GoalDay `json:"days"`
+}
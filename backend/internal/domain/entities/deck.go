@@ -7,18 +7,40 @@ import (
 )
 
 type Deck struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	UserID      string             `bson:"userId" json:"user_id"`
-	Name        string             `bson:"name" json:"name"`
-	Description string             `bson:"description,omitempty" json:"description,omitempty"`
-	Tags        []string           `bson:"tags,omitempty" json:"tags,omitempty"`
-	Color       string             `bson:"color,omitempty" json:"color,omitempty"`
-	Border      string             `bson:"border,omitempty" json:"border,omitempty"`
-	Background  string             `bson:"background,omitempty" json:"background,omitempty"`
-	CardCount   int                `bson:"cardCount" json:"card_count"`
-	IsPublic    bool               `bson:"isPublic" json:"is_public"`
-	CreatedAt   time.Time          `bson:"createdAt" json:"created_at"`
-	UpdatedAt   time.Time          `bson:"updatedAt" json:"updated_at"`
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID        string             `bson:"userId" json:"user_id"`
+	Name          string             `bson:"name" json:"name"` // caminho completo, ex.: "Medicina::Cardiologia::ECG"
+	ParentID      string             `bson:"parentId,omitempty" json:"parent_id,omitempty"`
+	Description   string             `bson:"description,omitempty" json:"description,omitempty"`
+	Tags          []string           `bson:"tags,omitempty" json:"tags,omitempty"`
+	Color         string             `bson:"color,omitempty" json:"color,omitempty"`
+	Border        string             `bson:"border,omitempty" json:"border,omitempty"`
+	Background    string             `bson:"background,omitempty" json:"background,omitempty"`
+	CardCount     int                `bson:"cardCount" json:"card_count"`
+	IsPublic      bool               `bson:"isPublic" json:"is_public"`
+	ForkedFrom    string             `bson:"forkedFrom,omitempty" json:"forked_from,omitempty"` // deck de origem quando for uma cópia
+	ForkCount     int                `bson:"forkCount,omitempty" json:"fork_count"`
+	Language      string             `bson:"language,omitempty" json:"language,omitempty"`
+	RatingAverage float64            `bson:"ratingAverage,omitempty" json:"rating_average"` // média das avaliações
+	RatingCount   int                `bson:"ratingCount,omitempty" json:"rating_count"`
+	Role          string             `bson:"-" json:"role,omitempty"` // papel de quem consulta, quando relevante
+	CreatedAt     time.Time          `bson:"createdAt" json:"created_at"`
+	UpdatedAt     time.Time          `bson:"updatedAt" json:"updated_at"`
+
+	// ModerationLockedAt é quando a moderação tirou o deck do catálogo; com a
+	// trava o dono não pode publicá-lo de novo
+	ModerationLockedAt *time.Time `bson:"moderationLockedAt,omitempty" json:"moderation_locked_at,omitempty"`
+	// DeletedOriginCards são os cards de origem cujas cópias o dono apagou do
+	// fork; a sincronização com a origem não os oferece de novo
+	DeletedOriginCards []string `bson:"deletedOriginCards,omitempty" json:"-"`
+}
+
+// DeckTreeNode é um deck com seus subdecks. TotalCardCount soma os cards do
+// deck e de todos os descendentes.
+type DeckTreeNode struct {
+	Deck
+	TotalCardCount int             `json:"total_card_count"`
+	Children       []*DeckTreeNode `json:"children"`
 }
 
 type DeckStats struct {
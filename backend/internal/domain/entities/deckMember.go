@@ -0,0 +1,51 @@
+package entities
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Papéis em um deck colaborativo. O dono é sempre Deck.UserID e não é
+// gravado como membro.
+const (
+	DeckRoleOwner  = "owner"
+	DeckRoleEditor = "editor"
+	DeckRoleViewer = "viewer"
+)
+
+// Situação de um membro
+const (
+	MemberPending = "pending"
+	MemberActive  = "active"
+)
+
+// DeckMember é um convite (pendente) ou a participação (ativa) de um usuário
+// em um deck de outra pessoa. Vale também para os subdecks.
+type DeckMember struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	DeckID     string             `bson:"deck_id" json:"deck_id"`
+	UserID     string             `bson:"user_id,omitempty" json:"user_id,omitempty"` // preenchido ao aceitar o convite
+	Email      string             `bson:"email" json:"email"`
+	Name       string             `bson:"name,omitempty" json:"name,omitempty"`
+	Role       string             `bson:"role" json:"role"`
+	Status     string             `bson:"status" json:"status"`
+	InvitedBy  string             `bson:"invited_by" json:"invited_by"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+	AcceptedAt *time.Time         `bson:"accepted_at,omitempty" json:"accepted_at,omitempty"`
+}
+
+// CardSchedule é o estado de revisão de um card para um membro do deck. O
+// dono continua usando os campos do próprio card.
+type CardSchedule struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID       string             `bson:"user_id" json:"user_id"`
+	CardID       string             `bson:"card_id" json:"card_id"`
+	DeckID       string             `bson:"deck_id" json:"deck_id"`
+	ReviewCount  int                `bson:"review_count" json:"review_count"`
+	LastReviewed *time.Time         `bson:"last_reviewed,omitempty" json:"last_reviewed,omitempty"`
+	NextReview   *time.Time         `bson:"next_review,omitempty" json:"next_review,omitempty"`
+	Lapses       int                `bson:"lapses,omitempty" json:"lapses,omitempty"`
+	Ease         float64            `bson:"ease,omitempty" json:"ease,omitempty"`
+	UpdatedAt    time.Time          `bson:"updated_at" json:"updated_at"`
+}
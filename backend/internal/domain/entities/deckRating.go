@@ -0,0 +1,28 @@
+package entities
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DeckRating é a nota (1 a 5) e a resenha de um usuário para um deck. Cada
+// usuário tem no máximo uma por deck.
+type DeckRating struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	DeckID    string             `bson:"deck_id" json:"deck_id"`
+	UserID    string             `bson:"user_id" json:"user_id"`
+	UserName  string             `bson:"user_name,omitempty" json:"user_name,omitempty"`
+	Rating    int                `bson:"rating" json:"rating"`
+	Review    string             `bson:"review,omitempty" json:"review,omitempty"`
+	Reply     *RatingReply       `bson:"reply,omitempty" json:"reply,omitempty"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// RatingReply é a resposta do autor do deck a uma resenha
+type RatingReply struct {
+	Text      string    `bson:"text" json:"text"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
@@ -21,17 +21,86 @@ type Flashcard struct {
 	ReviewCount        int                `bson:"reviewCount" json:"review_count"`
 	LastReviewed       *time.Time         `bson:"lastReviewed,omitempty" json:"last_reviewed,omitempty"`
 	NextReview         *time.Time         `bson:"nextReview,omitempty" json:"next_review,omitempty"`
+	Lapses             int                `bson:"lapses,omitempty" json:"lapses,omitempty"`                    // erros depois de o card já ter sido aprendido
+	Ease               float64            `bson:"ease,omitempty" json:"ease,omitempty"`                        // fator de facilidade; zero equivale ao inicial
+	Suspended          bool               `bson:"suspended,omitempty" json:"suspended,omitempty"`              // fora das revisões até ser reativado
+	FilteredSession    string             `bson:"filteredSession,omitempty" json:"filtered_session,omitempty"` // sessão filtrada que está com o card
+	Origin             *CardOrigin        `bson:"origin,omitempty" json:"origin,omitempty"`
 	CreatedAt          time.Time          `bson:"createdAt" json:"created_at"`
 	UpdatedAt          time.Time          `bson:"updatedAt" json:"updated_at"`
 }
 
+// CardOrigin aponta para o card de onde uma cópia (fork) foi feita. Base
+// guarda o conteúdo original na última sincronização, usado para separar
+// mudanças do autor das edições locais.
+type CardOrigin struct {
+	DeckID   string       `bson:"deckId" json:"deck_id"`
+	CardID   string       `bson:"cardId" json:"card_id"`
+	ForkedAt time.Time    `bson:"forkedAt" json:"forked_at"`
+	SyncedAt *time.Time   `bson:"syncedAt,omitempty" json:"synced_at,omitempty"`
+	Base     *CardContent `bson:"base,omitempty" json:"-"`
+}
+
+// CardContent é a parte editável de um card, sem o estado de revisão
+type CardContent struct {
+	Question           string   `bson:"question" json:"question"`
+	Answer             string   `bson:"answer" json:"answer"`
+	Alternatives       []string `bson:"alternatives,omitempty" json:"alternatives,omitempty"`
+	CorrectAlternative *int     `bson:"correctAlternative,omitempty" json:"correctAlternative,omitempty"`
+	ImageURL           string   `bson:"imageUrl,omitempty" json:"image_url,omitempty"`
+	AudioURL           string   `bson:"audioUrl,omitempty" json:"audio_url,omitempty"`
+	Tags               []string `bson:"tags,omitempty" json:"tags,omitempty"`
+}
+
+// Estados de uma sessão de estudo
+const (
+	StudySessionActive    = "active"
+	StudySessionCompleted = "completed"
+	StudySessionAbandoned = "abandoned" // encerrada sozinha por inatividade
+)
+
+// Tipos de sessão além da sessão de um deck
+const (
+	StudySessionFiltered    = "filtered"    // montada a partir de uma busca, em vez dos cards vencidos de um deck
+	StudySessionInterleaved = "interleaved" // cards vencidos de vários decks intercalados
+)
+
+// StudySession é uma sessão conduzida pelo servidor: a fila de cards é
+// montada no início, os cards são entregues um a um e as respostas ficam
+// registradas na sessão
 type StudySession struct {
-	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	UserID        primitive.ObjectID `bson:"user_id" json:"user_id"`
-	DeckID        primitive.ObjectID `bson:"deck_id" json:"deck_id"`
-	StartTime     time.Time          `bson:"start_time" json:"start_time"`
-	EndTime       time.Time          `bson:"end_time,omitempty" json:"end_time,omitempty"`
-	Duration      int                `bson:"duration" json:"duration"` // in minutes
-	CardsReviewed int                `bson:"cards_reviewed" json:"cards_reviewed"`
-	Score         float64            `bson:"score" json:"score"` // percentage correct
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID          primitive.ObjectID `bson:"user_id" json:"user_id"`
+	DeckID          primitive.ObjectID `bson:"deck_id" json:"deck_id"`
+	Status          string             `bson:"status,omitempty" json:"status,omitempty"`
+	Kind            string             `bson:"kind,omitempty" json:"kind,omitempty"`             // vazio para sessões de um deck
+	Query           string             `bson:"query,omitempty" json:"query,omitempty"`           // busca que montou a sessão filtrada
+	Reschedule      bool               `bson:"reschedule,omitempty" json:"reschedule,omitempty"` // respostas da sessão filtrada reagendam os cards
+	DeckIDs         []string           `bson:"deck_ids,omitempty" json:"deck_ids,omitempty"`     // decks de uma sessão intercalada
+	Strategy        string             `bson:"strategy,omitempty" json:"strategy,omitempty"`     // como os decks foram intercalados
+	StartTime       time.Time          `bson:"start_time" json:"start_time"`
+	EndTime         time.Time          `bson:"end_time,omitempty" json:"end_time,omitempty"`
+	LastActivity    time.Time          `bson:"last_activity,omitempty" json:"last_activity,omitempty"`
+	Duration        int                `bson:"duration" json:"duration"` // in minutes
+	DurationSeconds int                `bson:"duration_seconds,omitempty" json:"duration_seconds,omitempty"`
+	Queue           []string           `bson:"queue,omitempty" json:"-"`       // IDs dos cards, na ordem de estudo
+	Position        int                `bson:"position" json:"position"`       // índice do próximo card da fila
+	TotalCards      int                `bson:"total_cards" json:"total_cards"` // tamanho da fila
+	ShownAt         *time.Time         `bson:"shown_at,omitempty" json:"-"`    // quando o card atual foi entregue
+	Answers         []SessionAnswer    `bson:"answers,omitempty" json:"answers,omitempty"`
+	CardsReviewed   int                `bson:"cards_reviewed" json:"cards_reviewed"`
+	CorrectCount    int                `bson:"correct_count" json:"correct_count"`
+	Score           float64            `bson:"score" json:"score"` // percentage correct
+	XP              int                `bson:"xp,omitempty" json:"xp,omitempty"`
+}
+
+// SessionAnswer é uma resposta registrada numa sessão de estudo
+type SessionAnswer struct {
+	CardID     string    `bson:"card_id" json:"card_id"`
+	DeckID     string    `bson:"deck_id" json:"deck_id"`
+	Difficulty string    `bson:"difficulty" json:"difficulty"` // "again", "hard", "good", "easy"
+	IsCorrect  bool      `bson:"is_correct" json:"is_correct"`
+	TimeSpent  int       `bson:"time_spent" json:"time_spent"` // em segundos
+	XP         int       `bson:"xp" json:"xp"`
+	AnsweredAt time.Time `bson:"answered_at" json:"answered_at"`
 }
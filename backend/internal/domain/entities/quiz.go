@@ -0,0 +1,56 @@
+package entities
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Estados de uma tentativa de quiz
+const (
+	QuizInProgress = "in_progress"
+	QuizCompleted  = "completed"
+	QuizExpired    = "expired" // o tempo acabou antes de o aluno entregar
+)
+
+// QuizAttempt é uma tentativa de quiz sobre um deck. As respostas são
+// corrigidas pelo servidor e ficam separadas das revisões espaçadas: um quiz
+// não reagenda cards nem entra nas estatísticas de estudo.
+type QuizAttempt struct {
+	ID                 primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID             string             `bson:"user_id" json:"user_id"`
+	DeckID             string             `bson:"deck_id" json:"deck_id"`
+	Status             string             `bson:"status" json:"status"`
+	MultipleChoiceOnly bool               `bson:"multiple_choice_only,omitempty" json:"multiple_choice_only,omitempty"`
+	TimeLimitSeconds   int                `bson:"time_limit_seconds,omitempty" json:"time_limit_seconds,omitempty"` // 0 = sem limite
+	Questions          []QuizQuestion     `bson:"questions" json:"questions,omitempty"`
+	StartedAt          time.Time          `bson:"started_at" json:"started_at"`
+	Deadline           *time.Time         `bson:"deadline,omitempty" json:"deadline,omitempty"`
+	FinishedAt         *time.Time         `bson:"finished_at,omitempty" json:"finished_at,omitempty"`
+	DurationSeconds    int                `bson:"duration_seconds,omitempty" json:"duration_seconds,omitempty"`
+	CorrectCount       int                `bson:"correct_count" json:"correct_count"`
+	Score              float64            `bson:"score" json:"score"` // percentage correct
+}
+
+// QuizQuestion é um card sorteado para o quiz, copiado no início da tentativa
+// para editar o deck depois não mudar a correção. A resposta certa só aparece
+// no relatório.
+type QuizQuestion struct {
+	CardID             string        `bson:"card_id" json:"card_id"`
+	Question           string        `bson:"question" json:"question"`
+	Alternatives       []string      `bson:"alternatives,omitempty" json:"alternatives,omitempty"`
+	ImageURL           *string       `bson:"image_url,omitempty" json:"image_url,omitempty"`
+	AudioURL           *string       `bson:"audio_url,omitempty" json:"audio_url,omitempty"`
+	Answer             string        `bson:"answer" json:"-"`
+	CorrectAlternative *int          `bson:"correct_alternative,omitempty" json:"-"`
+	Response           *QuizResponse `bson:"response,omitempty" json:"response,omitempty"`
+}
+
+// QuizResponse é a resposta dada a uma questão
+type QuizResponse struct {
+	Alternative *int      `bson:"alternative,omitempty" json:"alternative,omitempty"` // questões de múltipla escolha
+	Text        string    `bson:"text,omitempty" json:"text,omitempty"`               // questões abertas
+	IsCorrect   bool      `bson:"is_correct" json:"-"`
+	TimeSpent   int       `bson:"time_spent" json:"time_spent"` // em segundos, desde a resposta anterior
+	AnsweredAt  time.Time `bson:"answered_at" json:"answered_at"`
+}
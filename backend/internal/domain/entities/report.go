@@ -0,0 +1,60 @@
+package entities
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Conteúdo que pode ser denunciado
+const (
+	ReportTargetDeck = "deck"
+	ReportTargetCard = "card"
+)
+
+// Situação de uma denúncia
+const (
+	ReportOpen      = "open"
+	ReportDismissed = "dismissed"
+	ReportActioned  = "actioned"
+)
+
+// Ações de moderação registradas na trilha de auditoria
+const (
+	ModerationDismiss   = "dismiss"
+	ModerationUnpublish = "unpublish"
+	ModerationSuspend   = "suspend"
+	ModerationUnsuspend = "unsuspend"
+)
+
+// ContentReport é a denúncia de um deck público ou de um card dele
+type ContentReport struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TargetType  string             `bson:"target_type" json:"target_type"`
+	TargetID    string             `bson:"target_id" json:"target_id"`
+	DeckID      string             `bson:"deck_id" json:"deck_id"`           // deck denunciado ou deck do card
+	AuthorID    string             `bson:"author_id" json:"author_id"`       // dono do conteúdo
+	TargetTitle string             `bson:"target_title" json:"target_title"` // nome do deck ou pergunta do card no momento da denúncia
+	ReporterID  string             `bson:"reporter_id" json:"reporter_id"`
+	Reason      string             `bson:"reason" json:"reason"`
+	Details     string             `bson:"details,omitempty" json:"details,omitempty"`
+	Status      string             `bson:"status" json:"status"`
+	Resolution  string             `bson:"resolution,omitempty" json:"resolution,omitempty"` // ação que encerrou a denúncia
+	ResolvedBy  string             `bson:"resolved_by,omitempty" json:"resolved_by,omitempty"`
+	ResolvedAt  *time.Time         `bson:"resolved_at,omitempty" json:"resolved_at,omitempty"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// ModerationAction é um registro da trilha de auditoria da moderação
+type ModerationAction struct {
+	ID               primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Action           string             `bson:"action" json:"action"`
+	AdminID          string             `bson:"admin_id" json:"admin_id"`
+	ReportID         string             `bson:"report_id,omitempty" json:"report_id,omitempty"`
+	DeckID           string             `bson:"deck_id,omitempty" json:"deck_id,omitempty"`
+	AuthorID         string             `bson:"author_id,omitempty" json:"author_id,omitempty"`
+	Note             string             `bson:"note,omitempty" json:"note,omitempty"`
+	ResolvedReports  int                `bson:"resolved_reports" json:"resolved_reports"`   // denúncias encerradas pela ação
+	UnpublishedDecks int                `bson:"unpublished_decks" json:"unpublished_decks"` // decks tirados do catálogo
+	CreatedAt        time.Time          `bson:"created_at" json:"created_at"`
+}
@@ -0,0 +1,21 @@
+package entities
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ShareLink é um link não listado para um deck privado. O token não é
+// gravado: ele é o ID do link assinado com o segredo do servidor.
+type ShareLink struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	DeckID    string             `bson:"deck_id" json:"deck_id"`
+	UserID    string             `bson:"user_id" json:"user_id"`
+	Token     string             `bson:"-" json:"token,omitempty"`
+	ExpiresAt *time.Time         `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	MaxUses   int                `bson:"max_uses" json:"max_uses"` // 0 = sem limite
+	Uses      int                `bson:"uses" json:"uses"`         // importações feitas pelo link
+	RevokedAt *time.Time         `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
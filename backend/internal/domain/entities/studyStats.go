@@ -22,10 +22,10 @@ type StudyStats struct {
 	Level      int                    `bson:"level,omitempty" json:"level,omitempty"`
 	Metadata   map[string]interface{} `bson:"metadata,omitempty" json:"metadata,omitempty"` // dados adicionais específicos da ação
 	CreatedAt  time.Time              `bson:"created_at" json:"created_at"`
-	Date       time.Time              `bson:"date" json:"date"`   // data para agrupamento (sem hora)
-	Week       int                    `bson:"week" json:"week"`   // semana do ano
-	Month      int                    `bson:"month" json:"month"` // mês do ano
-	Year       int                    `bson:"year" json:"year"`   // ano
+	Date       time.Time              `bson:"date" json:"date"`   // dia no fuso do usuário, à meia-noite UTC, para agrupamento
+	Week       int                    `bson:"week" json:"week"`   // semana ISO no fuso do usuário
+	Month      int                    `bson:"month" json:"month"` // mês no fuso do usuário
+	Year       int                    `bson:"year" json:"year"`   // ano ISO da semana
 }
 
 // StudyStatsSummary representa um resumo das estatísticas
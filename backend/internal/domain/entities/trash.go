@@ -0,0 +1,31 @@
+package entities
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tipos de item da lixeira
+const (
+	TrashDeck = "deck"
+	TrashCard = "card"
+)
+
+// TrashItem guarda um deck ou card apagado até ser restaurado ou expirar.
+// Document é o documento original, restaurado com o mesmo ID.
+type TrashItem struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID       string             `bson:"user_id" json:"user_id"`
+	Type         string             `bson:"type" json:"type"`
+	ItemID       primitive.ObjectID `bson:"item_id" json:"item_id"`
+	Name         string             `bson:"name" json:"name"`
+	DeckID       string             `bson:"deck_id,omitempty" json:"deck_id,omitempty"`
+	DeletedWith  string             `bson:"deleted_with,omitempty" json:"-"` // item do deck apagado junto
+	CardCount    int                `bson:"card_count,omitempty" json:"card_count,omitempty"`
+	SubdeckCount int                `bson:"subdeck_count,omitempty" json:"subdeck_count,omitempty"`
+	Document     bson.Raw           `bson:"document" json:"-"`
+	DeletedAt    time.Time          `bson:"deleted_at" json:"deleted_at"`
+	ExpiresAt    time.Time          `bson:"expires_at" json:"expires_at"`
+}
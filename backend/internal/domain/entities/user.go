@@ -7,19 +7,22 @@ import (
 )
 
 type User struct {
-	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Email      string             `bson:"email" json:"email"`
-	Name       string             `bson:"name" json:"name"`
-	Password   string             `bson:"password,omitempty" json:"-"` // omitido do JSON por segurança
-	Avatar     string             `bson:"avatar,omitempty" json:"avatar,omitempty"`
-	Provider   string             `bson:"provider" json:"provider"` // "google", "linkedin"
-	ProviderID string             `bson:"provider_id" json:"provider_id"`
-	Plan       string             `bson:"plan" json:"plan"` // "free", "premium"
-	XP         int                `bson:"xp" json:"xp"`
-	Streak     int                `bson:"streak" json:"streak"`
-	LastLogin  time.Time          `bson:"last_login" json:"last_login"`
-	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt  time.Time          `bson:"updated_at" json:"updated_at"`
+	ID               primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Email            string             `bson:"email" json:"email"`
+	Name             string             `bson:"name" json:"name"`
+	Password         string             `bson:"password,omitempty" json:"-"` // omitido do JSON por segurança
+	Avatar           string             `bson:"avatar,omitempty" json:"avatar,omitempty"`
+	Provider         string             `bson:"provider" json:"provider"` // "google", "linkedin"
+	ProviderID       string             `bson:"provider_id" json:"provider_id"`
+	Plan             string             `bson:"plan" json:"plan"` // "free", "premium"
+	XP               int                `bson:"xp" json:"xp"`
+	Streak           int                `bson:"streak" json:"streak"`
+	TimeZone         string             `bson:"time_zone,omitempty" json:"time_zone,omitempty"` // fuso IANA (ex. America/Sao_Paulo) dos limites de dia; vazio é UTC
+	LastLogin        time.Time          `bson:"last_login" json:"last_login"`
+	SuspendedAt      *time.Time         `bson:"suspended_at,omitempty" json:"suspended_at,omitempty"` // suspenso pela moderação: não publica decks
+	SuspensionReason string             `bson:"suspension_reason,omitempty" json:"suspension_reason,omitempty"`
+	CreatedAt        time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt        time.Time          `bson:"updated_at" json:"updated_at"`
 }
 
 type UserStats struct {
@@ -6,6 +6,7 @@ import (
 	"log"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
@@ -78,6 +79,30 @@ func createIndexes(ctx context.Context, db *mongo.Database) error {
 		return fmt.Errorf("failed to create decks userId index: %v", err)
 	}
 
+	// Subdecks são buscados pelo caminho completo no nome
+	_, err = decksCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "userId", Value: 1},
+			{Key: "name", Value: 1},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create decks userId_name index: %v", err)
+	}
+
+	// Catálogo: decks públicos e busca textual. O campo language do deck é um
+	// código livre, então não pode ser o language override do índice.
+	_, err = decksCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "isPublic", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{
+			Keys:    bson.D{{Key: "name", Value: "text"}, {Key: "description", Value: "text"}, {Key: "tags", Value: "text"}},
+			Options: options.Index().SetDefaultLanguage("none").SetLanguageOverride("textLanguage"),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create decks catalog indexes: %v", err)
+	}
+
 	// Cards collection indexes
 	cardsCollection := db.Collection("cards")
 	_, err = cardsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
@@ -89,6 +114,142 @@ func createIndexes(ctx context.Context, db *mongo.Database) error {
 		return fmt.Errorf("failed to create cards deckId index: %v", err)
 	}
 
+	// Uma coleção só pode ter um índice textual: a versão anterior, sem as
+	// alternativas, é removida antes de criar a atual
+	_, _ = cardsCollection.Indexes().DropOne(ctx, "question_text_answer_text_tags_text")
+	_, err = cardsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "question", Value: "text"},
+			{Key: "answer", Value: "text"},
+			{Key: "alternatives", Value: "text"},
+			{Key: "tags", Value: "text"},
+		},
+		Options: options.Index().
+			SetName("cards_text").
+			SetDefaultLanguage("none").
+			SetWeights(bson.D{{Key: "question", Value: 3}, {Key: "tags", Value: 2}, {Key: "answer", Value: 1}, {Key: "alternatives", Value: 1}}),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create cards text index: %v", err)
+	}
+
+	_, err = cardsCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "deckId", Value: 1}, {Key: "updatedAt", Value: -1}}},
+		{Keys: bson.D{{Key: "deckId", Value: 1}, {Key: "nextReview", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create cards search indexes: %v", err)
+	}
+
+	// Cards presos a uma sessão filtrada, liberados quando ela acaba
+	_, err = cardsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "filteredSession", Value: 1}},
+		Options: options.Index().SetSparse(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create cards filtered session index: %v", err)
+	}
+
+	// Imported clippings collection indexes
+	importedClippingsCollection := db.Collection("imported_clippings")
+	_, err = importedClippingsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "user_id", Value: 1},
+			{Key: "key", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create imported_clippings user_id_key index: %v", err)
+	}
+
+	// Card revisions collection indexes
+	cardRevisionsCollection := db.Collection("card_revisions")
+	_, err = cardRevisionsCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "card_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "deck_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create card_revisions indexes: %v", err)
+	}
+
+	// Deck members collection indexes
+	deckMembersCollection := db.Collection("deck_members")
+	_, err = deckMembersCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "deck_id", Value: 1}, {Key: "email", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "status", Value: 1}}},
+		{Keys: bson.D{{Key: "email", Value: 1}, {Key: "status", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create deck_members indexes: %v", err)
+	}
+
+	// Card schedules collection indexes
+	cardSchedulesCollection := db.Collection("card_schedules")
+	_, err = cardSchedulesCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "card_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "deck_id", Value: 1}}},
+		{Keys: bson.D{{Key: "card_id", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "updated_at", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create card_schedules indexes: %v", err)
+	}
+
+	// Deck ratings collection indexes
+	deckRatingsCollection := db.Collection("deck_ratings")
+	_, err = deckRatingsCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "deck_id", Value: 1}, {Key: "user_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "deck_id", Value: 1}, {Key: "updated_at", Value: -1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create deck_ratings indexes: %v", err)
+	}
+
+	// Share links collection indexes
+	shareLinksCollection := db.Collection("share_links")
+	_, err = shareLinksCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "deck_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create share_links indexes: %v", err)
+	}
+
+	// Reports collection indexes
+	reportsCollection := db.Collection("reports")
+	_, err = reportsCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "reporter_id", Value: 1}, {Key: "target_id", Value: 1}, {Key: "status", Value: 1}}},
+		{Keys: bson.D{{Key: "deck_id", Value: 1}, {Key: "status", Value: 1}}},
+		{Keys: bson.D{{Key: "author_id", Value: 1}, {Key: "status", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create reports indexes: %v", err)
+	}
+
+	// Moderation audit trail indexes
+	moderationActionsCollection := db.Collection("moderation_actions")
+	_, err = moderationActionsCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "author_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "deck_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create moderation_actions indexes: %v", err)
+	}
+
+	// Trash collection indexes
+	trashCollection := db.Collection("trash")
+	_, err = trashCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "deleted_at", Value: -1}}},
+		{Keys: bson.D{{Key: "deleted_with", Value: 1}}},
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}},
+		{Keys: bson.D{{Key: "deck_id", Value: 1}, {Key: "deleted_at", Value: -1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create trash indexes: %v", err)
+	}
+
 	// Study sessions collection indexes
 	sessionsCollection := db.Collection("study_sessions")
 	_, err = sessionsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
@@ -100,6 +261,54 @@ func createIndexes(ctx context.Context, db *mongo.Database) error {
 	if err != nil {
 		return fmt.Errorf("failed to create study_sessions userId_date index: %v", err)
 	}
+	// Sessões ativas paradas, encerradas por inatividade
+	_, err = sessionsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "last_activity", Value: 1}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create study_sessions status_last_activity index: %v", err)
+	}
+
+	// Quiz attempts collection indexes
+	quizCollection := db.Collection("quiz_attempts")
+	_, err = quizCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "started_at", Value: -1}}},
+		{Keys: bson.D{{Key: "deck_id", Value: 1}, {Key: "started_at", Value: -1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create quiz_attempts indexes: %v", err)
+	}
+
+	// Sync operations collection indexes: a chave de idempotência é única por
+	// usuário e o recibo expira depois de 30 dias
+	syncCollection := db.Collection("sync_operations")
+	_, err = syncCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(30 * 24 * 60 * 60),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create sync_operations indexes: %v", err)
+	}
+
+	// Deck access losses collection indexes: marcam quando um membro perdeu o
+	// acesso a um deck compartilhado e expiram junto com os itens da lixeira
+	accessLossCollection := db.Collection("deck_access_losses")
+	_, err = accessLossCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "lost_at", Value: 1}}},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create deck_access_losses indexes: %v", err)
+	}
 
 	// Notifications collection indexes
 	notificationsCollection := db.Collection("notifications")
@@ -195,5 +404,15 @@ func createIndexes(ctx context.Context, db *mongo.Database) error {
 		return fmt.Errorf("failed to create achievements user_id_category index: %v", err)
 	}
 
+	// Daily goals collection indexes: uma versão da meta por usuário e dia
+	dailyGoalsCollection := db.Collection("daily_goals")
+	_, err = dailyGoalsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "effective_from", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create daily_goals user_id_effective_from index: %v", err)
+	}
+
 	return nil
 }
@@ -0,0 +1,106 @@
+// Package mediaclient faz as requisições do servidor às mídias dos cards. As
+// URLs são gravadas pelos usuários, então o cliente só acessa os hosts de
+// mídia configurados e nunca endereços da rede interna.
+package mediaclient
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrHostNotAllowed = errors.New("media host is not allowed")
+	ErrPrivateAddress = errors.New("media host resolves to a private address")
+)
+
+// NewClient retorna um cliente que só acessa URLs https dos hosts
+// permitidos, inclusive nos redirecionamentos, e recusa conectar em
+// endereços privados, de loopback ou link-local (o IP é verificado na hora
+// da conexão, depois da resolução do nome)
+func NewClient(allowedHosts []string, timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+			}
+			return nil
+		},
+	}
+	transport := &http.Transport{
+		Proxy:                 nil, // sem proxy: a conexão vai ao IP verificado
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: timeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       90 * time.Second,
+	}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &allowListTransport{allowed: hostSet(allowedHosts), next: transport},
+	}
+}
+
+// Allowed indica se o cliente aceitaria a URL
+func Allowed(rawURL string, allowedHosts []string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && checkURL(u, hostSet(allowedHosts)) == nil
+}
+
+// allowListTransport confere cada requisição, inclusive as geradas por
+// redirecionamentos
+type allowListTransport struct {
+	allowed map[string]bool
+	next    http.RoundTripper
+}
+
+func (t *allowListTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := checkURL(req.URL, t.allowed); err != nil {
+		return nil, err
+	}
+	return t.next.RoundTrip(req)
+}
+
+func hostSet(hosts []string) map[string]bool {
+	set := make(map[string]bool, len(hosts))
+	for _, host := range hosts {
+		set[strings.ToLower(host)] = true
+	}
+	return set
+}
+
+func checkURL(u *url.URL, allowed map[string]bool) error {
+	if u.Scheme != "https" || u.User != nil || !allowed[strings.ToLower(u.Hostname())] {
+		return fmt.Errorf("%w: %s", ErrHostNotAllowed, u.Host)
+	}
+	if port := u.Port(); port != "" && port != "443" {
+		return fmt.Errorf("%w: %s", ErrHostNotAllowed, u.Host)
+	}
+	return nil
+}
+
+// isPublicIP recusa loopback, redes privadas, link-local (onde ficam os
+// endpoints de metadados das nuvens), CGNAT, multicast e não especificados
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		// 0.0.0.0/8 e 100.64.0.0/10
+		if ip4[0] == 0 || (ip4[0] == 100 && ip4[1]&0xc0 == 64) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,44 @@
+package mediaclient
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllowed(t *testing.T) {
+	hosts := []string{"bucket.s3.sa-east-1.amazonaws.com"}
+
+	assert.True(t, Allowed("https://bucket.s3.sa-east-1.amazonaws.com/cards/a.png", hosts))
+	assert.True(t, Allowed("https://BUCKET.s3.sa-east-1.amazonaws.com:443/a.png", hosts))
+	assert.False(t, Allowed("http://bucket.s3.sa-east-1.amazonaws.com/a.png", hosts))
+	assert.False(t, Allowed("https://bucket.s3.sa-east-1.amazonaws.com:8080/a.png", hosts))
+	assert.False(t, Allowed("https://evil.example.com/a.png", hosts))
+	assert.False(t, Allowed("https://169.254.169.254/latest/meta-data/", hosts))
+	assert.False(t, Allowed("https://user@bucket.s3.sa-east-1.amazonaws.com/a.png", hosts))
+	assert.False(t, Allowed("https://bucket.s3.sa-east-1.amazonaws.com/a.png", nil))
+}
+
+func TestIsPublicIP(t *testing.T) {
+	for _, ip := range []string{"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "100.64.0.1", "0.0.0.0", "::1", "fe80::1", "fd00::1", "::ffff:127.0.0.1"} {
+		assert.False(t, isPublicIP(net.ParseIP(ip)), ip)
+	}
+	for _, ip := range []string{"52.95.110.1", "8.8.8.8", "2600:1f18::1"} {
+		assert.True(t, isPublicIP(net.ParseIP(ip)), ip)
+	}
+}
+
+func TestClientRefusesRedirectToOtherHost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient([]string{"media.example.com"}, time.Second)
+	_, err := client.Get(server.URL)
+	assert.ErrorIs(t, err, ErrHostNotAllowed)
+}
@@ -0,0 +1,144 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Dimensões de uma página A4 em pontos
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+)
+
+// Document é um gerador mínimo de PDF que usa apenas as fontes padrão
+// Helvetica e Helvetica-Bold com codificação WinAnsi. Caracteres fora do
+// WinAnsi (grego, cirílico, CJK, emoji...) saem como '?'.
+type Document struct {
+	Width    float64
+	Height   float64
+	pages    []*Page
+	replaced int
+}
+
+// Page acumula os comandos de desenho de uma página. As coordenadas
+// usam origem no canto superior esquerdo.
+type Page struct {
+	doc     *Document
+	content bytes.Buffer
+}
+
+func New() *Document {
+	return &Document{Width: A4Width, Height: A4Height}
+}
+
+func (d *Document) AddPage() *Page {
+	page := &Page{doc: d}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// ReplacedChars é quantos caracteres do texto escrito não existem no WinAnsi
+// e saíram como '?'
+func (d *Document) ReplacedChars() int {
+	return d.replaced
+}
+
+// Text escreve uma linha de texto com a linha de base em (x, y)
+func (p *Page) Text(x, y, size float64, bold bool, text string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	encoded, replaced := encode(text)
+	p.doc.replaced += replaced
+	fmt.Fprintf(&p.content, "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, p.doc.Height-y, escape(encoded))
+}
+
+// Line desenha uma linha; dashed usa traço cinza, próprio para linhas de corte
+func (p *Page) Line(x1, y1, x2, y2, width float64, dashed bool) {
+	if dashed {
+		p.content.WriteString("q 0.6 G [4 3] 0 d\n")
+	}
+	fmt.Fprintf(&p.content, "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, p.doc.Height-y1, x2, p.doc.Height-y2)
+	if dashed {
+		p.content.WriteString("Q\n")
+	}
+}
+
+// Rect desenha o contorno de um retângulo com canto superior esquerdo em (x, y)
+func (p *Page) Rect(x, y, w, h, width float64, dashed bool) {
+	if dashed {
+		p.content.WriteString("q 0.6 G [4 3] 0 d\n")
+	}
+	fmt.Fprintf(&p.content, "%.2f w %.2f %.2f %.2f %.2f re S\n", width, x, p.doc.Height-y-h, w, h)
+	if dashed {
+		p.content.WriteString("Q\n")
+	}
+}
+
+// WriteTo serializa o documento
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	var offsets []int
+
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// 1: catálogo, 2: árvore de páginas, 3 e 4: fontes, depois página + conteúdo
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+i*2)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			d.Width, d.Height, 6+i*2))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.content.Len(), page.content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+// Bytes retorna o documento serializado
+func (d *Document) Bytes() []byte {
+	var buf bytes.Buffer
+	d.WriteTo(&buf)
+	return buf.Bytes()
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(', ')', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(s[i])
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,154 @@
+package pdf
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Larguras (em milésimos do corpo) dos caracteres ASCII 32..126
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
+
+// Caracteres do WinAnsi fora do Latin-1
+var winAnsiExtras = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91,
+	'’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98,
+	'™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// Letra base usada para medir caracteres acentuados
+var accentBase = map[rune]rune{}
+
+func init() {
+	groups := map[rune]string{
+		'A': "ÀÁÂÃÄÅ", 'C': "Ç", 'E': "ÈÉÊË", 'I': "ÌÍÎÏ", 'N': "Ñ", 'O': "ÒÓÔÕÖØ", 'U': "ÙÚÛÜ", 'Y': "ÝŸ",
+		'a': "àáâãäå", 'c': "ç", 'e': "èéêë", 'i': "ìíîï", 'n': "ñ", 'o': "òóôõöø", 'u': "ùúûü", 'y': "ýÿ",
+		'S': "Š", 's': "š", 'Z': "Ž", 'z': "ž",
+	}
+	for base, chars := range groups {
+		for _, r := range chars {
+			accentBase[r] = base
+		}
+	}
+}
+
+// encode converte texto UTF-8 para bytes WinAnsi, trocando o que não
+// for representável por '?', e retorna quantos caracteres foram trocados
+func encode(s string) (string, int) {
+	var b strings.Builder
+	replaced := 0
+	for _, r := range s {
+		switch {
+		case r == '\t':
+			b.WriteByte(' ')
+		case r >= 32 && r <= 126:
+			b.WriteByte(byte(r))
+		case r >= 0xA0 && r <= 0xFF:
+			b.WriteByte(byte(r))
+		default:
+			if c, ok := winAnsiExtras[r]; ok {
+				b.WriteByte(c)
+			} else if r >= 32 {
+				b.WriteByte('?')
+				replaced++
+			}
+		}
+	}
+	return b.String(), replaced
+}
+
+func runeWidth(r rune, bold bool) int {
+	widths := &helveticaWidths
+	if bold {
+		widths = &helveticaBoldWidths
+	}
+	if base, ok := accentBase[r]; ok {
+		r = base
+	}
+	switch {
+	case r >= 32 && r <= 126:
+		return widths[r-32]
+	case r == '“' || r == '”' || r == '‘' || r == '’':
+		return 333
+	case r == '—' || r == '…':
+		return 1000
+	}
+	return 556
+}
+
+// TextWidth mede o texto em pontos
+func TextWidth(s string, size float64, bold bool) float64 {
+	total := 0
+	for _, r := range s {
+		total += runeWidth(r, bold)
+	}
+	return float64(total) * size / 1000
+}
+
+// Wrap quebra o texto em linhas que cabem em maxWidth, respeitando as
+// quebras de linha existentes. Palavras maiores que a linha são cortadas.
+func Wrap(s string, size float64, bold bool, maxWidth float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(strings.ReplaceAll(s, "\r", ""), "\n") {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+		current := ""
+		for _, word := range words {
+			for TextWidth(word, size, bold) > maxWidth && utf8.RuneCountInString(word) > 1 {
+				if current != "" {
+					lines = append(lines, current)
+					current = ""
+				}
+				cut := fitRunes(word, size, bold, maxWidth)
+				lines = append(lines, word[:cut])
+				word = word[cut:]
+			}
+			candidate := word
+			if current != "" {
+				candidate = current + " " + word
+			}
+			if TextWidth(candidate, size, bold) <= maxWidth {
+				current = candidate
+				continue
+			}
+			lines = append(lines, current)
+			current = word
+		}
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+// fitRunes retorna quantos bytes do início de word cabem em maxWidth
+func fitRunes(word string, size float64, bold bool, maxWidth float64) int {
+	width := 0.0
+	for i, r := range word {
+		width += float64(runeWidth(r, bold)) * size / 1000
+		if width > maxWidth {
+			if i == 0 {
+				return utf8.RuneLen(r)
+			}
+			return i
+		}
+	}
+	return len(word)
+}
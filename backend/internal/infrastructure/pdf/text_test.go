@@ -0,0 +1,66 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncode(t *testing.T) {
+	cases := []struct {
+		in       string
+		out      string
+		replaced int
+	}{
+		{"Hello", "Hello", 0},
+		{"coração", "cora\xe7\xe3o", 0},
+		{"€ – “x”", "\x80 \x96 \x93x\x94", 0},
+		{"a\tb", "a b", 0},
+		{"a\x01b", "ab", 0}, // controles somem
+		{"Ωμέγα", "?????", 5},
+		{"日本語 ok", "??? ok", 3},
+	}
+	for _, tc := range cases {
+		out, replaced := encode(tc.in)
+		assert.Equal(t, tc.out, out, tc.in)
+		assert.Equal(t, tc.replaced, replaced, tc.in)
+	}
+}
+
+func TestEscape(t *testing.T) {
+	cases := map[string]string{
+		"plain":     "plain",
+		"(a)":       `\(a\)`,
+		`c:\dir`:    `c:\\dir`,
+		`((\))`:     `\(\(\\\)\)`,
+		"\x93x\x94": "\x93x\x94",
+	}
+	for in, out := range cases {
+		assert.Equal(t, out, escape(in), in)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	// Cada "W" tem 9,44pt no corpo 10: cabem cinco em 50pt
+	assert.Equal(t, []string{"WWWWW", "WWWWW", "WW"}, Wrap(strings.Repeat("W", 12), 10, false, 50))
+	// A palavra cortada fecha a linha em andamento
+	assert.Equal(t, []string{"a", "WWWWW", "WW b"}, Wrap("a WWWWWWW b", 10, false, 50))
+	// Acentos contam como a letra base e não são partidos no meio
+	lines := Wrap(strings.Repeat("É", 9), 10, false, 30)
+	assert.Equal(t, strings.Repeat("É", 9), strings.Join(lines, ""))
+	for _, line := range lines {
+		assert.LessOrEqual(t, TextWidth(line, 10, false), 30.0)
+	}
+	// Quebras de linha existentes são mantidas
+	assert.Equal(t, []string{"um dois", "", "tres"}, Wrap("um dois\r\n\ntres", 10, false, 500))
+}
+
+func TestDocumentCountsReplacedChars(t *testing.T) {
+	doc := New()
+	page := doc.AddPage()
+	page.Text(10, 10, 12, false, "Olá")
+	page.Text(10, 30, 12, true, "Привет")
+	assert.Equal(t, 6, doc.ReplacedChars())
+	assert.Contains(t, string(doc.Bytes()), "(Ol\xe1) Tj")
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireAdmin libera a rota apenas para admins. Deve vir depois do
+// AuthMiddleware, que define user_id e user_email.
+func RequireAdmin(adminService interface {
+	IsAdminUser(ctx context.Context, userID, email string) bool
+}) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("user_id")
+		email := c.GetString("user_email")
+		if userID == "" || !adminService.IsAdminUser(c.Request.Context(), userID, email) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"flashcard-backend/internal/infrastructure/server/middleware"
 	"flashcard-backend/internal/modules/admin"
 	"flashcard-backend/internal/modules/auth"
+	"flashcard-backend/internal/modules/backup"
+	"flashcard-backend/internal/modules/catalog"
 	"flashcard-backend/internal/modules/favorites"
 	"flashcard-backend/internal/modules/flashcards"
 	"flashcard-backend/internal/modules/gamification"
@@ -21,6 +23,8 @@ func SetupRoutes(
 	gamificationModule *gamification.Module,
 	favoriteModule *favorites.Module,
 	adminModule *admin.Module,
+	backupModule *backup.Module,
+	catalogModule *catalog.Module,
 	cfg *config.Config,
 ) {
 	// Health check
@@ -42,6 +46,9 @@ func SetupRoutes(
 		auth.POST("/google", authModule.Handler.GoogleAuthExpo)
 	}
 
+	// Prévia pública de decks compartilhados por link
+	router.GET("/share/:token", flashcardsModule.Handler.PreviewSharedDeck)
+
 	// Protected routes
 	protected := router.Group("/api")
 	protected.Use(middleware.AuthMiddleware(cfg))
@@ -51,6 +58,7 @@ func SetupRoutes(
 		{
 			user.PUT("/avatar", authModule.Handler.UpdateAvatar)
 			user.PUT("/profile", authModule.Handler.UpdateProfile)
+			user.PUT("/time-zone", authModule.Handler.UpdateTimeZone)
 		}
 
 		// Deck routes
@@ -64,27 +72,111 @@ func SetupRoutes(
 			decks.DELETE("/favorite/:deckId", favoriteModule.Handler.RemoveFavorite)
 			decks.GET("/favorites", favoriteModule.Handler.ListFavorites)
 
+			// Decks compartilhados com o usuário
+			decks.GET("/shared", flashcardsModule.Handler.GetSharedDecks)
+
 			// Rotas genéricas de deck
 			decks.GET(":id", flashcardsModule.Handler.GetDeck)
 			decks.PUT(":id", flashcardsModule.Handler.UpdateDeck)
 			decks.DELETE(":id", flashcardsModule.Handler.DeleteDeck)
+			decks.PUT(":id/move", flashcardsModule.Handler.MoveDeck)
+			decks.POST(":id/fork", flashcardsModule.Handler.ForkDeck)
+			decks.GET(":id/upstream", flashcardsModule.Handler.GetUpstreamDiff)
+			decks.GET(":id/changes", flashcardsModule.Handler.GetDeckChanges)
+			decks.POST(":id/upstream/sync", flashcardsModule.Handler.SyncUpstream)
+			decks.GET(":id/export/pdf", flashcardsModule.Handler.ExportDeckPDF)
+			decks.GET(":id/lint", flashcardsModule.Handler.LintDeck)
+			decks.POST(":id/quiz", flashcardsModule.Handler.StartQuiz)
+			decks.GET(":id/quiz/results", flashcardsModule.Handler.GetDeckQuizResults)
+			decks.GET(":id/members", flashcardsModule.Handler.GetDeckMembers)
+			decks.POST(":id/members", flashcardsModule.Handler.InviteDeckMember)
+			decks.PUT(":id/members/:memberId", flashcardsModule.Handler.UpdateDeckMember)
+			decks.DELETE(":id/members/:memberId", flashcardsModule.Handler.RemoveDeckMember)
+			decks.PUT(":id/rating", flashcardsModule.Handler.RateDeck)
+			decks.DELETE(":id/rating", flashcardsModule.Handler.DeleteMyRating)
+			decks.GET(":id/ratings", flashcardsModule.Handler.GetDeckRatings)
+			decks.POST(":id/ratings/:ratingId/reply", flashcardsModule.Handler.ReplyToRating)
+			decks.DELETE(":id/ratings/:ratingId/reply", flashcardsModule.Handler.DeleteRatingReply)
+			decks.GET(":id/share-links", flashcardsModule.Handler.GetShareLinks)
+			decks.POST(":id/share-links", flashcardsModule.Handler.CreateShareLink)
+			decks.DELETE(":id/share-links/:linkId", flashcardsModule.Handler.RevokeShareLink)
 		}
 
+		// Deck invitation routes
+		invitations := protected.Group("/invitations")
+		{
+			invitations.GET("", flashcardsModule.Handler.GetInvitations)
+			invitations.POST("/:id/accept", flashcardsModule.Handler.AcceptInvitation)
+			invitations.POST("/:id/decline", flashcardsModule.Handler.DeclineInvitation)
+		}
+
+		// Importação de decks compartilhados por link
+		protected.POST("/share/:token/import", flashcardsModule.Handler.ImportSharedDeck)
+
+		// Sincronização dos clientes offline
+		protected.POST("/sync", flashcardsModule.Handler.Sync)
+
+		// Busca nos cards do usuário
+		protected.GET("/search", flashcardsModule.Handler.SearchCards)
+
+		// Catálogo de decks públicos
+		protected.GET("/catalog", catalogModule.Handler.Browse)
+
 		// Flashcard routes
 		cards := protected.Group("/cards")
 		{
 			cards.GET("/deck/:deckId", flashcardsModule.Handler.GetFlashcards)
 			cards.POST("", flashcardsModule.Handler.CreateFlashcard)
+			cards.POST("/bulk", flashcardsModule.Handler.BulkUpdateCards)
+			cards.GET("/duplicates", flashcardsModule.Handler.FindDuplicates)
+			cards.POST("/duplicates/merge", flashcardsModule.Handler.MergeDuplicates)
 			cards.PUT("/:id", flashcardsModule.Handler.UpdateFlashcard)
 			cards.DELETE("/:id", flashcardsModule.Handler.DeleteFlashcard)
+			cards.GET("/:id/revisions", flashcardsModule.Handler.GetCardRevisions)
+			cards.POST("/:id/revisions/:revisionId/restore", flashcardsModule.Handler.RestoreCardRevision)
+		}
+
+		// Quiz routes
+		quiz := protected.Group("/quiz")
+		{
+			quiz.GET("", flashcardsModule.Handler.GetQuizAttempts)
+			quiz.GET("/:id", flashcardsModule.Handler.GetQuiz)
+			quiz.POST("/:id/answer", flashcardsModule.Handler.AnswerQuiz)
+			quiz.POST("/:id/finish", flashcardsModule.Handler.FinishQuiz)
+		}
+
+		// Trash routes
+		trash := protected.Group("/trash")
+		{
+			trash.GET("", flashcardsModule.Handler.GetTrash)
+			trash.POST("/:id/restore", flashcardsModule.Handler.RestoreTrashItem)
+			trash.DELETE("/:id", flashcardsModule.Handler.PurgeTrashItem)
+		}
+
+		// Import routes
+		imports := protected.Group("/import")
+		{
+			imports.POST("/kindle", flashcardsModule.Handler.ImportKindle)
+		}
+
+		// Backup routes
+		backups := protected.Group("/backup")
+		{
+			backups.GET("", backupModule.Handler.Export)
+			backups.POST("/restore", backupModule.Handler.Restore)
 		}
 
 		// Study session routes
 		study := protected.Group("/study")
 		{
 			study.POST("/start", flashcardsModule.Handler.StartStudySession)
+			study.POST("/filtered", flashcardsModule.Handler.StartFilteredSession)
+			study.DELETE("/:id", flashcardsModule.Handler.DeleteStudySession)
+			study.GET("/:id/next", flashcardsModule.Handler.NextSessionCard)
+			study.POST("/:id/answer", flashcardsModule.Handler.AnswerSessionCard)
 			study.PUT("/:id/end", flashcardsModule.Handler.EndStudySession)
 			study.POST("/review", flashcardsModule.Handler.ReviewCard)
+			study.GET("/due", flashcardsModule.Handler.GetDueCards)
 			study.GET("/history", flashcardsModule.Handler.GetStudyHistory)
 		}
 
@@ -110,6 +202,16 @@ func SetupRoutes(
 			stats.GET("/export", gamificationModule.StatsHandler.ExportStats)
 		}
 
+		// Daily goal routes (os dias seguem o fuso salvo em PUT /api/user/time-zone)
+		goals := protected.Group("/goals")
+		{
+			goals.GET("", gamificationModule.GoalHandler.GetDailyGoal)
+			goals.PUT("", gamificationModule.GoalHandler.SetDailyGoal)
+			goals.DELETE("", gamificationModule.GoalHandler.DisableDailyGoal)
+			goals.GET("/today", gamificationModule.GoalHandler.GetTodayGoal)
+			goals.GET("/history", gamificationModule.GoalHandler.GetGoalHistory)
+		}
+
 		// Gamification routes
 		gamification := protected.Group("/gamification")
 		{
@@ -132,5 +234,20 @@ func SetupRoutes(
 			admin.PUT("/users/:id", adminModule.Handler.UpdateAdminUser)
 			admin.DELETE("/users/:id", adminModule.Handler.DeleteAdminUser)
 		}
+
+		// Denúncias de conteúdo público
+		protected.POST("/reports", adminModule.ModerationHandler.ReportContent)
+
+		// Moderação (somente admins)
+		moderation := protected.Group("/admin")
+		moderation.Use(middleware.RequireAdmin(adminModule.Service))
+		{
+			moderation.GET("/reports", adminModule.ModerationHandler.GetReports)
+			moderation.POST("/reports/:id/dismiss", adminModule.ModerationHandler.DismissReport)
+			moderation.POST("/reports/:id/unpublish", adminModule.ModerationHandler.UnpublishReportedDeck)
+			moderation.POST("/reports/:id/suspend", adminModule.ModerationHandler.SuspendReportedAuthor)
+			moderation.POST("/moderation/users/:userId/unsuspend", adminModule.ModerationHandler.UnsuspendUser)
+			moderation.GET("/moderation/log", adminModule.ModerationHandler.GetModerationLog)
+		}
 	}
 }
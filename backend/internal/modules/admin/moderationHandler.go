@@ -0,0 +1,153 @@
+package admin
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ModerationHandler struct {
+	service *ModerationService
+}
+
+func NewModerationHandler(service *ModerationService) *ModerationHandler {
+	return &ModerationHandler{service: service}
+}
+
+// POST /api/reports
+func (h *ModerationHandler) ReportContent(c *gin.Context) {
+	var req struct {
+		TargetType string `json:"target_type" binding:"required"`
+		TargetID   string `json:"target_id" binding:"required"`
+		Reason     string `json:"reason" binding:"required"`
+		Details    string `json:"details"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.service.ReportContent(c.Request.Context(), c.GetString("user_id"), req.TargetType, req.TargetID, req.Reason, req.Details)
+	if err != nil {
+		respondModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, report)
+}
+
+// GET /api/admin/reports?status=open&skip=...&limit=...
+func (h *ModerationHandler) GetReports(c *gin.Context) {
+	skip, limit := pageParams(c)
+	reports, err := h.service.GetReports(c.Request.Context(), c.Query("status"), skip, limit)
+	if err != nil {
+		respondModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reports)
+}
+
+// POST /api/admin/reports/:id/dismiss
+func (h *ModerationHandler) DismissReport(c *gin.Context) {
+	note, ok := moderationNote(c)
+	if !ok {
+		return
+	}
+	action, err := h.service.DismissReport(c.Request.Context(), c.GetString("user_id"), c.Param("id"), note)
+	if err != nil {
+		respondModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, action)
+}
+
+// POST /api/admin/reports/:id/unpublish
+func (h *ModerationHandler) UnpublishReportedDeck(c *gin.Context) {
+	note, ok := moderationNote(c)
+	if !ok {
+		return
+	}
+	action, err := h.service.UnpublishReportedDeck(c.Request.Context(), c.GetString("user_id"), c.Param("id"), note)
+	if err != nil {
+		respondModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, action)
+}
+
+// POST /api/admin/reports/:id/suspend
+func (h *ModerationHandler) SuspendReportedAuthor(c *gin.Context) {
+	note, ok := moderationNote(c)
+	if !ok {
+		return
+	}
+	action, err := h.service.SuspendReportedAuthor(c.Request.Context(), c.GetString("user_id"), c.Param("id"), note)
+	if err != nil {
+		respondModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, action)
+}
+
+// POST /api/admin/moderation/users/:userId/unsuspend
+func (h *ModerationHandler) UnsuspendUser(c *gin.Context) {
+	note, ok := moderationNote(c)
+	if !ok {
+		return
+	}
+	action, err := h.service.UnsuspendUser(c.Request.Context(), c.GetString("user_id"), c.Param("userId"), note)
+	if err != nil {
+		respondModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, action)
+}
+
+// GET /api/admin/moderation/log?author_id=...&deck_id=...&skip=...&limit=...
+func (h *ModerationHandler) GetModerationLog(c *gin.Context) {
+	skip, limit := pageParams(c)
+	actions, err := h.service.GetModerationLog(c.Request.Context(), c.Query("author_id"), c.Query("deck_id"), skip, limit)
+	if err != nil {
+		respondModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, actions)
+}
+
+// moderationNote lê a observação opcional do admin
+func moderationNote(c *gin.Context) (string, bool) {
+	var req struct {
+		Note string `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return "", false
+	}
+	return req.Note, true
+}
+
+func pageParams(c *gin.Context) (int64, int64) {
+	skip, _ := strconv.ParseInt(c.DefaultQuery("skip", "0"), 10, 64)
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "0"), 10, 64)
+	return skip, limit
+}
+
+func respondModerationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrReportNotFound), errors.Is(err, ErrContentNotFound), errors.Is(err, ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrReportClosed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
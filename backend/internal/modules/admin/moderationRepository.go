@@ -0,0 +1,189 @@
+package admin
+
+import (
+	"context"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ModerationRepository guarda denúncias e a trilha de auditoria e aplica as
+// ações de moderação sobre decks e usuários
+type ModerationRepository struct {
+	reportCollection *mongo.Collection
+	actionCollection *mongo.Collection
+	deckCollection   *mongo.Collection
+	cardCollection   *mongo.Collection
+	userCollection   *mongo.Collection
+}
+
+func NewModerationRepository(db *mongo.Database) *ModerationRepository {
+	return &ModerationRepository{
+		reportCollection: db.Collection("reports"),
+		actionCollection: db.Collection("moderation_actions"),
+		deckCollection:   db.Collection("decks"),
+		cardCollection:   db.Collection("cards"),
+		userCollection:   db.Collection("users"),
+	}
+}
+
+// Report methods
+func (r *ModerationRepository) CreateReport(ctx context.Context, report *entities.ContentReport) error {
+	report.ID = primitive.NewObjectID()
+	_, err := r.reportCollection.InsertOne(ctx, report)
+	return err
+}
+
+func (r *ModerationRepository) GetReport(ctx context.Context, reportID primitive.ObjectID) (*entities.ContentReport, error) {
+	var report entities.ContentReport
+	err := r.reportCollection.FindOne(ctx, bson.M{"_id": reportID}).Decode(&report)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// HasOpenReport indica se o usuário já tem uma denúncia aberta sobre o conteúdo
+func (r *ModerationRepository) HasOpenReport(ctx context.Context, reporterID, targetType, targetID string) (bool, error) {
+	count, err := r.reportCollection.CountDocuments(ctx, bson.M{
+		"reporter_id": reporterID,
+		"target_type": targetType,
+		"target_id":   targetID,
+		"status":      entities.ReportOpen,
+	})
+	return count > 0, err
+}
+
+// GetReports lista as denúncias com a situação informada. A fila aberta vem
+// das mais antigas para as mais recentes; as encerradas, ao contrário.
+func (r *ModerationRepository) GetReports(ctx context.Context, status string, skip, limit int64) ([]entities.ContentReport, error) {
+	order := -1
+	if status == entities.ReportOpen {
+		order = 1
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: order}}).SetSkip(skip).SetLimit(limit)
+	cursor, err := r.reportCollection.Find(ctx, bson.M{"status": status}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	reports := []entities.ContentReport{}
+	if err = cursor.All(ctx, &reports); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// ResolveReports encerra as denúncias abertas que casam com o filtro
+func (r *ModerationRepository) ResolveReports(ctx context.Context, filter bson.M, status, resolution, adminID string, now time.Time) (int, error) {
+	filter["status"] = entities.ReportOpen
+	result, err := r.reportCollection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{
+		"status":      status,
+		"resolution":  resolution,
+		"resolved_by": adminID,
+		"resolved_at": now,
+	}})
+	if err != nil {
+		return 0, err
+	}
+	return int(result.ModifiedCount), nil
+}
+
+// Audit trail methods
+func (r *ModerationRepository) LogAction(ctx context.Context, action *entities.ModerationAction) error {
+	action.ID = primitive.NewObjectID()
+	_, err := r.actionCollection.InsertOne(ctx, action)
+	return err
+}
+
+// GetActions lista a trilha de auditoria, da ação mais recente para a mais antiga
+func (r *ModerationRepository) GetActions(ctx context.Context, filter bson.M, skip, limit int64) ([]entities.ModerationAction, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetSkip(skip).SetLimit(limit)
+	cursor, err := r.actionCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	actions := []entities.ModerationAction{}
+	if err = cursor.All(ctx, &actions); err != nil {
+		return nil, err
+	}
+	return actions, nil
+}
+
+// Content methods
+func (r *ModerationRepository) GetDeck(ctx context.Context, deckID primitive.ObjectID) (*entities.Deck, error) {
+	var deck entities.Deck
+	err := r.deckCollection.FindOne(ctx, bson.M{"_id": deckID}).Decode(&deck)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &deck, nil
+}
+
+func (r *ModerationRepository) GetCard(ctx context.Context, cardID primitive.ObjectID) (*entities.Flashcard, error) {
+	var card entities.Flashcard
+	err := r.cardCollection.FindOne(ctx, bson.M{"_id": cardID}).Decode(&card)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+// UnpublishDecks torna privados os decks públicos que casam com o filtro.
+// Com lock, os decks ficam travados e o dono não consegue republicá-los.
+func (r *ModerationRepository) UnpublishDecks(ctx context.Context, filter bson.M, lock bool, now time.Time) (int, error) {
+	filter["isPublic"] = true
+	set := bson.M{"isPublic": false, "updatedAt": now}
+	if lock {
+		set["moderationLockedAt"] = now
+	}
+	result, err := r.deckCollection.UpdateMany(ctx, filter, bson.M{"$set": set})
+	if err != nil {
+		return 0, err
+	}
+	return int(result.ModifiedCount), nil
+}
+
+// GetUserEmail retorna o email do usuário, ou "" se ele não existir
+func (r *ModerationRepository) GetUserEmail(ctx context.Context, userID primitive.ObjectID) (string, error) {
+	var user entities.User
+	opts := options.FindOne().SetProjection(bson.M{"email": 1})
+	err := r.userCollection.FindOne(ctx, bson.M{"_id": userID}, opts).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return user.Email, nil
+}
+
+// SetUserSuspension suspende o usuário; at nil remove a suspensão
+func (r *ModerationRepository) SetUserSuspension(ctx context.Context, userID primitive.ObjectID, at *time.Time, reason string) (bool, error) {
+	update := bson.M{"$unset": bson.M{"suspended_at": "", "suspension_reason": ""}}
+	if at != nil {
+		update = bson.M{"$set": bson.M{"suspended_at": *at, "suspension_reason": reason}}
+	}
+	result, err := r.userCollection.UpdateOne(ctx, bson.M{"_id": userID}, update)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
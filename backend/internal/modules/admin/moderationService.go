@@ -0,0 +1,327 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrReportNotFound  = errors.New("report not found")
+	ErrReportClosed    = errors.New("report has already been resolved")
+	ErrContentNotFound = errors.New("content not found")
+	ErrUserNotFound    = errors.New("user not found")
+)
+
+// Motivos aceitos em uma denúncia
+var reportReasons = map[string]bool{
+	"spam":           true,
+	"offensive":      true,
+	"copyright":      true,
+	"misinformation": true,
+	"other":          true,
+}
+
+const (
+	maxReportDetails = 1000
+	defaultQueueSize = 50
+	maxQueueSize     = 200
+)
+
+type ModerationService struct {
+	repo *ModerationRepository
+}
+
+func NewModerationService(repo *ModerationRepository) *ModerationService {
+	return &ModerationService{repo: repo}
+}
+
+// ReportContent registra a denúncia de um deck público ou de um card de um
+// deck público
+func (s *ModerationService) ReportContent(ctx context.Context, reporterID, targetType, targetID, reason, details string) (*entities.ContentReport, error) {
+	reason = strings.ToLower(strings.TrimSpace(reason))
+	if !reportReasons[reason] {
+		return nil, fmt.Errorf("invalid reason: %s (use spam, offensive, copyright, misinformation or other)", reason)
+	}
+	details = strings.TrimSpace(details)
+	if reason == "other" && details == "" {
+		return nil, fmt.Errorf("details are required when the reason is other")
+	}
+	if len([]rune(details)) > maxReportDetails {
+		return nil, fmt.Errorf("details must have at most %d characters", maxReportDetails)
+	}
+
+	report := &entities.ContentReport{
+		TargetType: targetType,
+		TargetID:   targetID,
+		ReporterID: reporterID,
+		Reason:     reason,
+		Details:    details,
+		Status:     entities.ReportOpen,
+		CreatedAt:  time.Now(),
+	}
+	if err := s.resolveTarget(ctx, report); err != nil {
+		return nil, err
+	}
+	if report.AuthorID == reporterID {
+		return nil, fmt.Errorf("you cannot report your own content")
+	}
+
+	exists, err := s.repo.HasOpenReport(ctx, reporterID, report.TargetType, report.TargetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check reports: %w", err)
+	}
+	if exists {
+		return nil, fmt.Errorf("you have already reported this content")
+	}
+
+	if err := s.repo.CreateReport(ctx, report); err != nil {
+		return nil, fmt.Errorf("failed to create report: %w", err)
+	}
+	return report, nil
+}
+
+// resolveTarget preenche deck, autor e título da denúncia. Só conteúdo
+// público pode ser denunciado.
+func (s *ModerationService) resolveTarget(ctx context.Context, report *entities.ContentReport) error {
+	targetID, err := primitive.ObjectIDFromHex(report.TargetID)
+	if err != nil {
+		return ErrContentNotFound
+	}
+
+	deckID := targetID
+	switch report.TargetType {
+	case entities.ReportTargetDeck:
+	case entities.ReportTargetCard:
+		card, err := s.repo.GetCard(ctx, targetID)
+		if err != nil {
+			return fmt.Errorf("failed to get card: %w", err)
+		}
+		if card == nil {
+			return ErrContentNotFound
+		}
+		if deckID, err = primitive.ObjectIDFromHex(card.DeckID); err != nil {
+			return ErrContentNotFound
+		}
+		report.TargetTitle = card.Question
+	default:
+		return fmt.Errorf("invalid target type: %s (use deck or card)", report.TargetType)
+	}
+
+	deck, err := s.repo.GetDeck(ctx, deckID)
+	if err != nil {
+		return fmt.Errorf("failed to get deck: %w", err)
+	}
+	if deck == nil || !deck.IsPublic {
+		return ErrContentNotFound
+	}
+	report.DeckID = deck.ID.Hex()
+	report.AuthorID = deck.UserID
+	if report.TargetTitle == "" {
+		report.TargetTitle = deck.Name
+	}
+	return nil
+}
+
+// GetReports lista a fila de moderação; status vazio mostra as abertas
+func (s *ModerationService) GetReports(ctx context.Context, status string, skip, limit int64) ([]entities.ContentReport, error) {
+	if status == "" {
+		status = entities.ReportOpen
+	}
+	if status != entities.ReportOpen && status != entities.ReportDismissed && status != entities.ReportActioned {
+		return nil, fmt.Errorf("invalid status: %s (use open, dismissed or actioned)", status)
+	}
+	skip, limit = queuePage(skip, limit)
+	return s.repo.GetReports(ctx, status, skip, limit)
+}
+
+// DismissReport encerra a denúncia sem mexer no conteúdo
+func (s *ModerationService) DismissReport(ctx context.Context, adminID, reportID, note string) (*entities.ModerationAction, error) {
+	report, err := s.getOpenReport(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved, err := s.repo.ResolveReports(ctx, bson.M{"_id": report.ID}, entities.ReportDismissed, entities.ModerationDismiss, adminID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to dismiss report: %w", err)
+	}
+	return s.logAction(ctx, &entities.ModerationAction{
+		Action:          entities.ModerationDismiss,
+		AdminID:         adminID,
+		ReportID:        report.ID.Hex(),
+		DeckID:          report.DeckID,
+		AuthorID:        report.AuthorID,
+		Note:            note,
+		ResolvedReports: resolved,
+	})
+}
+
+// UnpublishReportedDeck tira do catálogo o deck denunciado (ou o deck do card
+// denunciado) e encerra todas as denúncias abertas sobre ele
+func (s *ModerationService) UnpublishReportedDeck(ctx context.Context, adminID, reportID, note string) (*entities.ModerationAction, error) {
+	report, err := s.getOpenReport(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+	deckID, err := primitive.ObjectIDFromHex(report.DeckID)
+	if err != nil {
+		return nil, ErrContentNotFound
+	}
+
+	now := time.Now()
+	unpublished, err := s.repo.UnpublishDecks(ctx, bson.M{"_id": deckID}, true, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpublish deck: %w", err)
+	}
+	resolved, err := s.repo.ResolveReports(ctx, bson.M{"deck_id": report.DeckID}, entities.ReportActioned, entities.ModerationUnpublish, adminID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve reports: %w", err)
+	}
+	return s.logAction(ctx, &entities.ModerationAction{
+		Action:           entities.ModerationUnpublish,
+		AdminID:          adminID,
+		ReportID:         report.ID.Hex(),
+		DeckID:           report.DeckID,
+		AuthorID:         report.AuthorID,
+		Note:             note,
+		ResolvedReports:  resolved,
+		UnpublishedDecks: unpublished,
+	})
+}
+
+// SuspendReportedAuthor suspende o autor do conteúdo denunciado: todos os
+// decks públicos dele saem do catálogo, ele não pode publicar outros e as
+// denúncias abertas contra ele são encerradas
+func (s *ModerationService) SuspendReportedAuthor(ctx context.Context, adminID, reportID, note string) (*entities.ModerationAction, error) {
+	report, err := s.getOpenReport(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+	authorID, err := primitive.ObjectIDFromHex(report.AuthorID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	now := time.Now()
+	found, err := s.repo.SetUserSuspension(ctx, authorID, &now, note)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suspend user: %w", err)
+	}
+	if !found {
+		return nil, ErrUserNotFound
+	}
+	// Decks antigos guardam o email do dono em userId
+	owners := bson.A{report.AuthorID}
+	email, err := s.repo.GetUserEmail(ctx, authorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if email != "" {
+		owners = append(owners, email)
+	}
+	unpublished, err := s.repo.UnpublishDecks(ctx, bson.M{"userId": bson.M{"$in": owners}}, false, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpublish decks: %w", err)
+	}
+	resolved, err := s.repo.ResolveReports(ctx, bson.M{"author_id": report.AuthorID}, entities.ReportActioned, entities.ModerationSuspend, adminID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve reports: %w", err)
+	}
+	return s.logAction(ctx, &entities.ModerationAction{
+		Action:           entities.ModerationSuspend,
+		AdminID:          adminID,
+		ReportID:         report.ID.Hex(),
+		DeckID:           report.DeckID,
+		AuthorID:         report.AuthorID,
+		Note:             note,
+		ResolvedReports:  resolved,
+		UnpublishedDecks: unpublished,
+	})
+}
+
+// UnsuspendUser remove a suspensão. Os decks despublicados continuam
+// privados até o autor publicá-los de novo.
+func (s *ModerationService) UnsuspendUser(ctx context.Context, adminID, userID, note string) (*entities.ModerationAction, error) {
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	found, err := s.repo.SetUserSuspension(ctx, userObjectID, nil, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to unsuspend user: %w", err)
+	}
+	if !found {
+		return nil, ErrUserNotFound
+	}
+	return s.logAction(ctx, &entities.ModerationAction{
+		Action:   entities.ModerationUnsuspend,
+		AdminID:  adminID,
+		AuthorID: userID,
+		Note:     note,
+	})
+}
+
+// GetModerationLog lista a trilha de auditoria, opcionalmente de um autor ou
+// de um deck
+func (s *ModerationService) GetModerationLog(ctx context.Context, authorID, deckID string, skip, limit int64) ([]entities.ModerationAction, error) {
+	filter := bson.M{}
+	if authorID != "" {
+		filter["author_id"] = authorID
+	}
+	if deckID != "" {
+		filter["deck_id"] = deckID
+	}
+	skip, limit = queuePage(skip, limit)
+	return s.repo.GetActions(ctx, filter, skip, limit)
+}
+
+func (s *ModerationService) getOpenReport(ctx context.Context, reportID string) (*entities.ContentReport, error) {
+	reportObjectID, err := primitive.ObjectIDFromHex(reportID)
+	if err != nil {
+		return nil, ErrReportNotFound
+	}
+	report, err := s.repo.GetReport(ctx, reportObjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get report: %w", err)
+	}
+	if report == nil {
+		return nil, ErrReportNotFound
+	}
+	if report.Status != entities.ReportOpen {
+		return nil, ErrReportClosed
+	}
+	return report, nil
+}
+
+// logAction grava a ação na trilha de auditoria. A ação já foi aplicada,
+// então uma falha aqui é devolvida para que o admin saiba que o registro
+// não foi feito.
+func (s *ModerationService) logAction(ctx context.Context, action *entities.ModerationAction) (*entities.ModerationAction, error) {
+	action.Note = strings.TrimSpace(action.Note)
+	action.CreatedAt = time.Now()
+	if err := s.repo.LogAction(ctx, action); err != nil {
+		return nil, fmt.Errorf("action applied but failed to record audit trail: %w", err)
+	}
+	return action, nil
+}
+
+func queuePage(skip, limit int64) (int64, int64) {
+	if limit <= 0 {
+		limit = defaultQueueSize
+	}
+	if limit > maxQueueSize {
+		limit = maxQueueSize
+	}
+	if skip < 0 {
+		skip = 0
+	}
+	return skip, limit
+}
@@ -0,0 +1,223 @@
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func toBSONDocument(t *mtest.T, value interface{}) bson.D {
+	raw, err := bson.Marshal(value)
+	require.NoError(t, err)
+	var doc bson.D
+	require.NoError(t, bson.Unmarshal(raw, &doc))
+	return doc
+}
+
+// updateOf devolve o filtro e o $set do próximo comando de update
+func updateOf(mt *mtest.T) (bson.Raw, bson.Raw) {
+	started := mt.GetStartedEvent()
+	require.NotNil(mt, started)
+	require.Equal(mt, "update", started.CommandName)
+	update := started.Command.Lookup("updates").Array().Index(0).Value().Document()
+	return update.Lookup("q").Document(), update.Lookup("u", "$set").Document()
+}
+
+func updated() bson.D {
+	return mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1})
+}
+
+func TestReportContentValidation(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	publicDeck := entities.Deck{ID: primitive.NewObjectID(), UserID: "author", Name: "Inglês", IsPublic: true}
+	privateDeck := entities.Deck{ID: primitive.NewObjectID(), UserID: "author", Name: "Rascunho"}
+	privateCard := entities.Flashcard{ID: primitive.NewObjectID(), DeckID: privateDeck.ID.Hex(), Question: "Q"}
+
+	cases := []struct {
+		name       string
+		reporter   string
+		targetType string
+		targetID   string
+		responses  []bson.D
+		message    string
+	}{
+		{"own content", "author", entities.ReportTargetDeck, publicDeck.ID.Hex(),
+			[]bson.D{mtest.CreateCursorResponse(0, "db.decks", mtest.FirstBatch, toBSONDocument(mt, publicDeck))},
+			"you cannot report your own content"},
+		{"private deck", "reporter", entities.ReportTargetDeck, privateDeck.ID.Hex(),
+			[]bson.D{mtest.CreateCursorResponse(0, "db.decks", mtest.FirstBatch, toBSONDocument(mt, privateDeck))},
+			ErrContentNotFound.Error()},
+		{"card of a private deck", "reporter", entities.ReportTargetCard, privateCard.ID.Hex(),
+			[]bson.D{
+				mtest.CreateCursorResponse(0, "db.cards", mtest.FirstBatch, toBSONDocument(mt, privateCard)),
+				mtest.CreateCursorResponse(0, "db.decks", mtest.FirstBatch, toBSONDocument(mt, privateDeck)),
+			},
+			ErrContentNotFound.Error()},
+		{"duplicate open report", "reporter", entities.ReportTargetDeck, publicDeck.ID.Hex(),
+			[]bson.D{
+				mtest.CreateCursorResponse(0, "db.decks", mtest.FirstBatch, toBSONDocument(mt, publicDeck)),
+				mtest.CreateCursorResponse(0, "db.reports", mtest.FirstBatch, bson.D{{Key: "n", Value: 1}}),
+			},
+			"you have already reported this content"},
+	}
+	for _, tc := range cases {
+		mt.Run(tc.name, func(mt *mtest.T) {
+			service := NewModerationService(NewModerationRepository(mt.DB))
+			mt.AddMockResponses(tc.responses...)
+
+			_, err := service.ReportContent(context.Background(), tc.reporter, tc.targetType, tc.targetID, "spam", "")
+			assert.EqualError(mt, err, tc.message)
+
+			// Nada é gravado
+			for started := mt.GetStartedEvent(); started != nil; started = mt.GetStartedEvent() {
+				assert.NotEqual(mt, "insert", started.CommandName)
+			}
+		})
+	}
+
+	mt.Run("invalid reason", func(mt *mtest.T) {
+		service := NewModerationService(NewModerationRepository(mt.DB))
+		_, err := service.ReportContent(context.Background(), "reporter", entities.ReportTargetDeck, publicDeck.ID.Hex(), "other", " ")
+		assert.EqualError(mt, err, "details are required when the reason is other")
+		assert.Nil(mt, mt.GetStartedEvent())
+	})
+}
+
+func TestReportContentResolvesCardTarget(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("card of a public deck", func(mt *mtest.T) {
+		service := NewModerationService(NewModerationRepository(mt.DB))
+
+		deck := entities.Deck{ID: primitive.NewObjectID(), UserID: "author", Name: "Inglês", IsPublic: true}
+		card := entities.Flashcard{ID: primitive.NewObjectID(), DeckID: deck.ID.Hex(), Question: "Pergunta ofensiva"}
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "db.cards", mtest.FirstBatch, toBSONDocument(mt, card)),
+			mtest.CreateCursorResponse(0, "db.decks", mtest.FirstBatch, toBSONDocument(mt, deck)),
+			mtest.CreateCursorResponse(0, "db.reports", mtest.FirstBatch),
+			mtest.CreateSuccessResponse(),
+		)
+
+		report, err := service.ReportContent(context.Background(), "reporter", entities.ReportTargetCard, card.ID.Hex(), " Offensive ", "")
+		require.NoError(mt, err)
+		assert.Equal(mt, "offensive", report.Reason)
+		assert.Equal(mt, deck.ID.Hex(), report.DeckID)
+		assert.Equal(mt, "author", report.AuthorID)
+		assert.Equal(mt, "Pergunta ofensiva", report.TargetTitle)
+		assert.Equal(mt, entities.ReportOpen, report.Status)
+	})
+}
+
+func TestModerationActions(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	authorID := primitive.NewObjectID()
+	report := entities.ContentReport{
+		ID:         primitive.NewObjectID(),
+		TargetType: entities.ReportTargetDeck,
+		DeckID:     primitive.NewObjectID().Hex(),
+		AuthorID:   authorID.Hex(),
+		Status:     entities.ReportOpen,
+	}
+
+	mt.Run("dismiss", func(mt *mtest.T) {
+		service := NewModerationService(NewModerationRepository(mt.DB))
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "db.reports", mtest.FirstBatch, toBSONDocument(mt, report)),
+			updated(),
+			mtest.CreateSuccessResponse(),
+		)
+
+		action, err := service.DismissReport(context.Background(), "admin", report.ID.Hex(), " sem problema ")
+		require.NoError(mt, err)
+		assert.Equal(mt, entities.ModerationDismiss, action.Action)
+		assert.Equal(mt, "sem problema", action.Note)
+		assert.Equal(mt, 1, action.ResolvedReports)
+
+		mt.GetStartedEvent() // denúncia
+		filter, set := updateOf(mt)
+		assert.Equal(mt, report.ID, filter.Lookup("_id").ObjectID())
+		assert.Equal(mt, entities.ReportDismissed, set.Lookup("status").StringValue())
+	})
+
+	mt.Run("unpublish", func(mt *mtest.T) {
+		service := NewModerationService(NewModerationRepository(mt.DB))
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "db.reports", mtest.FirstBatch, toBSONDocument(mt, report)),
+			updated(),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 2}, bson.E{Key: "nModified", Value: 2}),
+			mtest.CreateSuccessResponse(),
+		)
+
+		action, err := service.UnpublishReportedDeck(context.Background(), "admin", report.ID.Hex(), "")
+		require.NoError(mt, err)
+		assert.Equal(mt, 1, action.UnpublishedDecks)
+		assert.Equal(mt, 2, action.ResolvedReports)
+
+		mt.GetStartedEvent() // denúncia
+		filter, set := updateOf(mt)
+		assert.Equal(mt, report.DeckID, filter.Lookup("_id").ObjectID().Hex())
+		assert.False(mt, set.Lookup("isPublic").Boolean())
+		_, err = set.LookupErr("moderationLockedAt")
+		assert.NoError(mt, err, "o deck fica travado")
+
+		filter, _ = updateOf(mt)
+		assert.Equal(mt, report.DeckID, filter.Lookup("deck_id").StringValue())
+	})
+
+	mt.Run("suspend unpublishes decks stored by id or email", func(mt *mtest.T) {
+		service := NewModerationService(NewModerationRepository(mt.DB))
+		author := entities.User{ID: authorID, Email: "autor@example.com"}
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "db.reports", mtest.FirstBatch, toBSONDocument(mt, report)),
+			updated(),
+			mtest.CreateCursorResponse(0, "db.users", mtest.FirstBatch, toBSONDocument(mt, author)),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 3}, bson.E{Key: "nModified", Value: 3}),
+			updated(),
+			mtest.CreateSuccessResponse(),
+		)
+
+		action, err := service.SuspendReportedAuthor(context.Background(), "admin", report.ID.Hex(), "spam repetido")
+		require.NoError(mt, err)
+		assert.Equal(mt, entities.ModerationSuspend, action.Action)
+		assert.Equal(mt, 3, action.UnpublishedDecks)
+
+		mt.GetStartedEvent() // denúncia
+		filter, set := updateOf(mt)
+		assert.Equal(mt, authorID, filter.Lookup("_id").ObjectID())
+		assert.Equal(mt, "spam repetido", set.Lookup("suspension_reason").StringValue())
+
+		mt.GetStartedEvent() // email do autor
+		filter, set = updateOf(mt)
+		owners := filter.Lookup("userId", "$in").Array()
+		assert.Equal(mt, authorID.Hex(), owners.Index(0).Value().StringValue())
+		assert.Equal(mt, "autor@example.com", owners.Index(1).Value().StringValue())
+		assert.True(mt, filter.Lookup("isPublic").Boolean())
+		_, err = set.LookupErr("moderationLockedAt")
+		assert.Error(mt, err, "a suspensão não trava os decks")
+
+		filter, _ = updateOf(mt)
+		assert.Equal(mt, authorID.Hex(), filter.Lookup("author_id").StringValue())
+	})
+
+	mt.Run("closed report", func(mt *mtest.T) {
+		service := NewModerationService(NewModerationRepository(mt.DB))
+		closed := report
+		closed.Status = entities.ReportDismissed
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.reports", mtest.FirstBatch, toBSONDocument(mt, closed)))
+
+		_, err := service.SuspendReportedAuthor(context.Background(), "admin", closed.ID.Hex(), "")
+		assert.ErrorIs(mt, err, ErrReportClosed)
+	})
+}
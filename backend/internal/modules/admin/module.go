@@ -5,19 +5,24 @@ import (
 )
 
 type Module struct {
-	Repo    *AdminRepository
-	Service *Service
-	Handler *Handler
+	Repo              *AdminRepository
+	Service           *Service
+	Handler           *Handler
+	ModerationService *ModerationService
+	ModerationHandler *ModerationHandler
 }
 
 func NewModule(db *mongo.Database) *Module {
 	repo := NewAdminRepository(db)
 	service := NewService(repo)
 	handler := NewHandler(service)
+	moderationService := NewModerationService(NewModerationRepository(db))
 
 	return &Module{
-		Repo:    repo,
-		Service: service,
-		Handler: handler,
+		Repo:              repo,
+		Service:           service,
+		Handler:           handler,
+		ModerationService: moderationService,
+		ModerationHandler: NewModerationHandler(moderationService),
 	}
 }
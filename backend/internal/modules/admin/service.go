@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"flashcard-backend/internal/domain/entities"
 
@@ -138,3 +139,23 @@ func (s *Service) IsAdmin(ctx context.Context, userID primitive.ObjectID) (bool,
 	}
 	return adminUser.IsActive, nil
 }
+
+// IsAdminUser indica se o usuário autenticado é admin: um admin_user ativo ou
+// um email listado em AdminEmails
+func (s *Service) IsAdminUser(ctx context.Context, userID, email string) bool {
+	if objectID, err := primitive.ObjectIDFromHex(userID); err == nil {
+		if isAdmin, _ := s.IsAdmin(ctx, objectID); isAdmin {
+			return true
+		}
+	}
+	config, err := s.repo.GetConfig(ctx)
+	if err != nil || email == "" {
+		return false
+	}
+	for _, adminEmail := range config.AdminEmails {
+		if strings.EqualFold(adminEmail, email) {
+			return true
+		}
+	}
+	return false
+}
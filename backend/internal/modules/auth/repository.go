@@ -88,6 +88,20 @@ func (r *Repository) UpdateUserAvatar(userID primitive.ObjectID, avatar string)
 	return err
 }
 
+func (r *Repository) UpdateUserTimeZone(userID primitive.ObjectID, timeZone string) error {
+	collection := r.db.GetCollection("users")
+
+	_, err := collection.UpdateOne(
+		context.Background(),
+		bson.M{"_id": userID},
+		bson.M{"$set": bson.M{
+			"time_zone":  timeZone,
+			"updated_at": time.Now(),
+		}},
+	)
+	return err
+}
+
 func (r *Repository) GetUserByID(userID string) (*entities.User, error) {
 	collection := r.db.GetCollection("users")
 
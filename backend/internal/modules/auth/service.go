@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/smtp"
@@ -24,6 +25,9 @@ import (
 	"google.golang.org/api/option"
 )
 
+// ErrInvalidTimeZone indica um fuso que não é um nome IANA válido
+var ErrInvalidTimeZone = errors.New("invalid time zone")
+
 type Service struct {
 	repo *Repository
 	cfg  *config.Config
@@ -181,6 +185,21 @@ func (s *Service) UpdateAvatar(userID string, avatar string) error {
 	return s.repo.UpdateUserAvatar(objectID, avatar)
 }
 
+// UpdateTimeZone define o fuso IANA usado nos limites de dia do usuário
+// (estatísticas, streaks e metas). As estatísticas já gravadas mantêm o dia
+// em que foram contadas até o backfill ser rodado de novo.
+func (s *Service) UpdateTimeZone(userID, timeZone string) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+	if _, err := time.LoadLocation(timeZone); err != nil || timeZone == "" || timeZone == "Local" {
+		return fmt.Errorf("%w: %s", ErrInvalidTimeZone, timeZone)
+	}
+
+	return s.repo.UpdateUserTimeZone(objectID, timeZone)
+}
+
 func (s *Service) GetUserByID(userID string) (*entities.User, error) {
 	return s.repo.GetUserByID(userID)
 }
@@ -230,6 +249,31 @@ func sendResetEmail(to, code string) error {
 	return smtp.SendMail(addr, auth, from, []string{to}, msg)
 }
 
+// SendDeckInvitation avisa por email que um deck foi compartilhado
+func (s *Service) SendDeckInvitation(to, inviterName, deckName string) error {
+	host := os.Getenv("SMTP_HOST")
+	port := os.Getenv("SMTP_PORT")
+	user := os.Getenv("SMTP_USER")
+	pass := os.Getenv("SMTP_PASS")
+	from := os.Getenv("SMTP_FROM")
+	if host == "" || port == "" || user == "" || pass == "" || from == "" {
+		return fmt.Errorf("Configuração SMTP ausente")
+	}
+	portInt, err := strconv.Atoi(port)
+	if err != nil {
+		return fmt.Errorf("Porta SMTP inválida")
+	}
+	addr := fmt.Sprintf("%s:%d", host, portInt)
+	msg := []byte("To: " + to + "\r\n" +
+		"Subject: Convite para estudar um deck\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" +
+		fmt.Sprintf("%s convidou você para o deck \"%s\".\nEntre no app com este email para aceitar o convite.", inviterName, deckName) +
+		"\r\n")
+	auth := smtp.PlainAuth("", user, pass, host)
+	return smtp.SendMail(addr, auth, from, []string{to}, msg)
+}
+
 func (s *Service) CreatePasswordResetCode(email string) (string, error) {
 	user, err := s.repo.GetUserByEmail(email)
 	if err != nil || user == nil {
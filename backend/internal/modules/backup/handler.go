@@ -0,0 +1,90 @@
+package backup
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxArchiveSize = 500 * 1024 * 1024
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// GET /api/backup
+func (h *Handler) Export(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Gera o arquivo em memória para poder responder com erro caso algo falhe
+	var buf bytes.Buffer
+	if err := h.service.Export(c.Request.Context(), userID.(string), &buf); err != nil {
+		fmt.Printf("Error exporting backup for user %s: %v\n", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export backup"})
+		return
+	}
+
+	filename := fmt.Sprintf("flashcards-backup-%s.zip", time.Now().Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+// POST /api/backup/restore?conflict=skip|overwrite|duplicate
+func (h *Handler) Restore(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	fileHeader, err := c.FormFile("archive")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Backup archive is required"})
+		return
+	}
+	if fileHeader.Size > maxArchiveSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Backup archive too large"})
+		return
+	}
+	if !strings.HasSuffix(strings.ToLower(fileHeader.Filename), ".zip") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Backup archive must be a .zip file"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read backup archive"})
+		return
+	}
+	defer file.Close()
+
+	archive, err := zip.NewReader(file, fileHeader.Size)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid backup archive"})
+		return
+	}
+
+	result, err := h.service.Restore(c.Request.Context(), userID.(string), archive, c.DefaultQuery("conflict", ConflictSkip))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Backup restored",
+		"result":  result,
+	})
+}
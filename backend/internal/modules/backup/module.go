@@ -0,0 +1,22 @@
+package backup
+
+import (
+	"flashcard-backend/internal/infrastructure/database"
+)
+
+type Module struct {
+	Handler *Handler
+	Service *Service
+	Repo    *Repository
+}
+
+func NewModule(db *database.MongoDB, media MediaStorage, limits Limits) *Module {
+	repo := NewRepository(db)
+	service := NewService(repo, media, limits)
+	handler := NewHandler(service)
+	return &Module{
+		Handler: handler,
+		Service: service,
+		Repo:    repo,
+	}
+}
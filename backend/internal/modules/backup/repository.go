@@ -0,0 +1,114 @@
+package backup
+
+import (
+	"context"
+
+	"flashcard-backend/internal/domain/entities"
+	"flashcard-backend/internal/infrastructure/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type Repository struct {
+	db *database.MongoDB
+}
+
+func NewRepository(db *database.MongoDB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) GetDecks(ctx context.Context, userID string) ([]entities.Deck, error) {
+	var decks []entities.Deck
+	err := r.findAll(ctx, "decks", bson.M{"userId": userID}, &decks)
+	return decks, err
+}
+
+func (r *Repository) GetCards(ctx context.Context, deckIDs []string) ([]entities.Flashcard, error) {
+	var cards []entities.Flashcard
+	if len(deckIDs) == 0 {
+		return cards, nil
+	}
+	err := r.findAll(ctx, "cards", bson.M{"deckId": bson.M{"$in": deckIDs}}, &cards)
+	return cards, err
+}
+
+func (r *Repository) GetFavorites(ctx context.Context, userID primitive.ObjectID) ([]entities.Favorite, error) {
+	var favorites []entities.Favorite
+	err := r.findAll(ctx, "favorites", bson.M{"user_id": userID}, &favorites)
+	return favorites, err
+}
+
+func (r *Repository) GetAchievements(ctx context.Context, userID string) ([]entities.Achievement, error) {
+	var achievements []entities.Achievement
+	err := r.findAll(ctx, "achievements", bson.M{"user_id": userID}, &achievements)
+	return achievements, err
+}
+
+func (r *Repository) GetStudyStats(ctx context.Context, userID string) ([]entities.StudyStats, error) {
+	var stats []entities.StudyStats
+	opts := options.Find().SetSort(bson.M{"created_at": 1})
+	err := r.findAll(ctx, "study_stats", bson.M{"user_id": userID}, &stats, opts)
+	return stats, err
+}
+
+func (r *Repository) findAll(ctx context.Context, collection string, filter bson.M, result interface{}, opts ...*options.FindOptions) error {
+	cursor, err := r.db.GetCollection(collection).Find(ctx, filter, opts...)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+	return cursor.All(ctx, result)
+}
+
+// FindOwner retorna o dono de um documento pelo _id. found é falso quando o
+// documento não existe.
+func (r *Repository) FindOwner(ctx context.Context, collection, ownerField string, id primitive.ObjectID) (owner interface{}, found bool, err error) {
+	var doc bson.M
+	err = r.db.GetCollection(collection).FindOne(ctx, bson.M{"_id": id},
+		options.FindOne().SetProjection(bson.M{ownerField: 1})).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return doc[ownerField], true, nil
+}
+
+func (r *Repository) Insert(ctx context.Context, collection string, doc interface{}) error {
+	_, err := r.db.GetCollection(collection).InsertOne(ctx, doc)
+	return err
+}
+
+func (r *Repository) Replace(ctx context.Context, collection string, id primitive.ObjectID, doc interface{}) error {
+	_, err := r.db.GetCollection(collection).ReplaceOne(ctx, bson.M{"_id": id}, doc)
+	return err
+}
+
+func (r *Repository) CountCards(ctx context.Context, deckID string) (int64, error) {
+	return r.db.GetCollection("cards").CountDocuments(ctx, bson.M{"deckId": deckID})
+}
+
+// RefreshCardCount grava no deck a quantidade de cards que ele tem
+func (r *Repository) RefreshCardCount(ctx context.Context, deckID primitive.ObjectID) error {
+	count, err := r.CountCards(ctx, deckID.Hex())
+	if err != nil {
+		return err
+	}
+	_, err = r.db.GetCollection("decks").UpdateOne(ctx, bson.M{"_id": deckID}, bson.M{"$set": bson.M{"cardCount": count}})
+	return err
+}
+
+// IsUserSuspended indica se a moderação suspendeu o usuário de publicar
+func (r *Repository) IsUserSuspended(ctx context.Context, userID primitive.ObjectID) (bool, error) {
+	count, err := r.db.GetCollection("users").CountDocuments(ctx, bson.M{"_id": userID, "suspended_at": bson.M{"$ne": nil}})
+	return count > 0, err
+}
+
+func (r *Repository) FavoriteExists(ctx context.Context, userID, deckID primitive.ObjectID) (bool, error) {
+	count, err := r.db.GetCollection("favorites").CountDocuments(ctx, bson.M{"user_id": userID, "deck_id": deckID})
+	return count > 0, err
+}
@@ -0,0 +1,657 @@
+package backup
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+	"flashcard-backend/internal/infrastructure/mediaclient"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Estratégias de conflito na restauração
+const (
+	ConflictSkip      = "skip"
+	ConflictOverwrite = "overwrite"
+	ConflictDuplicate = "duplicate"
+)
+
+const (
+	archiveVersion = 1
+	maxJSONSize    = 100 * 1024 * 1024
+	maxMediaSize   = 50 * 1024 * 1024
+)
+
+var errMediaTooLarge = errors.New("media file is too large")
+
+// Manifest descreve o conteúdo de um arquivo de backup
+type Manifest struct {
+	Version      int            `json:"version"`
+	UserID       string         `json:"user_id"`
+	ExportedAt   time.Time      `json:"exported_at"`
+	Counts       map[string]int `json:"counts"`
+	MissingMedia []string       `json:"missing_media,omitempty"`
+	// SkippedMedia são mídias que não entram no backup de propósito: fora do
+	// host de envio ou acima do tamanho máximo
+	SkippedMedia []string `json:"skipped_media,omitempty"`
+}
+
+// MediaEntry liga a URL original de uma mídia ao arquivo dentro do backup
+type MediaEntry struct {
+	URL         string `json:"url"`
+	Path        string `json:"path"`
+	ContentType string `json:"content_type,omitempty"`
+}
+
+// RestoreCounts resume a restauração de uma coleção
+type RestoreCounts struct {
+	Inserted    int `json:"inserted"`
+	Overwritten int `json:"overwritten"`
+	Skipped     int `json:"skipped"`
+}
+
+// RestoreResult resume uma restauração completa
+type RestoreResult struct {
+	Decks         RestoreCounts `json:"decks"`
+	Cards         RestoreCounts `json:"cards"`
+	Favorites     RestoreCounts `json:"favorites"`
+	MediaUploaded int           `json:"media_uploaded"`
+	Errors        []string      `json:"errors,omitempty"`
+}
+
+type Service struct {
+	repo       *Repository
+	httpClient *http.Client
+	media      MediaStorage
+	limits     Limits
+}
+
+// MediaStorage é onde as mídias restauradas são reenviadas. Só de
+// MediaHosts o backup baixa mídias.
+type MediaStorage interface {
+	IsConfigured() bool
+	MediaHosts() []string
+	UploadBytes(data []byte, folder, ext, contentType string) (string, error)
+}
+
+// Limits são os limites do plano, os mesmos verificados ao criar decks e
+// cards pela API
+type Limits interface {
+	ValidateDeckCapacity(userID string, isPublic bool, n int) error
+	ValidateCardCapacity(userID string, currentCount, adding int) error
+}
+
+func NewService(repo *Repository, media MediaStorage, limits Limits) *Service {
+	var hosts []string
+	if media != nil {
+		hosts = media.MediaHosts()
+	}
+	return &Service{
+		repo:       repo,
+		httpClient: mediaclient.NewClient(hosts, 30*time.Second),
+		media:      media,
+		limits:     limits,
+	}
+}
+
+// Export escreve em w um zip com tudo o que pertence ao usuário
+func (s *Service) Export(ctx context.Context, userID string, w io.Writer) error {
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	decks, err := s.repo.GetDecks(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get decks: %w", err)
+	}
+	deckIDs := make([]string, len(decks))
+	for i, deck := range decks {
+		deckIDs[i] = deck.ID.Hex()
+	}
+	cards, err := s.repo.GetCards(ctx, deckIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get cards: %w", err)
+	}
+	favorites, err := s.repo.GetFavorites(ctx, userObjectID)
+	if err != nil {
+		return fmt.Errorf("failed to get favorites: %w", err)
+	}
+	achievements, err := s.repo.GetAchievements(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get achievements: %w", err)
+	}
+	stats, err := s.repo.GetStudyStats(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get study stats: %w", err)
+	}
+
+	archive := zip.NewWriter(w)
+
+	media, missing, skipped := s.writeMedia(ctx, archive, cards)
+
+	manifest := Manifest{
+		Version:    archiveVersion,
+		UserID:     userID,
+		ExportedAt: time.Now(),
+		Counts: map[string]int{
+			"decks":        len(decks),
+			"cards":        len(cards),
+			"favorites":    len(favorites),
+			"achievements": len(achievements),
+			"study_stats":  len(stats),
+			"media":        len(media),
+		},
+		MissingMedia: missing,
+		SkippedMedia: skipped,
+	}
+
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"manifest.json", manifest},
+		{"decks.json", decks},
+		{"cards.json", cards},
+		{"favorites.json", favorites},
+		{"achievements.json", achievements},
+		{"study_stats.json", stats},
+		{"media.json", media},
+	}
+	for _, file := range files {
+		entry, err := archive.Create(file.name)
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(entry)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+	}
+
+	return archive.Close()
+}
+
+// writeMedia baixa as imagens e áudios dos cards para dentro do zip. Mídias
+// que falharam vão para missing; as recusadas (host não permitido ou grandes
+// demais) vão para skipped.
+func (s *Service) writeMedia(ctx context.Context, archive *zip.Writer, cards []entities.Flashcard) ([]MediaEntry, []string, []string) {
+	media := []MediaEntry{}
+	var missing, skipped []string
+	seen := make(map[string]bool)
+
+	for _, card := range cards {
+		for _, url := range []*string{card.ImageURL, card.AudioURL} {
+			if url == nil || *url == "" || seen[*url] {
+				continue
+			}
+			seen[*url] = true
+
+			entry, err := s.downloadMedia(ctx, archive, *url)
+			if errors.Is(err, mediaclient.ErrHostNotAllowed) || errors.Is(err, mediaclient.ErrPrivateAddress) || errors.Is(err, errMediaTooLarge) {
+				fmt.Printf("Skipping media %s: %v\n", *url, err)
+				skipped = append(skipped, *url)
+				continue
+			}
+			if err != nil {
+				fmt.Printf("Failed to back up media %s: %v\n", *url, err)
+				missing = append(missing, *url)
+				continue
+			}
+			media = append(media, *entry)
+		}
+	}
+
+	return media, missing, skipped
+}
+
+func (s *Service) downloadMedia(ctx context.Context, archive *zip.Writer, url string) (*MediaEntry, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	if resp.ContentLength > maxMediaSize {
+		return nil, errMediaTooLarge
+	}
+	// Lê um byte além do limite para distinguir arquivo grande de truncado
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxMediaSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxMediaSize {
+		return nil, errMediaTooLarge
+	}
+
+	sum := sha1.Sum([]byte(url))
+	ext := path.Ext(strings.SplitN(url, "?", 2)[0])
+	entryPath := "media/" + hex.EncodeToString(sum[:]) + ext
+
+	writer, err := archive.Create(entryPath)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+
+	return &MediaEntry{
+		URL:         url,
+		Path:        entryPath,
+		ContentType: resp.Header.Get("Content-Type"),
+	}, nil
+}
+
+// archiveContent é o conteúdo decodificado de um backup
+type archiveContent struct {
+	manifest  Manifest
+	decks     []entities.Deck
+	cards     []entities.Flashcard
+	favorites []entities.Favorite
+	media     []MediaEntry
+	files     map[string]*zip.File
+}
+
+func readArchive(archive *zip.Reader) (*archiveContent, error) {
+	content := &archiveContent{files: make(map[string]*zip.File)}
+	for _, file := range archive.File {
+		content.files[file.Name] = file
+	}
+
+	targets := []struct {
+		name     string
+		dest     interface{}
+		required bool
+	}{
+		{"manifest.json", &content.manifest, true},
+		{"decks.json", &content.decks, true},
+		{"cards.json", &content.cards, true},
+		{"favorites.json", &content.favorites, false},
+		{"media.json", &content.media, false},
+	}
+	for _, target := range targets {
+		file, ok := content.files[target.name]
+		if !ok {
+			if target.required {
+				return nil, fmt.Errorf("invalid backup: missing %s", target.name)
+			}
+			continue
+		}
+		reader, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("invalid backup: %w", err)
+		}
+		err = json.NewDecoder(io.LimitReader(reader, maxJSONSize)).Decode(target.dest)
+		reader.Close()
+		if err != nil {
+			return nil, fmt.Errorf("invalid backup: failed to read %s: %w", target.name, err)
+		}
+	}
+
+	if content.manifest.Version != archiveVersion {
+		return nil, fmt.Errorf("unsupported backup version: %d", content.manifest.Version)
+	}
+
+	return content, nil
+}
+
+// Restore reaplica um backup na conta do usuário. Documentos que já existem
+// na conta seguem a estratégia de conflito; documentos de outras contas
+// sempre recebem um novo ID. Conquistas e histórico de estudo vão no backup
+// só para consulta: o arquivo está nas mãos do usuário, então restaurá-los
+// permitiria forjar XP, sequências e conquistas.
+func (s *Service) Restore(ctx context.Context, userID string, archive *zip.Reader, conflict string) (*RestoreResult, error) {
+	if conflict == "" {
+		conflict = ConflictSkip
+	}
+	if conflict != ConflictSkip && conflict != ConflictOverwrite && conflict != ConflictDuplicate {
+		return nil, fmt.Errorf("invalid conflict strategy: %s", conflict)
+	}
+
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	content, err := readArchive(archive)
+	if err != nil {
+		return nil, err
+	}
+
+	// Suspensão e travas de moderação valem também para decks restaurados
+	suspended, err := s.repo.IsUserSuspended(ctx, userObjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	current, err := s.repo.GetDecks(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get decks: %w", err)
+	}
+	currentDecks := make(map[primitive.ObjectID]*entities.Deck, len(current))
+	for i := range current {
+		currentDecks[current[i].ID] = &current[i]
+	}
+
+	// Tudo é planejado antes de gravar, para os limites do plano valerem
+	// para o que de fato vai entrar na conta
+	result := &RestoreResult{}
+	deckIDs := make(map[string]primitive.ObjectID)
+	var decks []plannedDeck
+	newDecks := map[bool]int{}
+	for _, deck := range content.decks {
+		oldID := deck.ID
+		id, action, err := s.planDocument(ctx, "decks", "userId", userID, deck.ID, conflict)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("deck %s: %v", deck.Name, err))
+			continue
+		}
+		deckIDs[oldID.Hex()] = id
+		if action == actionSkipped {
+			result.Decks.add(action)
+			continue
+		}
+
+		existing := currentDecks[id]
+		var lockedAt *time.Time
+		if locked, ok := currentDecks[oldID]; ok {
+			lockedAt = locked.ModerationLockedAt
+		}
+		deck.ID = id
+		deck.UserID = userID
+		sanitizeRestoredDeck(&deck, existing, lockedAt, !suspended)
+		if existing == nil || existing.IsPublic != deck.IsPublic {
+			newDecks[deck.IsPublic]++
+		}
+		decks = append(decks, plannedDeck{deck: deck, action: action})
+	}
+
+	var cards []plannedCard
+	addedCards := make(map[primitive.ObjectID]int)
+	for _, card := range content.cards {
+		deckID, ok := deckIDs[card.DeckID]
+		if !ok {
+			result.Cards.Skipped++
+			continue
+		}
+		id, action, err := s.planDocument(ctx, "cards", "userId", userObjectID, card.ID, conflict)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("card %s: %v", card.ID.Hex(), err))
+			continue
+		}
+		if action == actionSkipped {
+			result.Cards.add(action)
+			continue
+		}
+		card.ID = id
+		card.DeckID = deckID.Hex()
+		card.UserID = userObjectID
+		if action == actionInserted {
+			addedCards[deckID]++
+		}
+		cards = append(cards, plannedCard{card: card, action: action})
+	}
+
+	if err := s.validateRestoreLimits(ctx, userID, newDecks, addedCards, currentDecks); err != nil {
+		return nil, err
+	}
+
+	mediaURLs := s.restoreMedia(content, result)
+
+	failedDecks := make(map[string]bool)
+	restoredDecks := make(map[primitive.ObjectID]bool)
+	for _, planned := range decks {
+		deck := planned.deck
+		// Subdecks que ganharam um novo ID apontam para o pai restaurado
+		if parentID, ok := deckIDs[deck.ParentID]; ok {
+			deck.ParentID = parentID.Hex()
+		}
+		if err := s.writeDocument(ctx, "decks", deck.ID, planned.action, deck); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("deck %s: %v", deck.Name, err))
+			failedDecks[deck.ID.Hex()] = true
+			continue
+		}
+		restoredDecks[deck.ID] = true
+		result.Decks.add(planned.action)
+	}
+
+	for _, planned := range cards {
+		card := planned.card
+		if failedDecks[card.DeckID] {
+			result.Cards.Skipped++
+			continue
+		}
+		card.ImageURL = rewriteMediaURL(card.ImageURL, mediaURLs)
+		card.AudioURL = rewriteMediaURL(card.AudioURL, mediaURLs)
+		if err := s.writeDocument(ctx, "cards", card.ID, planned.action, card); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("card %s: %v", card.ID.Hex(), err))
+			continue
+		}
+		if deckID, err := primitive.ObjectIDFromHex(card.DeckID); err == nil {
+			restoredDecks[deckID] = true
+		}
+		result.Cards.add(planned.action)
+	}
+
+	// A contagem de cards vem do que ficou gravado, não do arquivo
+	for deckID := range restoredDecks {
+		if err := s.repo.RefreshCardCount(ctx, deckID); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("deck %s: %v", deckID.Hex(), err))
+		}
+	}
+
+	for _, favorite := range content.favorites {
+		if deckID, ok := deckIDs[favorite.DeckID.Hex()]; ok {
+			favorite.DeckID = deckID
+		}
+		exists, err := s.repo.FavoriteExists(ctx, userObjectID, favorite.DeckID)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("favorite %s: %v", favorite.DeckID.Hex(), err))
+			continue
+		}
+		if exists {
+			result.Favorites.Skipped++
+			continue
+		}
+		favorite.ID = primitive.NewObjectID()
+		favorite.UserID = userObjectID
+		if err := s.repo.Insert(ctx, "favorites", favorite); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("favorite %s: %v", favorite.DeckID.Hex(), err))
+			continue
+		}
+		result.Favorites.Inserted++
+	}
+
+	return result, nil
+}
+
+const (
+	actionInserted    = "inserted"
+	actionOverwritten = "overwritten"
+	actionSkipped     = "skipped"
+)
+
+func (c *RestoreCounts) add(action string) {
+	switch action {
+	case actionInserted:
+		c.Inserted++
+	case actionOverwritten:
+		c.Overwritten++
+	case actionSkipped:
+		c.Skipped++
+	}
+}
+
+// planDocument decide, sem gravar, o que a estratégia de conflito faz com um
+// documento do arquivo e qual ID ele terá
+func (s *Service) planDocument(ctx context.Context, collection, ownerField string, owner interface{}, id primitive.ObjectID, conflict string) (primitive.ObjectID, string, error) {
+	if id.IsZero() {
+		return primitive.NewObjectID(), actionInserted, nil
+	}
+
+	currentOwner, found, err := s.repo.FindOwner(ctx, collection, ownerField, id)
+	if err != nil {
+		return id, "", err
+	}
+
+	if found && currentOwner == owner {
+		switch conflict {
+		case ConflictSkip:
+			return id, actionSkipped, nil
+		case ConflictOverwrite:
+			return id, actionOverwritten, nil
+		}
+	}
+
+	if found {
+		id = primitive.NewObjectID()
+	}
+	return id, actionInserted, nil
+}
+
+func (s *Service) writeDocument(ctx context.Context, collection string, id primitive.ObjectID, action string, doc interface{}) error {
+	if action == actionOverwritten {
+		return s.repo.Replace(ctx, collection, id, doc)
+	}
+	return s.repo.Insert(ctx, collection, doc)
+}
+
+// plannedDeck e plannedCard são documentos do arquivo já com o ID final,
+// esperando os limites do plano serem verificados
+type plannedDeck struct {
+	deck   entities.Deck
+	action string
+}
+
+type plannedCard struct {
+	card   entities.Flashcard
+	action string
+}
+
+// sanitizeRestoredDeck troca pelos valores do servidor o que o arquivo não
+// pode decidir: contadores do catálogo, ligação com a origem e trava de
+// moderação. Um deck sobrescrito mantém os valores atuais e um deck novo
+// começa zerado. O deck só continua público se o dono puder publicar e o
+// deck não estiver travado.
+func sanitizeRestoredDeck(deck, existing *entities.Deck, lockedAt *time.Time, canPublish bool) {
+	var server entities.Deck
+	if existing != nil {
+		server = *existing
+	}
+	deck.CardCount = server.CardCount
+	deck.ForkedFrom = server.ForkedFrom
+	deck.ForkCount = server.ForkCount
+	deck.RatingAverage = server.RatingAverage
+	deck.RatingCount = server.RatingCount
+	deck.DeletedOriginCards = server.DeletedOriginCards
+	deck.ModerationLockedAt = lockedAt
+	deck.IsPublic = deck.IsPublic && canPublish && lockedAt == nil
+}
+
+// validateRestoreLimits verifica os limites do plano para os decks que vão
+// entrar na conta (por visibilidade) e para os cards acrescentados a cada
+// deck, antes de qualquer escrita
+func (s *Service) validateRestoreLimits(ctx context.Context, userID string, newDecks map[bool]int, addedCards map[primitive.ObjectID]int, currentDecks map[primitive.ObjectID]*entities.Deck) error {
+	if s.limits == nil {
+		return nil
+	}
+	for _, isPublic := range []bool{false, true} {
+		if newDecks[isPublic] == 0 {
+			continue
+		}
+		if err := s.limits.ValidateDeckCapacity(userID, isPublic, newDecks[isPublic]); err != nil {
+			return err
+		}
+	}
+	for deckID, adding := range addedCards {
+		count := 0
+		if _, ok := currentDecks[deckID]; ok {
+			current, err := s.repo.CountCards(ctx, deckID.Hex())
+			if err != nil {
+				return fmt.Errorf("failed to count cards: %w", err)
+			}
+			count = int(current)
+		}
+		if err := s.limits.ValidateCardCapacity(userID, count, adding); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreMedia reenvia as mídias do backup quando há armazenamento
+// configurado e retorna o mapa URL antiga -> URL nova. Sem armazenamento
+// os cards mantêm as URLs originais.
+func (s *Service) restoreMedia(content *archiveContent, result *RestoreResult) map[string]string {
+	urls := make(map[string]string)
+	if s.media == nil || !s.media.IsConfigured() {
+		return urls
+	}
+
+	for _, entry := range content.media {
+		file, ok := content.files[entry.Path]
+		if !ok {
+			continue
+		}
+		reader, err := file.Open()
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("media %s: %v", entry.URL, err))
+			continue
+		}
+		data, err := io.ReadAll(io.LimitReader(reader, maxMediaSize))
+		reader.Close()
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("media %s: %v", entry.URL, err))
+			continue
+		}
+
+		ext := path.Ext(entry.Path)
+		contentType := entry.ContentType
+		if contentType == "" {
+			contentType = mime.TypeByExtension(ext)
+		}
+		folder := "images"
+		if strings.HasPrefix(contentType, "audio/") {
+			folder = "audio"
+		}
+
+		url, err := s.media.UploadBytes(data, folder, ext, contentType)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("media %s: %v", entry.URL, err))
+			continue
+		}
+		urls[entry.URL] = url
+		result.MediaUploaded++
+	}
+
+	return urls
+}
+
+func rewriteMediaURL(url *string, urls map[string]string) *string {
+	if url == nil {
+		return nil
+	}
+	if newURL, ok := urls[*url]; ok {
+		return &newURL
+	}
+	return url
+}
@@ -0,0 +1,73 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSanitizeRestoredDeck(t *testing.T) {
+	// Um deck novo não traz contadores nem publicação de quem está suspenso
+	deck := entities.Deck{IsPublic: true, ForkCount: 900, RatingAverage: 5, RatingCount: 300, CardCount: 7,
+		ForkedFrom: "other", DeletedOriginCards: []string{"c1"}}
+	sanitizeRestoredDeck(&deck, nil, nil, false)
+	assert.False(t, deck.IsPublic)
+	assert.Zero(t, deck.ForkCount)
+	assert.Zero(t, deck.RatingAverage)
+	assert.Zero(t, deck.RatingCount)
+	assert.Zero(t, deck.CardCount)
+	assert.Empty(t, deck.ForkedFrom)
+	assert.Empty(t, deck.DeletedOriginCards)
+
+	// Um deck sobrescrito mantém os valores do servidor
+	lockedAt := time.Now()
+	existing := &entities.Deck{ForkCount: 2, RatingAverage: 3.5, RatingCount: 4, ForkedFrom: "source"}
+	deck = entities.Deck{IsPublic: true, ForkCount: 900}
+	sanitizeRestoredDeck(&deck, existing, &lockedAt, true)
+	assert.False(t, deck.IsPublic)
+	assert.Equal(t, 2, deck.ForkCount)
+	assert.Equal(t, 3.5, deck.RatingAverage)
+	assert.Equal(t, "source", deck.ForkedFrom)
+	assert.Equal(t, &lockedAt, deck.ModerationLockedAt)
+
+	deck = entities.Deck{IsPublic: true}
+	sanitizeRestoredDeck(&deck, nil, nil, true)
+	assert.True(t, deck.IsPublic)
+}
+
+type fakeLimits struct {
+	decks map[bool]int
+	cards []int
+	err   error
+}
+
+func (f *fakeLimits) ValidateDeckCapacity(userID string, isPublic bool, n int) error {
+	f.decks[isPublic] = n
+	return f.err
+}
+
+func (f *fakeLimits) ValidateCardCapacity(userID string, currentCount, adding int) error {
+	f.cards = append(f.cards, adding)
+	return f.err
+}
+
+func TestValidateRestoreLimits(t *testing.T) {
+	limits := &fakeLimits{decks: map[bool]int{}}
+	service := &Service{limits: limits}
+
+	deckID := primitive.NewObjectID()
+	err := service.validateRestoreLimits(context.Background(), "user", map[bool]int{false: 3}, map[primitive.ObjectID]int{deckID: 40}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[bool]int{false: 3}, limits.decks)
+	assert.Equal(t, []int{40}, limits.cards)
+
+	limits.err = errors.New("deck limit reached")
+	err = service.validateRestoreLimits(context.Background(), "user", map[bool]int{true: 1}, nil, nil)
+	assert.EqualError(t, err, "deck limit reached")
+}
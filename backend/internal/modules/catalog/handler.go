@@ -0,0 +1,35 @@
+package catalog
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// GET /api/catalog?q=&tag=&language=&sort=newest|favorites|forks|rating&cursor=&limit=
+func (h *Handler) Browse(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	page, err := h.service.Browse(c.Request.Context(), Query{
+		Text:     c.Query("q"),
+		Tag:      c.Query("tag"),
+		Language: c.Query("language"),
+		Sort:     c.Query("sort"),
+		Cursor:   c.Query("cursor"),
+		Limit:    limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
@@ -0,0 +1,22 @@
+package catalog
+
+import (
+	"flashcard-backend/internal/infrastructure/database"
+)
+
+type Module struct {
+	Handler *Handler
+	Service *Service
+	Repo    *Repository
+}
+
+func NewModule(db *database.MongoDB) *Module {
+	repo := NewRepository(db)
+	service := NewService(repo)
+	handler := NewHandler(service)
+	return &Module{
+		Handler: handler,
+		Service: service,
+		Repo:    repo,
+	}
+}
@@ -0,0 +1,133 @@
+package catalog
+
+import (
+	"context"
+
+	"flashcard-backend/internal/infrastructure/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type Repository struct {
+	db *database.MongoDB
+}
+
+func NewRepository(db *database.MongoDB) *Repository {
+	return &Repository{db: db}
+}
+
+// SearchDecks retorna os decks públicos cujo nome, descrição ou tags casam
+// com a busca
+func (r *Repository) SearchDecks(ctx context.Context, text string, limit int64) ([]primitive.ObjectID, error) {
+	filter := bson.M{"$text": bson.M{"$search": text}, "isPublic": true}
+	opts := options.Find().SetProjection(bson.M{"_id": 1}).SetLimit(limit)
+	cursor, err := r.db.GetCollection("decks").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, 0, len(docs))
+	for _, doc := range docs {
+		ids = append(ids, doc.ID)
+	}
+	return ids, nil
+}
+
+// SearchCardDecks retorna os decks públicos que têm cards cujo texto casa
+// com a busca. Os decks privados saem antes do limite, para não ocuparem as
+// vagas dos públicos.
+func (r *Repository) SearchCardDecks(ctx context.Context, text string, limit int64) ([]primitive.ObjectID, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"$text": bson.M{"$search": text}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$deckId"}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from": "decks",
+			"let": bson.M{"deckId": bson.M{
+				"$convert": bson.M{"input": "$_id", "to": "objectId", "onError": nil, "onNull": nil},
+			}},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"$expr": bson.M{"$and": bson.A{
+					bson.M{"$eq": bson.A{"$_id", "$$deckId"}},
+					bson.M{"$eq": bson.A{"$isPublic", true}},
+				}}}},
+				bson.M{"$project": bson.M{"_id": 1}},
+			},
+			"as": "deck",
+		}}},
+		{{Key: "$match", Value: bson.M{"deck": bson.M{"$ne": bson.A{}}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+	cursor, err := r.db.GetCollection("cards").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		DeckID string `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, 0, len(docs))
+	for _, doc := range docs {
+		if id, err := primitive.ObjectIDFromHex(doc.DeckID); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// ListDecks percorre os decks públicos ordenados por sortValue (decrescente)
+// e _id. A contagem de favoritos vem da coleção favorites; quando ela não é
+// o critério de ordenação, só é calculada para a página retornada.
+func (r *Repository) ListDecks(ctx context.Context, filter bson.M, sortExpr interface{}, sortByFavorites bool, after bson.M, limit int64) ([]CatalogDeck, error) {
+	favoriteStages := []bson.D{
+		{{Key: "$lookup", Value: bson.M{
+			"from":         "favorites",
+			"localField":   "_id",
+			"foreignField": "deck_id",
+			"as":           "favorites",
+		}}},
+		{{Key: "$addFields", Value: bson.M{"favoriteCount": bson.M{"$size": "$favorites"}}}},
+		{{Key: "$project", Value: bson.M{"favorites": 0}}},
+	}
+
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: filter}}}
+	if sortByFavorites {
+		pipeline = append(pipeline, favoriteStages...)
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$addFields", Value: bson.M{"sortValue": sortExpr}}})
+	if after != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: after}})
+	}
+	pipeline = append(pipeline,
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "sortValue", Value: -1}, {Key: "_id", Value: -1}}}},
+		bson.D{{Key: "$limit", Value: limit}},
+	)
+	if !sortByFavorites {
+		pipeline = append(pipeline, favoriteStages...)
+	}
+
+	cursor, err := r.db.GetCollection("decks").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	decks := []CatalogDeck{}
+	if err := cursor.All(ctx, &decks); err != nil {
+		return nil, err
+	}
+	return decks, nil
+}
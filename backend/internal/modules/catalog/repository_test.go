@@ -0,0 +1,33 @@
+package catalog
+
+import (
+	"context"
+	"testing"
+
+	"flashcard-backend/internal/infrastructure/database"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestSearchCardDecksFiltersPublicBeforeLimit(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("pipeline", func(mt *mtest.T) {
+		repo := NewRepository(&database.MongoDB{Client: mt.Client, Database: mt.DB})
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.cards", mtest.FirstBatch))
+
+		_, err := repo.SearchCardDecks(context.Background(), "mitocôndria", 1000)
+		assert.NoError(mt, err)
+
+		stages, err := mt.GetStartedEvent().Command.Lookup("pipeline").Array().Values()
+		assert.NoError(mt, err)
+		var names []string
+		for _, stage := range stages {
+			names = append(names, stage.Document().Index(0).Key())
+		}
+		assert.Equal(mt, []string{"$match", "$group", "$lookup", "$match", "$limit"}, names)
+		assert.Equal(mt, "decks", stages[2].Document().Lookup("$lookup", "from").StringValue())
+	})
+}
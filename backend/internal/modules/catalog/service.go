@@ -0,0 +1,207 @@
+package catalog
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Ordenações do catálogo, sempre da maior para a menor
+const (
+	SortNewest    = "newest"
+	SortFavorites = "favorites"
+	SortForks     = "forks"
+	SortRating    = "rating"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 50
+	// maxSearchMatches limita quantos decks uma busca textual considera
+	maxSearchMatches = 1000
+)
+
+// sortExpressions calcula o valor usado na ordenação e no cursor
+var sortExpressions = map[string]interface{}{
+	SortNewest:    "$createdAt",
+	SortFavorites: "$favoriteCount",
+	SortForks:     bson.M{"$ifNull": bson.A{"$forkCount", 0}},
+	SortRating:    bson.M{"$ifNull": bson.A{"$ratingAverage", 0}},
+}
+
+// CatalogDeck é um deck público com a quantidade de favoritos
+type CatalogDeck struct {
+	entities.Deck `bson:",inline"`
+	FavoriteCount int         `bson:"favoriteCount" json:"favorite_count"`
+	SortValue     interface{} `bson:"sortValue" json:"-"`
+}
+
+// Query descreve uma consulta ao catálogo
+type Query struct {
+	Text     string
+	Tag      string
+	Language string
+	Sort     string
+	Cursor   string
+	Limit    int
+}
+
+// Page é uma página do catálogo; NextCursor vazio indica a última
+type Page struct {
+	Decks      []CatalogDeck `json:"decks"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+type Service struct {
+	repo *Repository
+}
+
+func NewService(repo *Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Browse lista os decks públicos com busca, filtros e ordenação
+func (s *Service) Browse(ctx context.Context, query Query) (*Page, error) {
+	if query.Sort == "" {
+		query.Sort = SortNewest
+	}
+	sortExpr, ok := sortExpressions[query.Sort]
+	if !ok {
+		return nil, fmt.Errorf("invalid sort: %s (use newest, favorites, forks or rating)", query.Sort)
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	var after bson.M
+	if query.Cursor != "" {
+		var err error
+		if after, err = decodeCursor(query.Sort, query.Cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	filter := bson.M{"isPublic": true}
+	if tag := strings.TrimSpace(query.Tag); tag != "" {
+		filter["tags"] = tag
+	}
+	if language := strings.ToLower(strings.TrimSpace(query.Language)); language != "" {
+		filter["language"] = language
+	}
+	if text := strings.TrimSpace(query.Text); text != "" {
+		ids, err := s.searchDeckIDs(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) == 0 {
+			return &Page{Decks: []CatalogDeck{}}, nil
+		}
+		filter["_id"] = bson.M{"$in": ids}
+	}
+
+	decks, err := s.repo.ListDecks(ctx, filter, sortExpr, query.Sort == SortFavorites, after, int64(limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list catalog: %w", err)
+	}
+
+	page := &Page{Decks: decks}
+	if len(decks) > limit {
+		page.Decks = decks[:limit]
+		last := page.Decks[limit-1]
+		page.NextCursor = encodeCursor(query.Sort, last.SortValue, last.ID)
+	}
+	return page, nil
+}
+
+// searchDeckIDs junta os decks que casam pelo próprio texto e os que têm
+// cards que casam
+func (s *Service) searchDeckIDs(ctx context.Context, text string) ([]primitive.ObjectID, error) {
+	deckIDs, err := s.repo.SearchDecks(ctx, text, maxSearchMatches)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search decks: %w", err)
+	}
+	cardDeckIDs, err := s.repo.SearchCardDecks(ctx, text, maxSearchMatches)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search cards: %w", err)
+	}
+
+	seen := make(map[primitive.ObjectID]bool, len(deckIDs)+len(cardDeckIDs))
+	ids := make([]primitive.ObjectID, 0, len(deckIDs)+len(cardDeckIDs))
+	for _, id := range append(deckIDs, cardDeckIDs...) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// pageCursor é a posição do último deck de uma página: o valor de ordenação
+// e o ID, que desempata
+type pageCursor struct {
+	Sort   string     `json:"s"`
+	Number float64    `json:"n,omitempty"`
+	Time   *time.Time `json:"t,omitempty"`
+	ID     string     `json:"id"`
+}
+
+func encodeCursor(sort string, value interface{}, id primitive.ObjectID) string {
+	cursor := pageCursor{Sort: sort, ID: id.Hex()}
+	switch v := value.(type) {
+	case primitive.DateTime:
+		t := v.Time().UTC()
+		cursor.Time = &t
+	case time.Time:
+		t := v.UTC()
+		cursor.Time = &t
+	case int32:
+		cursor.Number = float64(v)
+	case int64:
+		cursor.Number = float64(v)
+	case float64:
+		cursor.Number = v
+	}
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor transforma o cursor no filtro dos decks que vêm depois dele
+func decodeCursor(sort, token string) (bson.M, error) {
+	invalid := fmt.Errorf("invalid cursor")
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, invalid
+	}
+	var cursor pageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.Sort != sort {
+		return nil, invalid
+	}
+	id, err := primitive.ObjectIDFromHex(cursor.ID)
+	if err != nil {
+		return nil, invalid
+	}
+
+	var value interface{} = cursor.Number
+	if sort == SortNewest {
+		if cursor.Time == nil {
+			return nil, invalid
+		}
+		value = *cursor.Time
+	}
+	return bson.M{"$or": bson.A{
+		bson.M{"sortValue": bson.M{"$lt": value}},
+		bson.M{"sortValue": value, "_id": bson.M{"$lt": id}},
+	}}, nil
+}
@@ -0,0 +1,36 @@
+package catalog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	id := primitive.NewObjectID()
+
+	after, err := decodeCursor(SortFavorites, encodeCursor(SortFavorites, int32(7), id))
+	require.NoError(t, err)
+	clauses := after["$or"].(bson.A)
+	assert.Equal(t, bson.M{"sortValue": bson.M{"$lt": float64(7)}}, clauses[0])
+	assert.Equal(t, bson.M{"sortValue": float64(7), "_id": bson.M{"$lt": id}}, clauses[1])
+
+	createdAt := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	after, err = decodeCursor(SortNewest, encodeCursor(SortNewest, primitive.NewDateTimeFromTime(createdAt), id))
+	require.NoError(t, err)
+	assert.Equal(t, bson.M{"sortValue": bson.M{"$lt": createdAt}}, after["$or"].(bson.A)[0])
+}
+
+func TestDecodeCursorRejectsOtherSort(t *testing.T) {
+	token := encodeCursor(SortForks, int64(3), primitive.NewObjectID())
+
+	_, err := decodeCursor(SortRating, token)
+	assert.Error(t, err)
+
+	_, err = decodeCursor(SortForks, "not-a-cursor")
+	assert.Error(t, err)
+}
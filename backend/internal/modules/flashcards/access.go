@@ -0,0 +1,244 @@
+package flashcards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+	"flashcard-backend/internal/modules/admin"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrDeckNotFound   = errors.New("deck not found")
+	ErrCardNotFound   = errors.New("card not found")
+	ErrForbidden      = errors.New("access denied")
+	ErrMemberNotFound = errors.New("member not found")
+	ErrSuspended      = errors.New("your account is suspended from publishing decks")
+	ErrDeckLocked     = errors.New("this deck was unpublished by moderation and cannot be made public")
+)
+
+// getReadableDeck retorna o deck se ele for público ou se o usuário for dono
+// ou membro
+func (s *Service) getReadableDeck(userID, deckID string) (*entities.Deck, error) {
+	deck, err := s.getDeck(deckID)
+	if err != nil {
+		return nil, err
+	}
+	deck.Role = s.deckRole(userID, deck)
+	if deck.Role == "" && !deck.IsPublic {
+		return nil, ErrForbidden
+	}
+	return deck, nil
+}
+
+// getEditableDeck retorna o deck se o usuário puder alterar seus cards
+// (dono ou editor)
+func (s *Service) getEditableDeck(userID, deckID string) (*entities.Deck, error) {
+	deck, err := s.getDeck(deckID)
+	if err != nil {
+		return nil, err
+	}
+	deck.Role = s.deckRole(userID, deck)
+	if !canEditDeck(deck.Role) {
+		return nil, ErrForbidden
+	}
+	return deck, nil
+}
+
+// getOwnedDeck retorna o deck apenas se ele pertencer ao usuário
+func (s *Service) getOwnedDeck(userID, deckID string) (*entities.Deck, error) {
+	deck, err := s.getDeck(deckID)
+	if err != nil {
+		return nil, err
+	}
+	if deck.UserID != userID {
+		return nil, ErrForbidden
+	}
+	deck.Role = entities.DeckRoleOwner
+	return deck, nil
+}
+
+// deckRole retorna o papel do usuário no deck. A participação em um deck vale
+// para os subdecks, então os decks acima dele também são consultados. Retorna
+// "" se o usuário não participa do deck.
+func (s *Service) deckRole(userID string, deck *entities.Deck) string {
+	if deck.UserID == userID {
+		return entities.DeckRoleOwner
+	}
+
+	deckIDs := []string{deck.ID.Hex()}
+	parentID := deck.ParentID
+	for depth := 0; parentID != "" && depth < maxDeckDepth; depth++ {
+		parent, err := s.getDeck(parentID)
+		if err != nil {
+			break
+		}
+		deckIDs = append(deckIDs, parentID)
+		parentID = parent.ParentID
+	}
+
+	memberships, err := s.repo.GetUserMemberships(userID, deckIDs)
+	if err != nil {
+		return ""
+	}
+	role := ""
+	for _, membership := range memberships {
+		if deckRoleRank[membership.Role] > deckRoleRank[role] {
+			role = membership.Role
+		}
+	}
+	return role
+}
+
+// maxDeckDepth limita a subida pelos decks pais
+const maxDeckDepth = 32
+
+var deckRoleRank = map[string]int{
+	entities.DeckRoleViewer: 1,
+	entities.DeckRoleEditor: 2,
+	entities.DeckRoleOwner:  3,
+}
+
+func canEditDeck(role string) bool {
+	return role == entities.DeckRoleOwner || role == entities.DeckRoleEditor
+}
+
+func (s *Service) getDeck(deckID string) (*entities.Deck, error) {
+	deckObjectID, err := primitive.ObjectIDFromHex(deckID)
+	if err != nil {
+		return nil, ErrDeckNotFound
+	}
+	deck, err := s.repo.GetDeckByID(deckObjectID)
+	if err != nil {
+		return nil, ErrDeckNotFound
+	}
+	return deck, nil
+}
+
+// userLocation retorna o fuso do usuário; sem fuso válido, UTC
+func (s *Service) userLocation(userID string) *time.Location {
+	if s.authService == nil {
+		return time.UTC
+	}
+	user, err := s.authService.GetUserByID(userID)
+	if err != nil || user == nil || user.TimeZone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(user.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// isAdminUser indica se o email do usuário está em AdminEmails, o que
+// libera os limites do plano
+func (s *Service) isAdminUser(userID string) bool {
+	if s.authService == nil || s.adminService == nil {
+		return false
+	}
+	user, err := s.authService.GetUserByID(userID)
+	if err != nil || user == nil {
+		return false
+	}
+	adminConfig, err := s.adminService.(*admin.Service).GetConfig(context.Background())
+	if err != nil || adminConfig == nil {
+		return false
+	}
+	for _, adminEmail := range adminConfig.AdminEmails {
+		if user.Email == adminEmail {
+			return true
+		}
+	}
+	return false
+}
+
+// validateCardCapacity verifica se cabem mais adding cards em um deck que
+// já tem currentCount cards
+func (s *Service) validateCardCapacity(userID string, currentCount, adding int) error {
+	if adding <= 0 || s.adminService == nil || s.isAdminUser(userID) {
+		return nil
+	}
+	// TODO: Get user plan from plans service
+	userPlan := "free"
+	return s.adminService.ValidateCardLimit(context.Background(), userPlan, currentCount+adding-1)
+}
+
+// ValidateDeckCapacity expõe a outros módulos (como a restauração de
+// backups) o limite de decks do plano
+func (s *Service) ValidateDeckCapacity(userID string, isPublic bool, n int) error {
+	return s.validateDeckCapacityFor(userID, isPublic, n)
+}
+
+// ValidateCardCapacity expõe a outros módulos o limite de cards por deck
+func (s *Service) ValidateCardCapacity(userID string, currentCount, adding int) error {
+	return s.validateCardCapacity(userID, currentCount, adding)
+}
+
+// respondError traduz os erros de acesso do serviço para status HTTP
+func respondError(c *gin.Context, err error) {
+	var lintErr *CardLintError
+	switch {
+	case errors.As(err, &lintErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "issues": lintErr.Issues})
+	case errors.Is(err, ErrDeckNotFound), errors.Is(err, ErrCardNotFound), errors.Is(err, ErrTrashItemNotFound),
+		errors.Is(err, ErrMemberNotFound), errors.Is(err, ErrRatingNotFound), errors.Is(err, ErrShareLinkNotFound),
+		errors.Is(err, ErrStudySessionNotFound), errors.Is(err, ErrQuizNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrStudySessionClosed), errors.Is(err, ErrNotCurrentCard), errors.Is(err, ErrQuizClosed),
+		errors.Is(err, ErrQuizAlreadyAnswered):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrShareLinkExpired), errors.Is(err, ErrQuizTimeUp):
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrForbidden), errors.Is(err, ErrSuspended), errors.Is(err, ErrDeckLocked):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
+
+// validatePublishing impede que usuários suspensos pela moderação publiquem
+// decks
+func (s *Service) validatePublishing(userID string, isPublic bool) error {
+	if !isPublic || s.authService == nil {
+		return nil
+	}
+	user, err := s.authService.GetUserByID(userID)
+	if err == nil && user != nil && user.SuspendedAt != nil {
+		return ErrSuspended
+	}
+	return nil
+}
+
+// validateDeckCapacity verifica se o usuário ainda pode ter mais um deck
+// com a visibilidade informada
+func (s *Service) validateDeckCapacity(userID string, isPublic bool) error {
+	return s.validateDeckCapacityFor(userID, isPublic, 1)
+}
+
+// validateDeckCapacityFor verifica se cabem mais n decks com a visibilidade
+// informada
+func (s *Service) validateDeckCapacityFor(userID string, isPublic bool, n int) error {
+	if s.adminService == nil || s.isAdminUser(userID) {
+		return nil
+	}
+	decks, err := s.repo.GetDecksByUserEmail(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get decks: %w", err)
+	}
+	count := 0
+	for _, deck := range decks {
+		if deck.IsPublic == isPublic {
+			count++
+		}
+	}
+	// TODO: Get user plan from plans service
+	userPlan := "free"
+	return s.adminService.ValidateDeckLimit(context.Background(), userPlan, count+n-1, isPublic)
+}
@@ -0,0 +1,30 @@
+package flashcards
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BulkUpdateCards move, copia, etiqueta, edita ou apaga vários cards de uma vez
+// POST /api/cards/bulk
+func (h *Handler) BulkUpdateCards(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req BulkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.BulkUpdateCards(userID, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,123 @@
+package flashcards
+
+import (
+	"context"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (r *MongoRepository) GetFlashcardsByIDs(ids []primitive.ObjectID) ([]entities.Flashcard, error) {
+	return r.findFlashcards(bson.M{"_id": bson.M{"$in": ids}})
+}
+
+// FindFlashcards lista os cards dos decks informados
+func (r *MongoRepository) FindFlashcards(deckIDs []string, limit int64) ([]entities.Flashcard, error) {
+	return r.QueryFlashcards(bson.M{"deckId": bson.M{"$in": deckIDs}}, limit)
+}
+
+// QueryFlashcards lista os cards que casam com o filtro, dos mais antigos
+// para os mais novos
+func (r *MongoRepository) QueryFlashcards(filter bson.M, limit int64) ([]entities.Flashcard, error) {
+	return r.findFlashcards(filter, options.Find().SetLimit(limit).SetSort(bson.M{"createdAt": 1}))
+}
+
+func (r *MongoRepository) findFlashcards(filter bson.M, opts ...*options.FindOptions) ([]entities.Flashcard, error) {
+	cursor, err := r.collection.Find(context.Background(), filter, opts...)
+	if err != nil {
+		return []entities.Flashcard{}, err
+	}
+	defer cursor.Close(context.Background())
+
+	var cards []entities.Flashcard
+	if err = cursor.All(context.Background(), &cards); err != nil {
+		return []entities.Flashcard{}, err
+	}
+	if cards == nil {
+		cards = []entities.Flashcard{}
+	}
+	return cards, nil
+}
+
+// SetFlashcardsSuspended suspende ou reativa os cards; suspender não altera
+// o agendamento, que continua de onde parou quando o card volta
+func (r *MongoRepository) SetFlashcardsSuspended(ids []primitive.ObjectID, suspended bool) (int64, error) {
+	update := bson.M{"$unset": bson.M{"suspended": ""}, "$set": bson.M{"updatedAt": time.Now()}}
+	if suspended {
+		update = bson.M{"$set": bson.M{"suspended": true, "updatedAt": time.Now()}}
+	}
+	result, err := r.collection.UpdateMany(context.Background(), bson.M{"_id": bson.M{"$in": ids}}, update)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *MongoRepository) MoveFlashcards(ids []primitive.ObjectID, deckID string) (int64, error) {
+	result, err := r.collection.UpdateMany(context.Background(),
+		bson.M{"_id": bson.M{"$in": ids}},
+		bson.M{"$set": bson.M{"deckId": deckID, "updatedAt": time.Now()}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *MongoRepository) AddFlashcardTags(ids []primitive.ObjectID, tags []string) (int64, error) {
+	result, err := r.collection.UpdateMany(context.Background(),
+		bson.M{"_id": bson.M{"$in": ids}, "tags": bson.M{"$not": bson.M{"$all": tags}}},
+		bson.M{
+			"$addToSet": bson.M{"tags": bson.M{"$each": tags}},
+			"$set":      bson.M{"updatedAt": time.Now()},
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *MongoRepository) RemoveFlashcardTags(ids []primitive.ObjectID, tags []string) (int64, error) {
+	result, err := r.collection.UpdateMany(context.Background(),
+		bson.M{"_id": bson.M{"$in": ids}, "tags": bson.M{"$in": tags}},
+		bson.M{
+			"$pull": bson.M{"tags": bson.M{"$in": tags}},
+			"$set":  bson.M{"updatedAt": time.Now()},
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// SetCardOriginBase registra o conteúdo de origem usado na última sincronização
+func (r *MongoRepository) SetCardOriginBase(cardID primitive.ObjectID, base entities.CardContent, syncedAt time.Time) error {
+	_, err := r.collection.UpdateOne(context.Background(),
+		bson.M{"_id": cardID},
+		bson.M{"$set": bson.M{"origin.base": base, "origin.syncedAt": syncedAt}},
+	)
+	return err
+}
+
+// RefreshDeckCardCount regrava o cardCount do deck a partir da coleção de cards
+func (r *MongoRepository) RefreshDeckCardCount(deckID string) error {
+	deckObjectID, err := primitive.ObjectIDFromHex(deckID)
+	if err != nil {
+		return err
+	}
+	count, err := r.CountCardsByDeckIDString(deckID)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.GetCollection("decks").UpdateOne(context.Background(),
+		bson.M{"_id": deckObjectID},
+		bson.M{"$set": bson.M{"cardCount": count}},
+	)
+	return err
+}
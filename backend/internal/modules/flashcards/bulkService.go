@@ -0,0 +1,437 @@
+package flashcards
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Ações de edição em massa
+const (
+	BulkMove       = "move"
+	BulkCopy       = "copy"
+	BulkAddTags    = "add_tags"
+	BulkRemoveTags = "remove_tags"
+	BulkReplace    = "replace"
+	BulkDelete     = "delete"
+	BulkSuspend    = "suspend"
+	BulkUnsuspend  = "unsuspend"
+)
+
+const maxBulkCards = 1000
+
+// BulkRequest seleciona os cards por IDs ou por busca e descreve a ação
+type BulkRequest struct {
+	Action        string   `json:"action"`
+	CardIDs       []string `json:"card_ids"`
+	DeckID        string   `json:"deck_id"` // limita a busca ao deck e seus subdecks
+	Query         string   `json:"query"`   // linguagem de busca de cards
+	TargetDeckID  string   `json:"target_deck_id"`
+	Tags          []string `json:"tags"`
+	Find          string   `json:"find"`
+	Replace       string   `json:"replace"`
+	CaseSensitive bool     `json:"case_sensitive"`
+}
+
+// BulkResult resume uma operação em massa
+type BulkResult struct {
+	Action     string   `json:"action"`
+	Matched    int      `json:"matched"`
+	Affected   int      `json:"affected"`
+	Skipped    int      `json:"skipped"`
+	CreatedIDs []string `json:"created_ids,omitempty"`
+	Errors     []string `json:"errors,omitempty"`
+}
+
+// BulkUpdateCards aplica a ação aos cards selecionados do usuário
+func (s *Service) BulkUpdateCards(userID string, req BulkRequest) (*BulkResult, error) {
+	switch req.Action {
+	case BulkMove, BulkCopy, BulkAddTags, BulkRemoveTags, BulkReplace, BulkDelete, BulkSuspend, BulkUnsuspend:
+	default:
+		return nil, fmt.Errorf("invalid bulk action: %s", req.Action)
+	}
+
+	cards, err := s.selectBulkCards(userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BulkResult{Action: req.Action, Matched: len(cards)}
+	if len(cards) == 0 {
+		return result, nil
+	}
+
+	switch req.Action {
+	case BulkMove:
+		err = s.bulkMove(userID, req.TargetDeckID, cards, result)
+	case BulkCopy:
+		err = s.bulkCopy(userID, req.TargetDeckID, cards, result)
+	case BulkAddTags, BulkRemoveTags:
+		err = s.bulkTags(s.revisionAuthorFor(userID), req.Action, req.Tags, cards, result)
+	case BulkReplace:
+		err = s.bulkReplace(s.revisionAuthorFor(userID), req, cards, result)
+	case BulkDelete:
+		err = s.bulkDelete(cards, result)
+	case BulkSuspend, BulkUnsuspend:
+		err = s.bulkSuspend(req.Action == BulkSuspend, cards, result)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// selectBulkCards resolve a seleção garantindo que todos os cards estejam
+// em decks que o usuário pode editar (próprios ou compartilhados como editor)
+func (s *Service) selectBulkCards(userID string, req BulkRequest) ([]entities.Flashcard, error) {
+	decks, err := s.getEditableDecks(userID)
+	if err != nil {
+		return nil, err
+	}
+	owned := make(map[string]entities.Deck, len(decks))
+	for _, deck := range decks {
+		owned[deck.ID.Hex()] = deck
+	}
+
+	if len(req.CardIDs) > 0 {
+		if len(req.CardIDs) > maxBulkCards {
+			return nil, fmt.Errorf("too many cards: the limit is %d per operation", maxBulkCards)
+		}
+		ids := make([]primitive.ObjectID, 0, len(req.CardIDs))
+		for _, id := range req.CardIDs {
+			objectID, err := primitive.ObjectIDFromHex(id)
+			if err != nil {
+				return nil, fmt.Errorf("invalid card ID: %s", id)
+			}
+			ids = append(ids, objectID)
+		}
+		cards, err := s.repo.GetFlashcardsByIDs(ids)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get cards: %w", err)
+		}
+		for _, card := range cards {
+			if _, ok := owned[card.DeckID]; !ok {
+				return nil, ErrForbidden
+			}
+		}
+		return cards, nil
+	}
+
+	if strings.TrimSpace(req.Query) == "" && req.DeckID == "" {
+		return nil, fmt.Errorf("card_ids or query is required")
+	}
+
+	var deckIDs []string
+	if req.DeckID != "" {
+		deck, err := s.getEditableDeck(userID, req.DeckID)
+		if err != nil {
+			return nil, err
+		}
+		deckIDs = append(deckIDs, deck.ID.Hex())
+		for _, other := range decks {
+			if other.UserID == deck.UserID && isDescendantPath(other.Name, deck.Name) {
+				deckIDs = append(deckIDs, other.ID.Hex())
+			}
+		}
+	} else {
+		for id := range owned {
+			deckIDs = append(deckIDs, id)
+		}
+	}
+
+	compiled, err := s.compileCardQuery(userID, decks, req.Query)
+	if err != nil {
+		return nil, err
+	}
+	filter := bson.M{"deckId": bson.M{"$in": deckIDs}}
+	compiled.apply(filter)
+
+	cards, err := s.repo.QueryFlashcards(filter, maxBulkCards+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search cards: %w", err)
+	}
+	if len(cards) > maxBulkCards {
+		return nil, fmt.Errorf("too many cards: the limit is %d per operation", maxBulkCards)
+	}
+	return cards, nil
+}
+
+func (s *Service) bulkMove(userID, targetDeckID string, cards []entities.Flashcard, result *BulkResult) error {
+	target, err := s.getEditableDeck(userID, targetDeckID)
+	if err != nil {
+		return err
+	}
+	targetID := target.ID.Hex()
+
+	moving, skipped := cardsToMove(cards, targetID)
+	result.Skipped += skipped
+	if len(moving) == 0 {
+		return nil
+	}
+
+	ids := make([]primitive.ObjectID, len(moving))
+	cardIDs := make([]string, len(moving))
+	sourceDecks := []string{targetID}
+	for i, card := range moving {
+		ids[i] = card.ID
+		cardIDs[i] = card.ID.Hex()
+		sourceDecks = append(sourceDecks, card.DeckID)
+	}
+
+	count, err := s.repo.CountCardsByDeckIDString(targetID)
+	if err != nil {
+		return fmt.Errorf("failed to count cards: %w", err)
+	}
+	if err := s.validateCardCapacity(userID, int(count), len(ids)); err != nil {
+		return err
+	}
+
+	moved, err := s.repo.MoveFlashcards(ids, targetID)
+	if err != nil {
+		return fmt.Errorf("failed to move cards: %w", err)
+	}
+	result.Affected = int(moved)
+	if err := s.repo.MoveCardSchedules(cardIDs, targetID); err != nil {
+		fmt.Printf("Failed to move card schedules: %v\n", err)
+	}
+	s.refreshDeckCardCounts(sourceDecks...)
+	return nil
+}
+
+// cardsToMove separa os cards que já estão no deck de destino; só os demais
+// contam para o limite de cards do destino
+func cardsToMove(cards []entities.Flashcard, targetID string) ([]entities.Flashcard, int) {
+	var moving []entities.Flashcard
+	skipped := 0
+	for _, card := range cards {
+		if card.DeckID == targetID {
+			skipped++
+			continue
+		}
+		moving = append(moving, card)
+	}
+	return moving, skipped
+}
+
+// bulkCopy cria cópias com agendamento zerado no deck de destino
+func (s *Service) bulkCopy(userID, targetDeckID string, cards []entities.Flashcard, result *BulkResult) error {
+	target, err := s.getEditableDeck(userID, targetDeckID)
+	if err != nil {
+		return err
+	}
+	targetID := target.ID.Hex()
+
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	count, err := s.repo.CountCardsByDeckIDString(targetID)
+	if err != nil {
+		return fmt.Errorf("failed to count cards: %w", err)
+	}
+	if err := s.validateCardCapacity(userID, int(count), len(cards)); err != nil {
+		return err
+	}
+
+	for _, card := range cards {
+		copied := &entities.Flashcard{
+			DeckID:             targetID,
+			UserID:             userObjectID,
+			Question:           card.Question,
+			Answer:             card.Answer,
+			Alternatives:       card.Alternatives,
+			CorrectAlternative: card.CorrectAlternative,
+			ImageURL:           card.ImageURL,
+			AudioURL:           card.AudioURL,
+			Tags:               card.Tags,
+			Difficulty:         card.Difficulty,
+		}
+		if err := s.repo.Create(context.Background(), copied); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("card %s: %v", card.ID.Hex(), err))
+			continue
+		}
+		result.Affected++
+		result.CreatedIDs = append(result.CreatedIDs, copied.ID.Hex())
+	}
+
+	s.refreshDeckCardCounts(targetID)
+	return nil
+}
+
+func (s *Service) bulkTags(author revisionAuthor, action string, tags []string, cards []entities.Flashcard, result *BulkResult) error {
+	var cleaned []string
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			cleaned = append(cleaned, tag)
+		}
+	}
+	if len(cleaned) == 0 {
+		return fmt.Errorf("at least one tag is required")
+	}
+
+	ids := make([]primitive.ObjectID, len(cards))
+	for i, card := range cards {
+		ids[i] = card.ID
+	}
+
+	var modified int64
+	var err error
+	if action == BulkAddTags {
+		modified, err = s.repo.AddFlashcardTags(ids, cleaned)
+	} else {
+		modified, err = s.repo.RemoveFlashcardTags(ids, cleaned)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update tags: %w", err)
+	}
+
+	result.Affected = int(modified)
+	result.Skipped = len(cards) - int(modified)
+
+	// As tags são gravadas de uma vez; o histórico é registrado card a card
+	for _, card := range cards {
+		before := cardContent(&card)
+		if action == BulkAddTags {
+			card.Tags = mergeTags(card.Tags, cleaned)
+		} else {
+			card.Tags = removeTags(card.Tags, cleaned)
+		}
+		s.recordRevision(&card, &before, author, entities.RevisionBulk, "")
+	}
+	return nil
+}
+
+// mergeTags acrescenta as tags que ainda não existem, mantendo a ordem
+func mergeTags(tags, added []string) []string {
+	result := append([]string{}, tags...)
+	for _, tag := range added {
+		exists := false
+		for _, existing := range result {
+			if existing == tag {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+func removeTags(tags, removed []string) []string {
+	var result []string
+	for _, tag := range tags {
+		keep := true
+		for _, r := range removed {
+			if tag == r {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+func (s *Service) bulkReplace(author revisionAuthor, req BulkRequest, cards []entities.Flashcard, result *BulkResult) error {
+	if req.Find == "" {
+		return fmt.Errorf("find text is required")
+	}
+
+	for _, card := range cards {
+		question := replaceText(card.Question, req.Find, req.Replace, req.CaseSensitive)
+		answer := replaceText(card.Answer, req.Find, req.Replace, req.CaseSensitive)
+		if question == card.Question && answer == card.Answer {
+			result.Skipped++
+			continue
+		}
+		if strings.TrimSpace(question) == "" {
+			result.Errors = append(result.Errors, fmt.Sprintf("card %s: question would be empty", card.ID.Hex()))
+			continue
+		}
+
+		before := cardContent(&card)
+		card.Question = question
+		card.Answer = answer
+		if err := s.repo.Update(context.Background(), &card); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("card %s: %v", card.ID.Hex(), err))
+			continue
+		}
+		s.recordRevision(&card, &before, author, entities.RevisionBulk, "")
+		result.Affected++
+	}
+	return nil
+}
+
+// bulkDelete move os cards para a lixeira
+func (s *Service) bulkDelete(cards []entities.Flashcard, result *BulkResult) error {
+	now := time.Now()
+	var deckIDs []string
+	for _, card := range cards {
+		if err := s.moveCardToTrash(&card, now); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("card %s: %v", card.ID.Hex(), err))
+			continue
+		}
+		result.Affected++
+		deckIDs = append(deckIDs, card.DeckID)
+	}
+	s.refreshDeckCardCounts(deckIDs...)
+	return nil
+}
+
+// bulkSuspend tira os cards das revisões ou os devolve a elas
+func (s *Service) bulkSuspend(suspended bool, cards []entities.Flashcard, result *BulkResult) error {
+	var ids []primitive.ObjectID
+	for _, card := range cards {
+		if card.Suspended == suspended {
+			result.Skipped++
+			continue
+		}
+		ids = append(ids, card.ID)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	affected, err := s.repo.SetFlashcardsSuspended(ids, suspended)
+	if err != nil {
+		return fmt.Errorf("failed to update cards: %w", err)
+	}
+	result.Affected = int(affected)
+	return nil
+}
+
+// refreshDeckCardCounts regrava o cardCount dos decks afetados
+func (s *Service) refreshDeckCardCounts(deckIDs ...string) {
+	seen := make(map[string]bool)
+	for _, deckID := range deckIDs {
+		if seen[deckID] {
+			continue
+		}
+		seen[deckID] = true
+		if err := s.repo.RefreshDeckCardCount(deckID); err != nil {
+			fmt.Printf("Failed to refresh card count for deck %s: %v\n", deckID, err)
+		}
+	}
+}
+
+// replaceText troca todas as ocorrências de find, opcionalmente ignorando
+// maiúsculas e minúsculas
+func replaceText(text, find, replace string, caseSensitive bool) string {
+	if caseSensitive {
+		return strings.ReplaceAll(text, find, replace)
+	}
+	return regexp.MustCompile("(?i)"+regexp.QuoteMeta(find)).ReplaceAllLiteralString(text, replace)
+}
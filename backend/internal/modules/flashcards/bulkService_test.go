@@ -0,0 +1,95 @@
+package flashcards
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// cardLimitAdmin reproduz a regra do admin: a contagem informada já inclui o
+// último card que será adicionado
+type cardLimitAdmin struct {
+	limit int
+}
+
+func (a cardLimitAdmin) ValidateDeckLimit(ctx context.Context, userPlan string, currentDeckCount int, isPublic bool) error {
+	return nil
+}
+
+func (a cardLimitAdmin) ValidateCardLimit(ctx context.Context, userPlan string, currentCardCount int) error {
+	if currentCardCount >= a.limit {
+		return fmt.Errorf("card limit of %d reached", a.limit)
+	}
+	return nil
+}
+
+func (a cardLimitAdmin) ValidatePublicDeckLimit(ctx context.Context, userPlan string, currentPublicDeckCount int) error {
+	return nil
+}
+
+func (a cardLimitAdmin) ValidatePublicCardLimit(ctx context.Context, userPlan string, currentPublicCardCount int) error {
+	return nil
+}
+
+func TestReplaceText(t *testing.T) {
+	assert.Equal(t, "Mitocôndria e mitocôndria", replaceText("Mitocondria e mitocôndria", "Mitocondria", "Mitocôndria", true))
+	assert.Equal(t, "ATP, ATP e ATP", replaceText("atp, ATP e Atp", "atp", "ATP", false))
+	// Metacaracteres são tratados como texto, inclusive na substituição
+	assert.Equal(t, "custo: $1", replaceText("custo: (x)", "(x)", "$1", false))
+	assert.Equal(t, "sem troca", replaceText("sem troca", "ATP", "ADP", false))
+}
+
+func TestBulkReplaceSkipsUnchangedCards(t *testing.T) {
+	unchanged := entities.Flashcard{ID: primitive.NewObjectID(), Question: "O que é ATP?", Answer: "Energia"}
+	// Só difere em maiúsculas: com busca sensível a elas não há o que trocar
+	otherCase := entities.Flashcard{ID: primitive.NewObjectID(), Question: "Função da glicose", Answer: "Energia"}
+	emptied := entities.Flashcard{ID: primitive.NewObjectID(), Question: "Glicose", Answer: "Açúcar"}
+
+	s := &Service{}
+	result := &BulkResult{}
+	err := s.bulkReplace(revisionAuthor{}, BulkRequest{Find: "Glicose", CaseSensitive: true},
+		[]entities.Flashcard{unchanged, otherCase, emptied}, result)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.Skipped)
+	assert.Equal(t, 0, result.Affected)
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0], "question would be empty")
+
+	assert.Error(t, s.bulkReplace(revisionAuthor{}, BulkRequest{}, []entities.Flashcard{unchanged}, &BulkResult{}))
+}
+
+func TestMergeAndRemoveTags(t *testing.T) {
+	assert.Equal(t, []string{"bio", "enem", "celula"}, mergeTags([]string{"bio", "enem"}, []string{"enem", "celula"}))
+	assert.Equal(t, []string{"bio"}, removeTags([]string{"bio", "enem", "celula"}, []string{"enem", "celula"}))
+	assert.Nil(t, removeTags([]string{"enem"}, []string{"enem"}))
+}
+
+func TestBulkCopyAndMoveCardCapacity(t *testing.T) {
+	s := &Service{adminService: cardLimitAdmin{limit: 10}}
+
+	// Cópia: o destino com 8 cards aceita mais 2, mas não 3
+	assert.NoError(t, s.validateCardCapacity("user", 8, 2))
+	assert.Error(t, s.validateCardCapacity("user", 8, 3))
+
+	// Movimento: cards que já estão no destino não contam para o limite
+	target := primitive.NewObjectID().Hex()
+	source := primitive.NewObjectID().Hex()
+	cards := []entities.Flashcard{
+		{ID: primitive.NewObjectID(), DeckID: target},
+		{ID: primitive.NewObjectID(), DeckID: target},
+		{ID: primitive.NewObjectID(), DeckID: source},
+	}
+	moving, skipped := cardsToMove(cards, target)
+	require.Len(t, moving, 1)
+	assert.Equal(t, source, moving[0].DeckID)
+	assert.Equal(t, 2, skipped)
+	assert.NoError(t, s.validateCardCapacity("user", 9, len(moving)))
+	assert.Error(t, s.validateCardCapacity("user", 9, len(cards)))
+}
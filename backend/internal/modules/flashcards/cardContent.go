@@ -0,0 +1,104 @@
+package flashcards
+
+import (
+	"reflect"
+
+	"flashcard-backend/internal/domain/entities"
+)
+
+// cardContentFields descreve, campo a campo, como comparar e copiar o
+// conteúdo de um card. Os nomes seguem o JSON da API.
+var cardContentFields = []struct {
+	name string
+	get  func(c *entities.CardContent) interface{}
+	copy func(dst, src *entities.CardContent)
+}{
+	{"question",
+		func(c *entities.CardContent) interface{} { return c.Question },
+		func(dst, src *entities.CardContent) { dst.Question = src.Question }},
+	{"answer",
+		func(c *entities.CardContent) interface{} { return c.Answer },
+		func(dst, src *entities.CardContent) { dst.Answer = src.Answer }},
+	{"alternatives",
+		func(c *entities.CardContent) interface{} { return c.Alternatives },
+		func(dst, src *entities.CardContent) { dst.Alternatives = src.Alternatives }},
+	{"correctAlternative",
+		func(c *entities.CardContent) interface{} { return c.CorrectAlternative },
+		func(dst, src *entities.CardContent) { dst.CorrectAlternative = src.CorrectAlternative }},
+	{"image_url",
+		func(c *entities.CardContent) interface{} { return c.ImageURL },
+		func(dst, src *entities.CardContent) { dst.ImageURL = src.ImageURL }},
+	{"audio_url",
+		func(c *entities.CardContent) interface{} { return c.AudioURL },
+		func(dst, src *entities.CardContent) { dst.AudioURL = src.AudioURL }},
+	{"tags",
+		func(c *entities.CardContent) interface{} { return c.Tags },
+		func(dst, src *entities.CardContent) { dst.Tags = src.Tags }},
+}
+
+// cardContent extrai o conteúdo do card normalizando vazios, para que
+// nil e "" (ou lista vazia) sejam considerados iguais
+func cardContent(card *entities.Flashcard) entities.CardContent {
+	content := entities.CardContent{
+		Question:           card.Question,
+		Answer:             card.Answer,
+		CorrectAlternative: card.CorrectAlternative,
+	}
+	if len(card.Alternatives) > 0 {
+		content.Alternatives = card.Alternatives
+	}
+	if len(card.Tags) > 0 {
+		content.Tags = card.Tags
+	}
+	if card.ImageURL != nil {
+		content.ImageURL = *card.ImageURL
+	}
+	if card.AudioURL != nil {
+		content.AudioURL = *card.AudioURL
+	}
+	return content
+}
+
+// applyCardContent grava o conteúdo no card sem mexer no estado de revisão
+func applyCardContent(card *entities.Flashcard, content entities.CardContent) {
+	card.Question = content.Question
+	card.Answer = content.Answer
+	card.Alternatives = content.Alternatives
+	card.CorrectAlternative = content.CorrectAlternative
+	card.ImageURL = &content.ImageURL
+	card.AudioURL = &content.AudioURL
+	card.Tags = content.Tags
+}
+
+// diffCardContent lista os campos diferentes entre dois conteúdos
+func diffCardContent(a, b entities.CardContent) []string {
+	var fields []string
+	for _, field := range cardContentFields {
+		if !reflect.DeepEqual(field.get(&a), field.get(&b)) {
+			fields = append(fields, field.name)
+		}
+	}
+	return fields
+}
+
+// mergeCardContent faz o merge de três vias: campos alterados só no upstream
+// são aplicados; campos alterados localmente são mantidos e, se o upstream
+// também mudou para outro valor, voltam como conflito
+func mergeCardContent(base, local, upstream entities.CardContent) (merged entities.CardContent, applied, conflicts []string) {
+	merged = local
+	for _, field := range cardContentFields {
+		baseValue, localValue, upstreamValue := field.get(&base), field.get(&local), field.get(&upstream)
+		upstreamChanged := !reflect.DeepEqual(baseValue, upstreamValue)
+		localChanged := !reflect.DeepEqual(baseValue, localValue)
+
+		switch {
+		case !upstreamChanged:
+		case !localChanged:
+			field.copy(&merged, &upstream)
+			applied = append(applied, field.name)
+		case !reflect.DeepEqual(localValue, upstreamValue):
+			conflicts = append(conflicts, field.name)
+		}
+	}
+	return merged, applied, conflicts
+}
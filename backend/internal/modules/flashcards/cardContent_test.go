@@ -0,0 +1,35 @@
+package flashcards
+
+import (
+	"testing"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeCardContent(t *testing.T) {
+	base := entities.CardContent{Question: "Capital da Austrália?", Answer: "Sydney", Tags: []string{"geo"}}
+	local := entities.CardContent{Question: "Qual a capital da Austrália?", Answer: "Sydney", Tags: []string{"geo"}}
+	upstream := entities.CardContent{Question: "Capital da Austrália?", Answer: "Canberra", Tags: []string{"geo", "oceania"}}
+
+	merged, applied, conflicts := mergeCardContent(base, local, upstream)
+
+	assert.Equal(t, "Qual a capital da Austrália?", merged.Question, "edição local deve ser mantida")
+	assert.Equal(t, "Canberra", merged.Answer)
+	assert.Equal(t, []string{"geo", "oceania"}, merged.Tags)
+	assert.Equal(t, []string{"answer", "tags"}, applied)
+	assert.Empty(t, conflicts)
+
+	upstream.Question = "Capital australiana?"
+	_, _, conflicts = mergeCardContent(base, local, upstream)
+	assert.Equal(t, []string{"question"}, conflicts)
+}
+
+func TestCardContentTreatsEmptyAsMissing(t *testing.T) {
+	empty := ""
+	withPointers := entities.Flashcard{Question: "Q", ImageURL: &empty, Tags: []string{}}
+	withoutPointers := entities.Flashcard{Question: "Q"}
+
+	assert.Empty(t, diffCardContent(cardContent(&withPointers), cardContent(&withoutPointers)))
+}
@@ -0,0 +1,161 @@
+package flashcards
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"flashcard-backend/internal/domain/entities"
+)
+
+// Severidade dos problemas encontrados pelas regras de qualidade
+const (
+	LintError   = "error"   // o card não funciona como esperado no estudo
+	LintWarning = "warning" // o card funciona, mas provavelmente está errado
+	LintInfo    = "info"    // vale revisar, sem nada errado no card
+)
+
+// Regras de qualidade dos cards
+const (
+	LintEmptyQuestion     = "empty_question"
+	LintEmptyAnswer       = "empty_answer"
+	LintCorrectOutOfRange = "correct_alternative_out_of_range"
+	LintDuplicateOptions  = "duplicate_alternatives"
+	LintLongQuestion      = "long_question"
+	LintInvalidMediaURL   = "invalid_media_url"
+	LintBrokenMediaLink   = "broken_media_link"
+	LintUnbalancedCloze   = "unbalanced_cloze"
+	LintNeverReviewed     = "never_reviewed"
+)
+
+const (
+	// maxLintQuestionLength é o tamanho a partir do qual a pergunta é
+	// considerada longa demais para ser lembrada de uma vez, em caracteres
+	maxLintQuestionLength = 400
+	// neverReviewedMonths é quanto tempo um card pode ficar sem nenhuma
+	// revisão antes de ser apontado
+	neverReviewedMonths = 6
+)
+
+var clozeOpening = regexp.MustCompile(`^\{\{c[0-9]+::`)
+
+// CardLintIssue é um problema encontrado num card
+type CardLintIssue struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Field    string `json:"field,omitempty"`
+	Message  string `json:"message"`
+}
+
+// lintCard aplica as regras que dependem só do conteúdo do card. A checagem
+// de links quebrados, que faz requisições, fica de fora.
+func lintCard(card *entities.Flashcard, now time.Time) []CardLintIssue {
+	var issues []CardLintIssue
+	add := func(rule, severity, field, message string) {
+		issues = append(issues, CardLintIssue{Rule: rule, Severity: severity, Field: field, Message: message})
+	}
+
+	if strings.TrimSpace(card.Question) == "" {
+		add(LintEmptyQuestion, LintError, "question", "question is empty")
+	}
+	if len(card.Alternatives) == 0 {
+		if strings.TrimSpace(card.Answer) == "" {
+			add(LintEmptyAnswer, LintError, "answer", "answer is empty")
+		}
+	} else {
+		if card.CorrectAlternative == nil || *card.CorrectAlternative < 0 || *card.CorrectAlternative >= len(card.Alternatives) {
+			add(LintCorrectOutOfRange, LintError, "correctAlternative",
+				fmt.Sprintf("correct alternative must point to one of the %d alternatives", len(card.Alternatives)))
+		}
+		seen := make(map[string]bool, len(card.Alternatives))
+		for _, alternative := range card.Alternatives {
+			normalized := normalizeCardText(alternative)
+			if seen[normalized] {
+				add(LintDuplicateOptions, LintWarning, "alternatives", fmt.Sprintf("alternative %q appears more than once", alternative))
+				break
+			}
+			seen[normalized] = true
+		}
+	}
+
+	if length := utf8.RuneCountInString(card.Question); length > maxLintQuestionLength {
+		add(LintLongQuestion, LintWarning, "question",
+			fmt.Sprintf("question has %d characters; consider splitting it (limit %d)", length, maxLintQuestionLength))
+	}
+
+	for _, media := range []struct {
+		field string
+		url   *string
+	}{{"image_url", card.ImageURL}, {"audio_url", card.AudioURL}} {
+		if media.url != nil && *media.url != "" && !isValidMediaURL(*media.url) {
+			add(LintInvalidMediaURL, LintError, media.field, fmt.Sprintf("%s is not a valid http(s) URL", media.field))
+		}
+	}
+
+	for _, field := range []struct{ name, text string }{{"question", card.Question}, {"answer", card.Answer}} {
+		if problem := clozeProblem(field.text); problem != "" {
+			add(LintUnbalancedCloze, LintError, field.name, problem)
+		}
+	}
+
+	if card.LastReviewed == nil && card.ReviewCount == 0 && !card.CreatedAt.IsZero() &&
+		card.CreatedAt.Before(now.AddDate(0, -neverReviewedMonths, 0)) {
+		add(LintNeverReviewed, LintInfo, "", fmt.Sprintf("card was created %s and never reviewed", card.CreatedAt.Format("2006-01-02")))
+	}
+	return issues
+}
+
+func isValidMediaURL(raw string) bool {
+	parsed, err := url.Parse(raw)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// clozeAnywhere acha um {{c<n>:: em qualquer ponto do texto
+var clozeAnywhere = regexp.MustCompile(`\{\{c[0-9]+::`)
+
+// clozeProblem confere a sintaxe de cloze ({{c1::texto}} ou
+// {{c1::texto::dica}}, que podem ser aninhados) e descreve o primeiro
+// problema, ou retorna "" se estiver correta. Textos sem nenhum {{c<n>::
+// não são cloze (código, JSON) e não são checados.
+func clozeProblem(text string) string {
+	if !clozeAnywhere.MatchString(text) {
+		return ""
+	}
+	var open []int
+	for i := 0; i < len(text); {
+		switch {
+		case strings.HasPrefix(text[i:], "{{"):
+			if !clozeOpening.MatchString(text[i:]) {
+				return fmt.Sprintf("cloze at position %d must start with {{c1::", utf8.RuneCountInString(text[:i])+1)
+			}
+			open = append(open, i)
+			i += 2
+		case strings.HasPrefix(text[i:], "}}"):
+			if len(open) == 0 {
+				return fmt.Sprintf("closing }} at position %d has no matching {{", utf8.RuneCountInString(text[:i])+1)
+			}
+			open = open[:len(open)-1]
+			i += 2
+		default:
+			i++
+		}
+	}
+	if len(open) > 0 {
+		return fmt.Sprintf("cloze opened at position %d is never closed", utf8.RuneCountInString(text[:open[0]])+1)
+	}
+	return ""
+}
+
+// lintErrors separa os problemas que bloqueiam a gravação
+func lintErrors(issues []CardLintIssue) []CardLintIssue {
+	var errs []CardLintIssue
+	for _, issue := range issues {
+		if issue.Severity == LintError {
+			errs = append(errs, issue)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,65 @@
+package flashcards
+
+import (
+	"testing"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func lintRules(issues []CardLintIssue) []string {
+	rules := []string{}
+	for _, issue := range issues {
+		rules = append(rules, issue.Rule)
+	}
+	return rules
+}
+
+func TestLintCard(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	three := 3
+	image := "ftp://arquivos/figura.png"
+
+	issues := lintCard(&entities.Flashcard{
+		Question:           "Qual alternativa?",
+		Alternatives:       []string{"Paris", "paris ", "Roma"},
+		CorrectAlternative: &three,
+		ImageURL:           &image,
+		CreatedAt:          now.AddDate(-1, 0, 0),
+	}, now)
+	assert.ElementsMatch(t, []string{LintCorrectOutOfRange, LintDuplicateOptions, LintInvalidMediaURL, LintNeverReviewed}, lintRules(issues))
+	assert.Len(t, lintErrors(issues), 2)
+
+	reviewed := now
+	issues = lintCard(&entities.Flashcard{Question: "A {{c1::mitocôndria}} produz ATP", Answer: "", LastReviewed: &reviewed, CreatedAt: now.AddDate(-1, 0, 0)}, now)
+	assert.Equal(t, []string{LintEmptyAnswer}, lintRules(issues))
+
+	issues = lintCard(&entities.Flashcard{Question: "Capital da França?", Answer: "Paris", CreatedAt: now}, now)
+	assert.Empty(t, issues)
+}
+
+func TestClozeProblem(t *testing.T) {
+	assert.Empty(t, clozeProblem("A {{c1::mitocôndria::organela}} produz {{c2::ATP}}"))
+	assert.Empty(t, clozeProblem("{{c1::Canberra {{c2::Austrália}}}}"))
+	assert.Contains(t, clozeProblem("A {{c1::mitocôndria produz ATP"), "never closed")
+	assert.Contains(t, clozeProblem("A mitocôndria}} produz {{c1::ATP}}"), "position 14")
+	assert.Contains(t, clozeProblem("A {{mitocôndria}} produz {{c1::ATP}}"), "must start with")
+
+	// Chaves que não são cloze não são checadas
+	assert.Empty(t, clozeProblem("if x {y()}}"))
+	assert.Empty(t, clozeProblem(`{"a":{"b":1}}`))
+	assert.Empty(t, clozeProblem("Use {{nome}} no template"))
+}
+
+func TestCheckMediaLinksSkipsOtherHosts(t *testing.T) {
+	internal := "http://169.254.169.254/latest/meta-data/iam"
+	external := "https://example.com/figura.png"
+	broken, notChecked := checkMediaLinks([]entities.Flashcard{
+		{ImageURL: &internal, AudioURL: &external},
+		{ImageURL: &external},
+	}, []string{"bucket.s3.sa-east-1.amazonaws.com"})
+	assert.Empty(t, broken)
+	assert.Equal(t, 2, notChecked)
+}
@@ -0,0 +1,61 @@
+package flashcards
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FindDuplicates procura cards repetidos ou quase iguais
+// GET /api/cards/duplicates?deck_id=...&threshold=0.85
+func (h *Handler) FindDuplicates(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var threshold float64
+	if value := c.Query("threshold"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid threshold"})
+			return
+		}
+		threshold = parsed
+	}
+
+	report, err := h.service.FindDuplicates(userID, c.Query("deck_id"), threshold)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// MergeDuplicates mantém um card e manda as duplicatas para a lixeira
+// POST /api/cards/duplicates/merge
+func (h *Handler) MergeDuplicates(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		KeepID    string   `json:"keep_id" binding:"required"`
+		RemoveIDs []string `json:"remove_ids" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.MergeDuplicates(userID, req.KeepID, req.RemoveIDs)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
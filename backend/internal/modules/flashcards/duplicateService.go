@@ -0,0 +1,452 @@
+package flashcards
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tipos de grupo de duplicatas
+const (
+	DuplicateExact = "exact" // mesmo texto depois de normalizado
+	DuplicateNear  = "near"  // texto parecido, acima do limite de similaridade
+)
+
+const (
+	// defaultDuplicateThreshold é a similaridade mínima para dois cards
+	// serem considerados quase iguais
+	defaultDuplicateThreshold = 0.85
+	minDuplicateThreshold     = 0.5
+	// maxDuplicateScanCards limita a varredura, que compara os cards dois a dois
+	maxDuplicateScanCards = 3000
+	// maxDuplicateWarnings limita os avisos ao criar um card
+	maxDuplicateWarnings = 5
+	// questionWeight é o peso da pergunta na similaridade; o resto é da resposta
+	questionWeight = 0.7
+)
+
+// DuplicateCard é um card de um grupo de duplicatas
+type DuplicateCard struct {
+	Card     entities.Flashcard `json:"card"`
+	DeckName string             `json:"deck_name"`
+}
+
+// DuplicateMerge é a ação sugerida para um grupo: manter um card, juntar as
+// tags dos demais nele e mandar os demais para a lixeira
+type DuplicateMerge struct {
+	KeepID    string   `json:"keep_id"`
+	RemoveIDs []string `json:"remove_ids"`
+	Tags      []string `json:"tags,omitempty"`
+}
+
+// DuplicateCluster é um grupo de cards repetidos ou quase iguais
+type DuplicateCluster struct {
+	Kind       string          `json:"kind"`
+	Similarity float64         `json:"similarity"` // a menor similaridade entre os pares que formaram o grupo
+	Cards      []DuplicateCard `json:"cards"`
+	Suggestion DuplicateMerge  `json:"suggestion"`
+}
+
+// DuplicateReport é o resultado de uma varredura
+type DuplicateReport struct {
+	Scanned   int                `json:"scanned"`
+	Threshold float64            `json:"threshold"`
+	Clusters  []DuplicateCluster `json:"clusters"`
+}
+
+// DuplicateMatch é um card parecido com um card recém-criado
+type DuplicateMatch struct {
+	CardID     string  `json:"card_id"`
+	Question   string  `json:"question"`
+	Similarity float64 `json:"similarity"`
+	Exact      bool    `json:"exact,omitempty"`
+}
+
+// MergeResult resume a junção de duplicatas
+type MergeResult struct {
+	Kept    entities.Flashcard `json:"kept"`
+	Removed int                `json:"removed"`
+	Errors  []string           `json:"errors,omitempty"`
+}
+
+// normalizeCardText reduz o texto às palavras, sem acentos, maiúsculas,
+// pontuação ou espaços repetidos
+func normalizeCardText(text string) string {
+	runes := []rune(text)
+	words := splitWords(runes)
+	parts := make([]string, len(words))
+	for i, word := range words {
+		parts[i] = foldWord(runes[word.start:word.end])
+	}
+	return strings.Join(parts, " ")
+}
+
+// cardAnswerText é a resposta do card; nos de múltipla escolha, o texto da
+// alternativa correta
+func cardAnswerText(card *entities.Flashcard) string {
+	if card.CorrectAlternative != nil {
+		if i := *card.CorrectAlternative; i >= 0 && i < len(card.Alternatives) {
+			return card.Alternatives[i]
+		}
+	}
+	return card.Answer
+}
+
+// textBigrams conta os pares de caracteres de um texto normalizado
+type textBigrams struct {
+	text  string
+	grams map[string]int
+	size  int
+}
+
+func newTextBigrams(text string) textBigrams {
+	runes := []rune(text)
+	b := textBigrams{text: text, grams: make(map[string]int)}
+	for i := 0; i+1 < len(runes); i++ {
+		b.grams[string(runes[i:i+2])]++
+		b.size++
+	}
+	return b
+}
+
+// diceSimilarity é o coeficiente de Sørensen-Dice entre os pares de
+// caracteres dos dois textos: 1 para textos iguais, 0 sem nada em comum
+func diceSimilarity(a, b textBigrams) float64 {
+	if a.text == b.text {
+		return 1
+	}
+	if a.size == 0 || b.size == 0 {
+		return 0
+	}
+	small, large := a, b
+	if len(small.grams) > len(large.grams) {
+		small, large = large, small
+	}
+	shared := 0
+	for gram, count := range small.grams {
+		if other := large.grams[gram]; other < count {
+			shared += other
+		} else {
+			shared += count
+		}
+	}
+	return 2 * float64(shared) / float64(a.size+b.size)
+}
+
+// cardFingerprint é o texto normalizado de um card, pronto para comparar
+type cardFingerprint struct {
+	question textBigrams
+	answer   textBigrams
+}
+
+func newCardFingerprint(card *entities.Flashcard) cardFingerprint {
+	return cardFingerprint{
+		question: newTextBigrams(normalizeCardText(card.Question)),
+		answer:   newTextBigrams(normalizeCardText(cardAnswerText(card))),
+	}
+}
+
+func (f cardFingerprint) exact(other cardFingerprint) bool {
+	return f.question.text == other.question.text && f.answer.text == other.answer.text
+}
+
+// similarity combina pergunta e resposta; sem resposta nos dois cards, só a
+// pergunta conta
+func (f cardFingerprint) similarity(other cardFingerprint) float64 {
+	question := diceSimilarity(f.question, other.question)
+	if f.answer.text == "" && other.answer.text == "" {
+		return question
+	}
+	return questionWeight*question + (1-questionWeight)*diceSimilarity(f.answer, other.answer)
+}
+
+// maxSimilarity é o teto da similarity pelo tamanho das perguntas, usado
+// para descartar pares sem calcular
+func (f cardFingerprint) maxSimilarity(other cardFingerprint) float64 {
+	a, b := f.question.size, other.question.size
+	if a == 0 && b == 0 {
+		return 1
+	}
+	if a > b {
+		a, b = b, a
+	}
+	return questionWeight*2*float64(a)/float64(a+b) + (1 - questionWeight)
+}
+
+// findDuplicateClusters agrupa os cards cuja similaridade passa do limite.
+// Pares ligam cards num mesmo grupo, então um grupo pode juntar cards que só
+// se parecem por meio de um terceiro.
+func findDuplicateClusters(cards []entities.Flashcard, threshold float64) []DuplicateCluster {
+	prints := make([]cardFingerprint, len(cards))
+	for i := range cards {
+		prints[i] = newCardFingerprint(&cards[i])
+	}
+	// Em ordem de tamanho da pergunta, o laço interno para quando o teto da
+	// similaridade fica abaixo do limite
+	order := make([]int, len(cards))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		return prints[order[a]].question.size < prints[order[b]].question.size
+	})
+
+	parent := make([]int, len(cards))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	lowest := make(map[int]float64)
+	allExact := make(map[int]bool)
+	type edge struct {
+		a, b  int
+		score float64
+		exact bool
+	}
+	var edges []edge
+	for x := 0; x < len(order); x++ {
+		i := order[x]
+		for y := x + 1; y < len(order); y++ {
+			j := order[y]
+			if prints[i].maxSimilarity(prints[j]) < threshold {
+				break
+			}
+			if prints[i].exact(prints[j]) {
+				edges = append(edges, edge{i, j, 1, true})
+				continue
+			}
+			if score := prints[i].similarity(prints[j]); score >= threshold {
+				edges = append(edges, edge{i, j, score, false})
+			}
+		}
+	}
+	for _, e := range edges {
+		parent[find(e.a)] = find(e.b)
+	}
+	for _, e := range edges {
+		root := find(e.a)
+		if current, ok := lowest[root]; !ok || e.score < current {
+			lowest[root] = e.score
+		}
+		if exact, ok := allExact[root]; !ok {
+			allExact[root] = e.exact
+		} else {
+			allExact[root] = exact && e.exact
+		}
+	}
+
+	groups := make(map[int][]int)
+	for i := range cards {
+		root := find(i)
+		if _, ok := lowest[root]; ok {
+			groups[root] = append(groups[root], i)
+		}
+	}
+
+	clusters := make([]DuplicateCluster, 0, len(groups))
+	for root, members := range groups {
+		cluster := DuplicateCluster{Kind: DuplicateNear, Similarity: roundSimilarity(lowest[root])}
+		if allExact[root] {
+			cluster.Kind = DuplicateExact
+		}
+		for _, i := range members {
+			cluster.Cards = append(cluster.Cards, DuplicateCard{Card: cards[i]})
+		}
+		cluster.Suggestion = suggestDuplicateMerge(cluster.Cards)
+		clusters = append(clusters, cluster)
+	}
+	sort.Slice(clusters, func(a, b int) bool {
+		if len(clusters[a].Cards) != len(clusters[b].Cards) {
+			return len(clusters[a].Cards) > len(clusters[b].Cards)
+		}
+		if clusters[a].Similarity != clusters[b].Similarity {
+			return clusters[a].Similarity > clusters[b].Similarity
+		}
+		return clusters[a].Suggestion.KeepID < clusters[b].Suggestion.KeepID
+	})
+	return clusters
+}
+
+// suggestDuplicateMerge mantém o card com mais revisões (o progresso de
+// estudo não se perde) e, no empate, o mais antigo
+func suggestDuplicateMerge(cards []DuplicateCard) DuplicateMerge {
+	sort.SliceStable(cards, func(a, b int) bool {
+		x, y := cards[a].Card, cards[b].Card
+		if x.ReviewCount != y.ReviewCount {
+			return x.ReviewCount > y.ReviewCount
+		}
+		return x.CreatedAt.Before(y.CreatedAt)
+	})
+	merge := DuplicateMerge{KeepID: cards[0].Card.ID.Hex(), Tags: cards[0].Card.Tags}
+	for _, card := range cards[1:] {
+		merge.RemoveIDs = append(merge.RemoveIDs, card.Card.ID.Hex())
+		merge.Tags = mergeTags(merge.Tags, card.Card.Tags)
+	}
+	return merge
+}
+
+func roundSimilarity(score float64) float64 {
+	return float64(int(score*1000+0.5)) / 1000
+}
+
+// FindDuplicates procura cards repetidos nos decks do usuário, ou só no deck
+// informado e seus subdecks
+func (s *Service) FindDuplicates(userID, deckID string, threshold float64) (*DuplicateReport, error) {
+	if threshold == 0 {
+		threshold = defaultDuplicateThreshold
+	}
+	if threshold < minDuplicateThreshold || threshold > 1 {
+		return nil, fmt.Errorf("threshold must be between %.1f and 1", minDuplicateThreshold)
+	}
+
+	decks, err := s.repo.GetDecksByUserEmail(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get decks: %w", err)
+	}
+	deckIDs, err := searchDeckIDs(decks, deckID)
+	if err != nil {
+		return nil, err
+	}
+	deckNames := make(map[string]string, len(decks))
+	for _, deck := range decks {
+		deckNames[deck.ID.Hex()] = deck.Name
+	}
+
+	cards, err := s.repo.FindFlashcards(deckIDs, maxDuplicateScanCards+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cards: %w", err)
+	}
+	if len(cards) > maxDuplicateScanCards {
+		return nil, fmt.Errorf("too many cards to scan: the limit is %d, pick a deck", maxDuplicateScanCards)
+	}
+
+	clusters := findDuplicateClusters(cards, threshold)
+	for i := range clusters {
+		for j := range clusters[i].Cards {
+			clusters[i].Cards[j].DeckName = deckNames[clusters[i].Cards[j].Card.DeckID]
+		}
+	}
+	return &DuplicateReport{Scanned: len(cards), Threshold: threshold, Clusters: clusters}, nil
+}
+
+// FindSimilarCards lista os cards do mesmo deck parecidos com o card, do
+// mais para o menos parecido. Serve de aviso ao criar um card.
+func (s *Service) FindSimilarCards(card *entities.Flashcard) ([]DuplicateMatch, error) {
+	cards, err := s.repo.FindFlashcards([]string{card.DeckID}, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cards: %w", err)
+	}
+
+	fingerprint := newCardFingerprint(card)
+	matches := []DuplicateMatch{}
+	for i := range cards {
+		if cards[i].ID == card.ID {
+			continue
+		}
+		other := newCardFingerprint(&cards[i])
+		if fingerprint.maxSimilarity(other) < defaultDuplicateThreshold {
+			continue
+		}
+		exact := fingerprint.exact(other)
+		score := 1.0
+		if !exact {
+			score = fingerprint.similarity(other)
+		}
+		if score >= defaultDuplicateThreshold {
+			matches = append(matches, DuplicateMatch{
+				CardID:     cards[i].ID.Hex(),
+				Question:   cards[i].Question,
+				Similarity: roundSimilarity(score),
+				Exact:      exact,
+			})
+		}
+	}
+	sort.SliceStable(matches, func(a, b int) bool { return matches[a].Similarity > matches[b].Similarity })
+	if len(matches) > maxDuplicateWarnings {
+		matches = matches[:maxDuplicateWarnings]
+	}
+	return matches, nil
+}
+
+// MergeDuplicates junta as tags dos cards no card mantido e manda os demais
+// para a lixeira, de onde ainda podem ser restaurados
+func (s *Service) MergeDuplicates(userID, keepID string, removeIDs []string) (*MergeResult, error) {
+	if len(removeIDs) == 0 {
+		return nil, fmt.Errorf("remove_ids is required")
+	}
+	if len(removeIDs) > maxBulkCards {
+		return nil, fmt.Errorf("too many cards: the limit is %d per operation", maxBulkCards)
+	}
+
+	keep, err := s.repo.GetByID(context.Background(), keepID)
+	if err != nil {
+		return nil, ErrCardNotFound
+	}
+	if _, err := s.getEditableDeck(userID, keep.DeckID); err != nil {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, 0, len(removeIDs))
+	for _, id := range removeIDs {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid card ID: %s", id)
+		}
+		if objectID == keep.ID {
+			return nil, fmt.Errorf("the kept card cannot also be removed")
+		}
+		ids = append(ids, objectID)
+	}
+	removed, err := s.repo.GetFlashcardsByIDs(ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cards: %w", err)
+	}
+	if len(removed) != len(ids) {
+		return nil, ErrCardNotFound
+	}
+	for _, card := range removed {
+		if _, err := s.getEditableDeck(userID, card.DeckID); err != nil {
+			return nil, err
+		}
+	}
+
+	var tags []string
+	for _, card := range removed {
+		tags = mergeTags(tags, card.Tags)
+	}
+	if merged := mergeTags(keep.Tags, tags); len(merged) > len(keep.Tags) {
+		before := cardContent(keep)
+		if _, err := s.repo.AddFlashcardTags([]primitive.ObjectID{keep.ID}, tags); err != nil {
+			return nil, fmt.Errorf("failed to update tags: %w", err)
+		}
+		keep.Tags = merged
+		s.recordRevision(keep, &before, s.revisionAuthorFor(userID), entities.RevisionMerge, "")
+	}
+
+	result := &MergeResult{}
+	now := time.Now()
+	deckIDs := []string{keep.DeckID}
+	for _, card := range removed {
+		if err := s.moveCardToTrash(&card, now); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("card %s: %v", card.ID.Hex(), err))
+			continue
+		}
+		result.Removed++
+		deckIDs = append(deckIDs, card.DeckID)
+	}
+	s.refreshDeckCardCounts(deckIDs...)
+	result.Kept = *keep
+	return result, nil
+}
@@ -0,0 +1,64 @@
+package flashcards
+
+import (
+	"testing"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestNormalizeCardText(t *testing.T) {
+	assert.Equal(t, "qual e a funcao do coracao", normalizeCardText("  Qual é a função do  CORAÇÃO? "))
+}
+
+func TestCardSimilarity(t *testing.T) {
+	a := newCardFingerprint(&entities.Flashcard{Question: "Qual é a função do coração?", Answer: "Bombear sangue"})
+	b := newCardFingerprint(&entities.Flashcard{Question: "qual e a funcao do coracao", Answer: "bombear sangue!"})
+	c := newCardFingerprint(&entities.Flashcard{Question: "Qual é a função do coração", Answer: "Bombear o sangue"})
+	d := newCardFingerprint(&entities.Flashcard{Question: "Onde fica o fígado?", Answer: "No abdômen"})
+
+	assert.True(t, a.exact(b))
+	assert.False(t, a.exact(c))
+	assert.Greater(t, a.similarity(c), defaultDuplicateThreshold)
+	assert.Less(t, a.similarity(d), 0.5)
+	assert.GreaterOrEqual(t, a.maxSimilarity(c), a.similarity(c))
+}
+
+func TestFindDuplicateClusters(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	card := func(question, answer string, reviews int, tags ...string) entities.Flashcard {
+		created = created.Add(time.Hour)
+		return entities.Flashcard{ID: primitive.NewObjectID(), Question: question, Answer: answer, ReviewCount: reviews, Tags: tags, CreatedAt: created}
+	}
+	cards := []entities.Flashcard{
+		card("Capital da França?", "Paris", 0, "geo"),
+		card("capital da frança", "paris", 3, "europa"),
+		card("Qual é a função do coração?", "Bombear sangue", 0),
+		card("Qual é a função do coração", "Bombear o sangue", 1),
+		card("Onde fica o fígado?", "No abdômen", 0),
+	}
+
+	clusters := findDuplicateClusters(cards, defaultDuplicateThreshold)
+	assert.Len(t, clusters, 2)
+
+	exact := clusters[0]
+	if exact.Kind != DuplicateExact {
+		exact = clusters[1]
+	}
+	assert.Equal(t, DuplicateExact, exact.Kind)
+	assert.Equal(t, 1.0, exact.Similarity)
+	// Fica o card com mais revisões, com as tags dos dois
+	assert.Equal(t, cards[1].ID.Hex(), exact.Suggestion.KeepID)
+	assert.Equal(t, []string{cards[0].ID.Hex()}, exact.Suggestion.RemoveIDs)
+	assert.ElementsMatch(t, []string{"geo", "europa"}, exact.Suggestion.Tags)
+
+	near := clusters[0]
+	if near.Kind != DuplicateNear {
+		near = clusters[1]
+	}
+	assert.Equal(t, DuplicateNear, near.Kind)
+	assert.Equal(t, cards[3].ID.Hex(), near.Suggestion.KeepID)
+}
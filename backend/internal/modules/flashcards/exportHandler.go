@@ -0,0 +1,46 @@
+package flashcards
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var unsafeFilenameRegex = regexp.MustCompile(`[^\p{L}\p{N}._-]+`)
+
+// ExportDeckPDF gera o PDF de um deck para impressão
+// GET /api/decks/:id/export/pdf?layout=cards|list|quiz
+func (h *Handler) ExportDeckPDF(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	deckID := c.Param("id")
+	if deckID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Deck ID is required"})
+		return
+	}
+
+	layout := c.DefaultQuery("layout", PDFLayoutCards)
+	export, err := h.service.ExportDeckPDF(userID, deckID, layout)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Avisa o cliente quando parte do texto não pôde ser representada
+	if export.UnsupportedChars > 0 {
+		c.Header("X-PDF-Unsupported-Characters", strconv.Itoa(export.UnsupportedChars))
+	}
+	filename := strings.Trim(unsafeFilenameRegex.ReplaceAllString(export.Deck.Name, "-"), "-")
+	if filename == "" {
+		filename = "deck"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s.pdf"`, filename, layout))
+	c.Data(http.StatusOK, "application/pdf", export.Content)
+}
@@ -0,0 +1,146 @@
+package flashcards
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FilteredSessionRequest descreve uma sessão filtrada: os cards vêm de uma
+// busca na linguagem de cards, vencidos ou não
+type FilteredSessionRequest struct {
+	Query      string `json:"query" binding:"required"`
+	Limit      int    `json:"limit"`      // máximo de cards na fila
+	Reschedule bool   `json:"reschedule"` // false é o modo cram: as respostas não mexem no agendamento
+}
+
+// StartFilteredSession monta uma sessão com os cards dos decks do usuário que
+// casam com a busca. Enquanto a sessão estiver ativa, esses cards saem das
+// revisões normais do deck de origem; voltam quando ela é encerrada ou
+// apagada. Cards suspensos ou já presos em outra sessão filtrada ficam de
+// fora.
+func (s *Service) StartFilteredSession(userID string, req FilteredSessionRequest) (*entities.StudySession, error) {
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultSessionCards
+	}
+	if limit > maxSessionCards {
+		limit = maxSessionCards
+	}
+
+	decks, err := s.repo.GetDecksByUserEmail(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get decks: %w", err)
+	}
+	deckIDs := make([]string, len(decks))
+	for i, deck := range decks {
+		deckIDs[i] = deck.ID.Hex()
+	}
+
+	compiled, err := s.compileCardQuery(userID, decks, req.Query)
+	if err != nil {
+		return nil, err
+	}
+	filter := bson.M{
+		"deckId":          bson.M{"$in": deckIDs},
+		"suspended":       bson.M{"$ne": true},
+		"filteredSession": bson.M{"$exists": false},
+	}
+	compiled.apply(filter)
+
+	cards, err := s.repo.QueryFlashcards(filter, int64(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search cards: %w", err)
+	}
+	if len(cards) == 0 {
+		return nil, fmt.Errorf("no cards match the query")
+	}
+
+	// O ID é gerado antes para os cards serem presos à sessão antes de ela
+	// existir; uma sessão filtrada concorrente fica só com o que sobrar
+	session := &entities.StudySession{
+		ID:           primitive.NewObjectID(),
+		UserID:       userObjectID,
+		Kind:         entities.StudySessionFiltered,
+		Query:        req.Query,
+		Reschedule:   req.Reschedule,
+		Status:       entities.StudySessionActive,
+		LastActivity: time.Now(),
+	}
+	ids := make([]primitive.ObjectID, len(cards))
+	for i, card := range cards {
+		ids[i] = card.ID
+	}
+	claimed, err := s.repo.ClaimFilteredCards(session.ID.Hex(), ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim cards: %w", err)
+	}
+	for _, id := range ids {
+		if claimed[id] {
+			session.Queue = append(session.Queue, id.Hex())
+		}
+	}
+	session.TotalCards = len(session.Queue)
+
+	if err := s.repo.CreateStudySession(session); err != nil {
+		s.releaseFilteredCards(session)
+		return nil, fmt.Errorf("failed to create study session: %w", err)
+	}
+	return session, nil
+}
+
+// DeleteStudySession apaga uma sessão filtrada, ativa ou não, e devolve seus
+// cards às revisões do deck de origem
+func (s *Service) DeleteStudySession(userID, sessionID string) error {
+	id, err := primitive.ObjectIDFromHex(sessionID)
+	if err != nil {
+		return ErrStudySessionNotFound
+	}
+	session, err := s.repo.GetStudySession(id)
+	if err != nil {
+		return err
+	}
+	if session.UserID.Hex() != userID {
+		return ErrStudySessionNotFound
+	}
+	if session.Kind != entities.StudySessionFiltered {
+		return ErrNotFilteredSession
+	}
+
+	if _, err := s.repo.ReleaseFilteredCards(session.ID.Hex()); err != nil {
+		return fmt.Errorf("failed to release cards: %w", err)
+	}
+	if err := s.repo.DeleteStudySession(session.ID); err != nil {
+		return fmt.Errorf("failed to delete study session: %w", err)
+	}
+	return nil
+}
+
+// releaseFilteredCards devolve os cards de uma sessão filtrada encerrada. Uma
+// falha só é registrada: a sessão continua encerrada e os cards podem ser
+// liberados apagando-a.
+func (s *Service) releaseFilteredCards(session *entities.StudySession) {
+	if session.Kind != entities.StudySessionFiltered {
+		return
+	}
+	if _, err := s.repo.ReleaseFilteredCards(session.ID.Hex()); err != nil {
+		fmt.Printf("Failed to release cards of study session %s: %v\n", session.ID.Hex(), err)
+	}
+}
+
+// reschedules diz se as respostas da sessão mexem no agendamento dos cards
+func reschedules(session *entities.StudySession) bool {
+	return session.Kind != entities.StudySessionFiltered || session.Reschedule
+}
@@ -0,0 +1,36 @@
+package flashcards
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ForkDeck copia um deck público para a conta do usuário
+// POST /api/decks/:id/fork
+func (h *Handler) ForkDeck(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	deckID := c.Param("id")
+	if deckID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Deck ID is required"})
+		return
+	}
+
+	result, err := h.service.ForkDeck(userID, deckID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Forks não rendem XP para evitar farm copiando decks
+	if err := h.statsService.LogDeckCreated(c.Request.Context(), userID, result.Deck.ID.Hex(), 0); err != nil {
+		fmt.Printf("Failed to log deck creation: %v\n", err)
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
@@ -0,0 +1,227 @@
+package flashcards
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ForkResult resume a cópia de um deck
+type ForkResult struct {
+	Deck           *entities.Deck `json:"deck"`
+	CardsCopied    int            `json:"cards_copied"`
+	SubdecksCopied int            `json:"subdecks_copied"`
+	SourceDeckID   string         `json:"source_deck_id"`
+}
+
+// ForkDeck copia um deck público (ou do próprio usuário), seus subdecks e
+// cards para a conta do usuário. Os cards começam sem histórico de revisão e as mídias
+// continuam apontando para as mesmas URLs.
+func (s *Service) ForkDeck(userID, deckID string) (*ForkResult, error) {
+	source, err := s.getReadableDeck(userID, deckID)
+	if err != nil {
+		return nil, err
+	}
+	return s.copyDeck(userID, source)
+}
+
+// availableDeckName retorna name ou, se o usuário já tem um deck com esse
+// nome, a primeira variação livre "name (2)", "name (3)"...
+func (s *Service) availableDeckName(userID, name string) (string, error) {
+	candidate := name
+	for i := 2; ; i++ {
+		existing, err := s.repo.GetDeckByUserAndName(userID, candidate)
+		if err != nil {
+			return "", fmt.Errorf("failed to check deck name: %w", err)
+		}
+		if existing == nil {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s (%d)", name, i)
+	}
+}
+
+// copyDeck copia o deck de origem, os subdecks que o usuário pode ler e os
+// cards de todos eles para a conta do usuário. Se alguma escrita falhar, o
+// que já foi criado é apagado para não deixar uma cópia pela metade.
+func (s *Service) copyDeck(userID string, source *entities.Deck) (result *ForkResult, err error) {
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	subdecks, err := s.readableSubdecks(userID, source)
+	if err != nil {
+		return nil, err
+	}
+	decks := append([]entities.Deck{*source}, subdecks...)
+	cardsByDeck := make([][]entities.Flashcard, len(decks))
+	largestDeck := 0
+	for i := range decks {
+		cards, err := s.repo.GetFlashcardsByDeckIDString(decks[i].ID.Hex())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get cards: %w", err)
+		}
+		cardsByDeck[i] = cards
+		if len(cards) > largestDeck {
+			largestDeck = len(cards)
+		}
+	}
+
+	// Os limites do plano são verificados antes de qualquer escrita; o de
+	// cards vale por deck, então basta o maior deck caber
+	if len(subdecks) > 0 {
+		if err := s.validateDeckCapacityFor(userID, false, len(decks)); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.validateCardCapacity(userID, 0, largestDeck); err != nil {
+		return nil, err
+	}
+
+	// A cópia vai para o primeiro nível e sempre começa privada
+	name, err := s.availableDeckName(userID, deckLeafName(source.Name))
+	if err != nil {
+		return nil, err
+	}
+	fork, err := s.CreateDeck(userID, name, source.Description, source.Tags, false, source.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	created := []primitive.ObjectID{fork.ID}
+	defer func() {
+		if err != nil {
+			s.discardFork(created)
+		}
+	}()
+
+	fork.Color = source.Color
+	fork.Border = source.Border
+	fork.Background = source.Background
+	fork.ForkedFrom = source.ID.Hex()
+	if err := s.repo.UpdateDeck(fork); err != nil {
+		return nil, fmt.Errorf("failed to update forked deck: %w", err)
+	}
+
+	// Cada subdeck copiado aponta para o seu subdeck de origem, então pode ser
+	// sincronizado com ele como qualquer fork
+	forkIDs := map[string]string{source.ID.Hex(): fork.ID.Hex()}
+	forkIDsByPath := map[string]string{source.Name: fork.ID.Hex()}
+	for _, subdeck := range subdecks {
+		forkedSubdeck := &entities.Deck{
+			Name:        name + strings.TrimPrefix(subdeck.Name, source.Name),
+			ParentID:    forkIDsByPath[parentDeckPath(subdeck.Name)],
+			Description: subdeck.Description,
+			Tags:        subdeck.Tags,
+			Color:       subdeck.Color,
+			Border:      subdeck.Border,
+			Background:  subdeck.Background,
+			Language:    subdeck.Language,
+			ForkedFrom:  subdeck.ID.Hex(),
+		}
+		if err := s.repo.CreateDeckWithStringUserID(forkedSubdeck, userID); err != nil {
+			return nil, fmt.Errorf("failed to copy subdeck %s: %w", subdeck.Name, err)
+		}
+		created = append(created, forkedSubdeck.ID)
+		forkIDs[subdeck.ID.Hex()] = forkedSubdeck.ID.Hex()
+		forkIDsByPath[subdeck.Name] = forkedSubdeck.ID.Hex()
+	}
+
+	forkedAt := time.Now()
+	copied := 0
+	for i, deck := range decks {
+		for _, card := range cardsByDeck[i] {
+			base := cardContent(&card)
+			forkedCard := &entities.Flashcard{
+				DeckID:             forkIDs[deck.ID.Hex()],
+				UserID:             userObjectID,
+				Question:           card.Question,
+				Answer:             card.Answer,
+				Alternatives:       card.Alternatives,
+				CorrectAlternative: card.CorrectAlternative,
+				ImageURL:           card.ImageURL,
+				AudioURL:           card.AudioURL,
+				Tags:               card.Tags,
+				Difficulty:         card.Difficulty,
+				Origin: &entities.CardOrigin{
+					DeckID:   deck.ID.Hex(),
+					CardID:   card.ID.Hex(),
+					ForkedAt: forkedAt,
+					Base:     &base,
+				},
+			}
+			if err := s.repo.Create(context.Background(), forkedCard); err != nil {
+				return nil, fmt.Errorf("failed to copy card: %w", err)
+			}
+			copied++
+		}
+	}
+	for _, id := range forkIDs {
+		s.refreshDeckCardCounts(id)
+	}
+	fork.CardCount = len(cardsByDeck[0])
+
+	// Duplicar o próprio deck não conta como fork para o autor
+	if source.UserID != userID {
+		if err := s.repo.IncrementDeckForkCount(source.ID); err != nil {
+			fmt.Printf("Failed to increment fork count for deck %s: %v\n", source.ID.Hex(), err)
+		}
+	}
+
+	return &ForkResult{
+		Deck:           fork,
+		CardsCopied:    copied,
+		SubdecksCopied: len(subdecks),
+		SourceDeckID:   source.ID.Hex(),
+	}, nil
+}
+
+// readableSubdecks retorna os subdecks de source que o usuário pode ler, com
+// os pais antes dos filhos. Um subdeck cujo pai ficou de fora também fica,
+// para a cópia não ter um caminho sem o deck intermediário.
+func (s *Service) readableSubdecks(userID string, source *entities.Deck) ([]entities.Deck, error) {
+	descendants, err := s.repo.GetDescendantDecks(source.UserID, source.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subdecks: %w", err)
+	}
+	sort.SliceStable(descendants, func(i, j int) bool {
+		return strings.Count(descendants[i].Name, DeckPathSeparator) < strings.Count(descendants[j].Name, DeckPathSeparator)
+	})
+
+	// O pai é achado pelo caminho, que vale também para decks antigos sem
+	// parentId
+	included := map[string]bool{source.Name: true}
+	subdecks := []entities.Deck{}
+	for i := range descendants {
+		subdeck := &descendants[i]
+		if !included[parentDeckPath(subdeck.Name)] {
+			continue
+		}
+		if source.UserID != userID && !subdeck.IsPublic && s.deckRole(userID, subdeck) == "" {
+			continue
+		}
+		included[subdeck.Name] = true
+		subdecks = append(subdecks, *subdeck)
+	}
+	return subdecks, nil
+}
+
+// discardFork apaga os decks de uma cópia que falhou e os cards já copiados
+// para eles
+func (s *Service) discardFork(deckIDs []primitive.ObjectID) {
+	for _, deckID := range deckIDs {
+		if err := s.repo.DeleteFlashcardsByDeckID(deckID.Hex()); err != nil {
+			fmt.Printf("Failed to delete cards of partial fork %s: %v\n", deckID.Hex(), err)
+		}
+		if err := s.repo.DeleteDeck(deckID); err != nil {
+			fmt.Printf("Failed to delete partial fork %s: %v\n", deckID.Hex(), err)
+		}
+	}
+}
@@ -52,6 +52,7 @@ func (h *Handler) CreateDeck(c *gin.Context) {
 		Description string   `json:"description"`
 		Tags        []string `json:"tags"`
 		IsPublic    bool     `json:"isPublic"`
+		Language    string   `json:"language"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -61,7 +62,7 @@ func (h *Handler) CreateDeck(c *gin.Context) {
 
 	fmt.Printf("CreateDeck: request data: %+v\n", req)
 
-	deck, err := h.service.CreateDeck(userIDStr, req.Name, req.Description, req.Tags, req.IsPublic)
+	deck, err := h.service.CreateDeck(userIDStr, req.Name, req.Description, req.Tags, req.IsPublic, req.Language)
 	if err != nil {
 		fmt.Printf("CreateDeck: service error: %v\n", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -100,6 +101,17 @@ func (h *Handler) GetDecks(c *gin.Context) {
 	visibility := c.DefaultQuery("visibility", "all")
 	search := c.DefaultQuery("search", "")
 
+	// ?tree=true retorna os subdecks aninhados com contagem de cards acumulada
+	if c.Query("tree") == "true" {
+		tree, err := h.service.GetDeckTree(userIDStr, visibility, search)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, tree)
+		return
+	}
+
 	decks, err := h.service.GetDecksByUserIDWithFilter(userIDStr, visibility, search)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -115,15 +127,20 @@ func (h *Handler) GetDecks(c *gin.Context) {
 }
 
 func (h *Handler) GetDeck(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	deckID := c.Param("id")
 	if deckID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Deck ID is required"})
 		return
 	}
 
-	deck, err := h.service.GetDeckByID(deckID)
+	deck, err := h.service.GetDeckByID(userID, deckID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Deck not found"})
+		respondError(c, err)
 		return
 	}
 
@@ -131,6 +148,11 @@ func (h *Handler) GetDeck(c *gin.Context) {
 }
 
 func (h *Handler) UpdateDeck(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	deckID := c.Param("id")
 	if deckID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Deck ID is required"})
@@ -145,6 +167,7 @@ func (h *Handler) UpdateDeck(c *gin.Context) {
 		Border      string   `json:"border"`
 		Background  string   `json:"background"`
 		IsPublic    bool     `json:"isPublic"`
+		Language    string   `json:"language"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -152,9 +175,9 @@ func (h *Handler) UpdateDeck(c *gin.Context) {
 		return
 	}
 
-	deck, err := h.service.UpdateDeck(deckID, req.Name, req.Description, req.Tags, req.Color, req.Border, req.Background, req.IsPublic)
+	deck, err := h.service.UpdateDeck(userID, deckID, req.Name, req.Description, req.Tags, req.Color, req.Border, req.Background, req.IsPublic, req.Language)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -162,14 +185,19 @@ func (h *Handler) UpdateDeck(c *gin.Context) {
 }
 
 func (h *Handler) DeleteDeck(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	deckID := c.Param("id")
 	if deckID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Deck ID is required"})
 		return
 	}
 
-	if err := h.service.DeleteDeck(deckID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.service.DeleteDeck(userID, deckID); err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -236,6 +264,19 @@ func (h *Handler) CreateFlashcard(c *gin.Context) {
 		}
 	}
 
+	lintIssues, err := h.service.CheckCard(&entities.Flashcard{
+		Question:           req.Question,
+		Answer:             req.Answer,
+		Alternatives:       req.Alternatives,
+		CorrectAlternative: req.CorrectAlternative,
+		ImageURL:           &req.ImageURL,
+		AudioURL:           &req.AudioURL,
+	}, lintWarnOnly(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
 	card, err := h.service.CreateFlashcard(
 		userIDStr,
 		req.DeckID,
@@ -250,7 +291,7 @@ func (h *Handler) CreateFlashcard(c *gin.Context) {
 	)
 	if err != nil {
 		fmt.Printf("CreateFlashcard: service error: %v\n", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -261,10 +302,25 @@ func (h *Handler) CreateFlashcard(c *gin.Context) {
 		// Não falhar a operação principal por causa do log
 	}
 
-	c.JSON(http.StatusCreated, card)
+	// Avisa sobre cards parecidos no mesmo deck, sem impedir a criação
+	response := struct {
+		*entities.Flashcard
+		DuplicateWarnings []DuplicateMatch `json:"duplicate_warnings,omitempty"`
+		LintWarnings      []CardLintIssue  `json:"lint_warnings,omitempty"`
+	}{Flashcard: card, LintWarnings: lintIssues}
+	if response.DuplicateWarnings, err = h.service.FindSimilarCards(card); err != nil {
+		fmt.Printf("Failed to check duplicates: %v\n", err)
+	}
+
+	c.JSON(http.StatusCreated, response)
 }
 
 func (h *Handler) GetFlashcards(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	deckID := c.Param("deckId")
 	if deckID == "" {
 		deckID = c.Query("deckId")
@@ -274,9 +330,9 @@ func (h *Handler) GetFlashcards(c *gin.Context) {
 		return
 	}
 
-	cards, err := h.service.GetFlashcardsByDeckID(deckID)
+	cards, err := h.service.GetFlashcardsByDeckID(userID, deckID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -284,6 +340,11 @@ func (h *Handler) GetFlashcards(c *gin.Context) {
 }
 
 func (h *Handler) UpdateFlashcard(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	cardID := c.Param("id")
 	if cardID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Card ID is required"})
@@ -323,24 +384,45 @@ func (h *Handler) UpdateFlashcard(c *gin.Context) {
 		}
 	}
 
-	card, err := h.service.UpdateFlashcard(cardID, req.Question, req.Answer, req.Alternatives, req.CorrectAlternative, req.ImageURL, req.AudioURL, req.Tags, req.Difficulty)
+	lintIssues, err := h.service.CheckCard(&entities.Flashcard{
+		Question:           req.Question,
+		Answer:             req.Answer,
+		Alternatives:       req.Alternatives,
+		CorrectAlternative: req.CorrectAlternative,
+		ImageURL:           &req.ImageURL,
+		AudioURL:           &req.AudioURL,
+	}, lintWarnOnly(c))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, card)
+	card, err := h.service.UpdateFlashcard(userID, cardID, req.Question, req.Answer, req.Alternatives, req.CorrectAlternative, req.ImageURL, req.AudioURL, req.Tags, req.Difficulty)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, struct {
+		*entities.Flashcard
+		LintWarnings []CardLintIssue `json:"lint_warnings,omitempty"`
+	}{card, lintIssues})
 }
 
 func (h *Handler) DeleteFlashcard(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	cardID := c.Param("id")
 	if cardID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Card ID is required"})
 		return
 	}
 
-	if err := h.service.DeleteFlashcard(cardID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.service.DeleteFlashcard(userID, cardID); err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -349,14 +431,15 @@ func (h *Handler) DeleteFlashcard(c *gin.Context) {
 
 // Study session handlers
 func (h *Handler) StartStudySession(c *gin.Context) {
-	userID, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+	userID, ok := currentUserID(c)
+	if !ok {
 		return
 	}
 
+	// Com deck_ids, a sessão intercala os cards de vários decks
 	var req struct {
-		DeckID string `json:"deck_id" binding:"required"`
+		DeckID string `json:"deck_id"`
+		InterleavedSessionRequest
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -364,14 +447,27 @@ func (h *Handler) StartStudySession(c *gin.Context) {
 		return
 	}
 
-	session, err := h.service.StartStudySession(userID.(string), req.DeckID)
+	var session *entities.StudySession
+	var err error
+	switch {
+	case len(req.DeckIDs) > 0:
+		session, err = h.service.StartInterleavedSession(userID, req.InterleavedSessionRequest)
+	case req.DeckID != "":
+		session, err = h.service.StartStudySession(userID, req.DeckID, req.Limit)
+	default:
+		err = fmt.Errorf("deck_id or deck_ids is required")
+	}
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	// Log do início da sessão
-	err = h.statsService.LogStudySessionStart(c.Request.Context(), userID.(string), session.ID.Hex(), req.DeckID)
+	// Log do início da sessão, com todos os decks de uma sessão intercalada
+	deckIDs := session.DeckIDs
+	if len(deckIDs) == 0 {
+		deckIDs = []string{session.DeckID.Hex()}
+	}
+	err = h.statsService.LogStudySessionStart(c.Request.Context(), userID, session.ID.Hex(), deckIDs)
 	if err != nil {
 		fmt.Printf("Failed to log study session start: %v\n", err)
 		// Não falhar a operação principal por causa do log
@@ -380,45 +476,30 @@ func (h *Handler) StartStudySession(c *gin.Context) {
 	c.JSON(http.StatusCreated, session)
 }
 
+// EndStudySession encerra a sessão. Duração, cards revisados e acertos são
+// calculados pelo servidor a partir das respostas registradas.
 func (h *Handler) EndStudySession(c *gin.Context) {
-	sessionID := c.Param("id")
-	if sessionID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Session ID is required"})
-		return
-	}
-
-	var req struct {
-		CardsReviewed int     `json:"cards_reviewed" binding:"required"`
-		Score         float64 `json:"score" binding:"required"`
-	}
-
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	userID, ok := currentUserID(c)
+	if !ok {
 		return
 	}
 
-	if err := h.service.EndStudySession(sessionID, req.CardsReviewed, req.Score); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	session, err := h.service.EndStudySession(userID, c.Param("id"))
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	// Log do fim da sessão (valores de exemplo para XP, streak e level)
-	userID, exists := c.Get("user_id")
-	if exists {
-		userIDStr := userID.(string)
-		studyTime := req.CardsReviewed * 30 // estimativa de 30 segundos por card
-		xp := req.CardsReviewed * 5         // 5 XP por card
-		streak := 1                         // valor de exemplo
-		level := 1                          // valor de exemplo
-
-		err := h.statsService.LogStudySessionEnd(c.Request.Context(), userIDStr, sessionID, studyTime, xp, streak, level)
-		if err != nil {
-			fmt.Printf("Failed to log study session end: %v\n", err)
-			// Não falhar a operação principal por causa do log
-		}
+	// Log do fim da sessão (streak e level ainda são valores de exemplo)
+	streak := 1
+	level := 1
+	err = h.statsService.LogStudySessionEnd(c.Request.Context(), userID, session.ID.Hex(), session.DurationSeconds, session.XP, streak, level)
+	if err != nil {
+		fmt.Printf("Failed to log study session end: %v\n", err)
+		// Não falhar a operação principal por causa do log
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Study session ended successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "Study session ended successfully", "session": session})
 }
 
 // ReviewCard registra uma revisão de card com estatísticas
@@ -430,7 +511,6 @@ func (h *Handler) ReviewCard(c *gin.Context) {
 	}
 
 	var req struct {
-		DeckID     string `json:"deck_id" binding:"required"`
 		CardID     string `json:"card_id" binding:"required"`
 		Difficulty string `json:"difficulty" binding:"required"` // "easy", "good", "hard", "again"
 		IsCorrect  bool   `json:"is_correct"`
@@ -442,28 +522,25 @@ func (h *Handler) ReviewCard(c *gin.Context) {
 		return
 	}
 
+	// O tempo vem do cliente, então é limitado como nas sessões para não
+	// cumprir sozinho as metas de minutos
+	req.StudyTime = clampAnswerSeconds(req.StudyTime)
+
 	// Calcular XP baseado na dificuldade e acurácia
-	xp := 0
-	switch req.Difficulty {
-	case "easy":
-		xp = 1
-	case "good":
-		xp = 2
-	case "hard":
-		xp = 3
-	case "again":
-		xp = 0
-	}
+	xp := reviewXP(req.Difficulty, req.IsCorrect)
 
-	if req.IsCorrect {
-		xp *= 2 // bônus por acertar
+	// Atualiza o agendamento de quem estudou (cada membro tem o seu)
+	card, err := h.service.ReviewCard(userID.(string), req.CardID, req.IsCorrect)
+	if err != nil {
+		respondError(c, err)
+		return
 	}
 
-	// Log da revisão do card
-	err := h.statsService.LogCardReview(
+	// Log da revisão do card, no deck em que o card está
+	err = h.statsService.LogCardReview(
 		c.Request.Context(),
 		userID.(string),
-		req.DeckID,
+		card.DeckID,
 		req.CardID,
 		req.Difficulty,
 		req.IsCorrect,
@@ -476,8 +553,9 @@ func (h *Handler) ReviewCard(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Card reviewed successfully",
-		"xp":      xp,
+		"message":     "Card reviewed successfully",
+		"xp":          xp,
+		"next_review": card.NextReview,
 	})
 }
 
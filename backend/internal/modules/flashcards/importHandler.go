@@ -0,0 +1,84 @@
+package flashcards
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// currentUserID lê o user_id definido pelo middleware de autenticação
+func currentUserID(c *gin.Context) (string, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return "", false
+	}
+
+	if objectID, ok := userID.(primitive.ObjectID); ok {
+		return objectID.Hex(), true
+	}
+	if str, ok := userID.(string); ok {
+		return str, true
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID format"})
+	return "", false
+}
+
+// ImportKindle importa o arquivo "My Clippings.txt" do Kindle
+// POST /api/import/kindle (multipart: file, mode=cloze|qa)
+func (h *Handler) ImportKindle(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No clippings file provided"})
+		return
+	}
+
+	// Check file size (max 10MB)
+	if file.Size > 10*1024*1024 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File too large. Maximum size is 10MB"})
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to open clippings file"})
+		return
+	}
+	defer src.Close()
+
+	clippings, err := ParseKindleClippings(src)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(clippings) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No highlights found in clippings file"})
+		return
+	}
+
+	result, err := h.service.ImportKindleClippings(userID, clippings, c.DefaultPostForm("mode", ImportModeCloze))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Decks importados não rendem XP para evitar farm com arquivos grandes
+	for _, deck := range result.Decks {
+		if !deck.Created {
+			continue
+		}
+		if err := h.statsService.LogDeckCreated(c.Request.Context(), userID, deck.DeckID, 0); err != nil {
+			fmt.Printf("Failed to log deck creation: %v\n", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,54 @@
+package flashcards
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GetImportedClippingKeys retorna quais chaves de clipping o usuário já importou
+func (r *MongoRepository) GetImportedClippingKeys(userID string, keys []string) (map[string]bool, error) {
+	imported := make(map[string]bool)
+	if len(keys) == 0 {
+		return imported, nil
+	}
+
+	collection := r.db.GetCollection("imported_clippings")
+	cursor, err := collection.Find(context.Background(), bson.M{
+		"user_id": userID,
+		"key":     bson.M{"$in": keys},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	for cursor.Next(context.Background()) {
+		var doc struct {
+			Key string `bson:"key"`
+		}
+		if err := cursor.Decode(&doc); err == nil {
+			imported[doc.Key] = true
+		}
+	}
+
+	return imported, cursor.Err()
+}
+
+// MarkClippingImported registra o clipping para que não seja importado novamente
+func (r *MongoRepository) MarkClippingImported(userID, key, deckID, cardID string) error {
+	collection := r.db.GetCollection("imported_clippings")
+	_, err := collection.InsertOne(context.Background(), bson.M{
+		"user_id":    userID,
+		"key":        key,
+		"deck_id":    deckID,
+		"card_id":    cardID,
+		"created_at": time.Now(),
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,223 @@
+package flashcards
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"flashcard-backend/internal/domain/entities"
+)
+
+// Modos de geração de cards a partir de destaques
+const (
+	ImportModeCloze = "cloze"
+	ImportModeQA    = "qa"
+)
+
+// KindleImportResult resume uma importação do Kindle
+type KindleImportResult struct {
+	Books        int                `json:"books"`
+	DecksCreated int                `json:"decks_created"`
+	CardsCreated int                `json:"cards_created"`
+	Skipped      int                `json:"skipped"`
+	Decks        []KindleImportDeck `json:"decks"`
+	Errors       []string           `json:"errors,omitempty"`
+}
+
+// KindleImportDeck resume a importação de um livro
+type KindleImportDeck struct {
+	DeckID       string `json:"deck_id"`
+	Title        string `json:"title"`
+	Created      bool   `json:"created"`
+	CardsCreated int    `json:"cards_created"`
+	Skipped      int    `json:"skipped"`
+}
+
+// ImportKindleClippings cria um deck por livro e um card por destaque.
+// Clippings já importados pelo usuário são ignorados.
+func (s *Service) ImportKindleClippings(userID string, clippings []KindleClipping, mode string) (*KindleImportResult, error) {
+	if mode == "" {
+		mode = ImportModeCloze
+	}
+	if mode != ImportModeCloze && mode != ImportModeQA {
+		return nil, fmt.Errorf("invalid import mode: %s", mode)
+	}
+
+	keys := make([]string, 0, len(clippings))
+	for _, clipping := range clippings {
+		keys = append(keys, clipping.Key())
+	}
+	imported, err := s.repo.GetImportedClippingKeys(userID, keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check imported clippings: %w", err)
+	}
+
+	// Agrupar por livro mantendo a ordem do arquivo
+	var titles []string
+	byBook := make(map[string][]KindleClipping)
+	for _, clipping := range clippings {
+		if _, ok := byBook[clipping.Title]; !ok {
+			titles = append(titles, clipping.Title)
+		}
+		byBook[clipping.Title] = append(byBook[clipping.Title], clipping)
+	}
+
+	existing, err := s.repo.GetDecksByUserEmail(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get decks: %w", err)
+	}
+	decksByName := make(map[string]*entities.Deck)
+	for i := range existing {
+		decksByName[existing[i].Name] = &existing[i]
+	}
+
+	result := &KindleImportResult{Books: len(titles)}
+	for _, title := range titles {
+		bookClippings := byBook[title]
+		summary := KindleImportDeck{Title: title}
+
+		var pending []KindleClipping
+		for _, clipping := range bookClippings {
+			if imported[clipping.Key()] {
+				summary.Skipped++
+				continue
+			}
+			pending = append(pending, clipping)
+		}
+
+		if len(pending) == 0 {
+			if deck, ok := decksByName[title]; ok {
+				summary.DeckID = deck.ID.Hex()
+			}
+			result.Skipped += summary.Skipped
+			result.Decks = append(result.Decks, summary)
+			continue
+		}
+
+		deck, ok := decksByName[title]
+		if !ok {
+			description := "Destaques do Kindle"
+			if pending[0].Author != "" {
+				description = fmt.Sprintf("Destaques do Kindle — %s", pending[0].Author)
+			}
+			deck, err = s.CreateDeck(userID, title, description, []string{"kindle"}, false, "")
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", title, err))
+				result.Skipped += summary.Skipped + len(pending)
+				summary.Skipped += len(pending)
+				result.Decks = append(result.Decks, summary)
+				continue
+			}
+			decksByName[title] = deck
+			summary.Created = true
+			result.DecksCreated++
+		}
+		summary.DeckID = deck.ID.Hex()
+
+		for i, clipping := range pending {
+			question, answer := kindleCardContent(clipping, mode)
+			card, err := s.CreateFlashcard(userID, summary.DeckID, question, answer, nil, nil, "", "", []string{"kindle"}, 0)
+			if err != nil {
+				// Limite do plano atingido: os demais destaques do livro também falhariam
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", title, err))
+				summary.Skipped += len(pending) - i
+				break
+			}
+			if err := s.repo.MarkClippingImported(userID, clipping.Key(), summary.DeckID, card.ID.Hex()); err != nil {
+				fmt.Printf("Failed to mark clipping as imported: %v\n", err)
+			}
+			summary.CardsCreated++
+		}
+
+		result.CardsCreated += summary.CardsCreated
+		result.Skipped += summary.Skipped
+		result.Decks = append(result.Decks, summary)
+	}
+
+	return result, nil
+}
+
+// kindleCardContent monta pergunta e resposta para um destaque
+func kindleCardContent(clipping KindleClipping, mode string) (string, string) {
+	if clipping.Kind == ClippingNote {
+		return kindleSource(clipping), clipping.Text
+	}
+
+	if mode == ImportModeCloze {
+		if cloze, ok := clozeFromHighlight(clipping.Text); ok {
+			return cloze, clipping.Text
+		}
+	}
+
+	if clipping.Note != "" {
+		return clipping.Note, clipping.Text
+	}
+	return kindleSource(clipping), clipping.Text
+}
+
+func kindleSource(clipping KindleClipping) string {
+	source := fmt.Sprintf("“%s”", clipping.Title)
+	if clipping.Author != "" {
+		source += fmt.Sprintf(" (%s)", clipping.Author)
+	}
+	if clipping.Location != "" {
+		source += fmt.Sprintf(", posição %s", clipping.Location)
+	} else if clipping.Page != "" {
+		source += fmt.Sprintf(", página %s", clipping.Page)
+	}
+	return source
+}
+
+var clozeStopwords = map[string]bool{
+	"about": true, "after": true, "because": true, "before": true, "being": true, "could": true,
+	"every": true, "other": true, "should": true, "their": true, "there": true, "these": true,
+	"those": true, "through": true, "which": true, "while": true, "would": true,
+	"aquela": true, "aquele": true, "assim": true, "porque": true, "quando": true, "sobre": true,
+	"também": true, "todos": true, "ainda": true, "depois": true, "entre": true, "mesmo": true,
+	"muito": true, "nossa": true, "nosso": true, "outra": true, "outro": true, "podem": true,
+	"sempre": true, "seria": true,
+}
+
+// clozeFromHighlight oculta a palavra mais longa do trecho com a sintaxe {{c1::...}}
+func clozeFromHighlight(text string) (string, bool) {
+	best := ""
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+	}) {
+		word = strings.Trim(word, "-")
+		if utf8.RuneCountInString(word) < 5 || clozeStopwords[strings.ToLower(word)] {
+			continue
+		}
+		if utf8.RuneCountInString(word) > utf8.RuneCountInString(best) {
+			best = word
+		}
+	}
+	if best == "" {
+		return "", false
+	}
+
+	idx := indexWholeWord(text, best)
+	if idx < 0 {
+		return "", false
+	}
+	return text[:idx] + "{{c1::" + best + "}}" + text[idx+len(best):], true
+}
+
+func indexWholeWord(text, word string) int {
+	offset := 0
+	for {
+		idx := strings.Index(text[offset:], word)
+		if idx < 0 {
+			return -1
+		}
+		start := offset + idx
+		end := start + len(word)
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if (start == 0 || !unicode.IsLetter(before)) && (end == len(text) || !unicode.IsLetter(after)) {
+			return start
+		}
+		offset = end
+	}
+}
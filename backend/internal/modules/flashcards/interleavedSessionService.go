@@ -0,0 +1,207 @@
+package flashcards
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Estratégias para intercalar os decks de uma sessão
+const (
+	InterleaveRoundRobin = "round_robin" // um card de cada deck por vez
+	InterleaveRandom     = "random"
+	InterleaveWeighted   = "weighted" // decks mais atrasados aparecem com mais frequência
+)
+
+// maxInterleavedDecks limita quantos decks uma sessão pode intercalar
+const maxInterleavedDecks = 20
+
+// InterleavedSessionRequest descreve uma sessão com os cards vencidos de
+// vários decks
+type InterleavedSessionRequest struct {
+	DeckIDs      []string       `json:"deck_ids"`
+	Strategy     string         `json:"strategy"`       // round_robin (padrão), random ou weighted
+	Limit        int            `json:"limit"`          // máximo de cards na fila
+	PerDeckLimit int            `json:"per_deck_limit"` // máximo de cards de cada deck
+	DeckLimits   map[string]int `json:"deck_limits"`    // limite de decks específicos, pelo ID
+}
+
+// StartInterleavedSession abre uma sessão com os cards para revisão dos decks
+// escolhidos (e seus subdecks), intercalados pela estratégia. Um card que
+// aparece em mais de um deck escolhido entra uma vez só. As respostas
+// continuam atribuídas ao deck de cada card.
+func (s *Service) StartInterleavedSession(userID string, req InterleavedSessionRequest) (*entities.StudySession, error) {
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+	if req.Strategy == "" {
+		req.Strategy = InterleaveRoundRobin
+	}
+	if req.Strategy != InterleaveRoundRobin && req.Strategy != InterleaveRandom && req.Strategy != InterleaveWeighted {
+		return nil, fmt.Errorf("invalid strategy: %s (use round_robin, random or weighted)", req.Strategy)
+	}
+	deckIDs := uniqueStrings(req.DeckIDs)
+	if len(deckIDs) == 0 {
+		return nil, fmt.Errorf("deck_ids is required")
+	}
+	if len(deckIDs) > maxInterleavedDecks {
+		return nil, fmt.Errorf("too many decks: the limit is %d per session", maxInterleavedDecks)
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultSessionCards
+	}
+	if limit > maxSessionCards {
+		limit = maxSessionCards
+	}
+
+	now := time.Now()
+	queued := make(map[string]bool)
+	queues := make([][]string, len(deckIDs))
+	weights := make([]float64, len(deckIDs))
+	for i, deckID := range deckIDs {
+		deckLimit := interleavedDeckLimit(req, deckID, limit)
+		if deckLimit == 0 {
+			continue
+		}
+
+		// Pede a mais o que pode ser pulado por já estar na fila de outro deck
+		cards, err := s.GetDueCards(userID, deckID, int64(deckLimit+len(queued)))
+		if err != nil {
+			return nil, err
+		}
+		for _, card := range cards {
+			if len(queues[i]) == deckLimit {
+				break
+			}
+			id := card.ID.Hex()
+			if queued[id] {
+				continue
+			}
+			queued[id] = true
+			queues[i] = append(queues[i], id)
+			weights[i] += overdueWeight(card.NextReview, now)
+		}
+	}
+
+	rng := rand.New(rand.NewSource(now.UnixNano()))
+	queue := interleaveQueues(queues, req.Strategy, weights, rng, limit)
+
+	session := &entities.StudySession{
+		ID:           primitive.NewObjectID(),
+		UserID:       userObjectID,
+		Kind:         entities.StudySessionInterleaved,
+		DeckIDs:      deckIDs,
+		Strategy:     req.Strategy,
+		Status:       entities.StudySessionActive,
+		Queue:        queue,
+		TotalCards:   len(queue),
+		LastActivity: now,
+	}
+	if err := s.repo.CreateStudySession(session); err != nil {
+		return nil, fmt.Errorf("failed to create study session: %w", err)
+	}
+	return session, nil
+}
+
+// interleavedDeckLimit é o máximo de cards do deck na sessão: o limite
+// específico do deck tem precedência sobre per_deck_limit, e os dois ficam
+// limitados ao total da sessão
+func interleavedDeckLimit(req InterleavedSessionRequest, deckID string, limit int) int {
+	deckLimit := limit
+	if req.PerDeckLimit > 0 {
+		deckLimit = req.PerDeckLimit
+	}
+	if custom, ok := req.DeckLimits[deckID]; ok && custom >= 0 {
+		deckLimit = custom
+	}
+	if deckLimit > limit {
+		deckLimit = limit
+	}
+	return deckLimit
+}
+
+// overdueWeight é o peso de um card vencido na estratégia weighted: um mais
+// os dias de atraso. Cards novos pesam um.
+func overdueWeight(nextReview *time.Time, now time.Time) float64 {
+	if nextReview == nil || nextReview.After(now) {
+		return 1
+	}
+	return 1 + now.Sub(*nextReview).Hours()/24
+}
+
+// interleaveQueues junta as filas dos decks numa só, com até limit cards.
+// round_robin tira um card de cada deck por vez; random embaralha tudo;
+// weighted usa round-robin ponderado suave (o deck com maior crédito
+// acumulado sai primeiro), então um deck com o dobro do peso aparece cerca
+// do dobro das vezes, sem rajadas.
+func interleaveQueues(queues [][]string, strategy string, weights []float64, rng *rand.Rand, limit int) []string {
+	total := 0
+	for _, queue := range queues {
+		total += len(queue)
+	}
+	if total > limit {
+		total = limit
+	}
+	result := make([]string, 0, total)
+
+	switch strategy {
+	case InterleaveRandom:
+		var all []string
+		for _, queue := range queues {
+			all = append(all, queue...)
+		}
+		rng.Shuffle(len(all), func(a, b int) { all[a], all[b] = all[b], all[a] })
+		return append(result, all[:total]...)
+
+	case InterleaveWeighted:
+		next := make([]int, len(queues))
+		credit := make([]float64, len(queues))
+		for len(result) < total {
+			best, sum := -1, 0.0
+			for i, queue := range queues {
+				if next[i] >= len(queue) {
+					continue
+				}
+				credit[i] += weights[i]
+				sum += weights[i]
+				if best < 0 || credit[i] > credit[best] {
+					best = i
+				}
+			}
+			credit[best] -= sum
+			result = append(result, queues[best][next[best]])
+			next[best]++
+		}
+		return result
+
+	default:
+		for round := 0; len(result) < total; round++ {
+			for _, queue := range queues {
+				if round < len(queue) && len(result) < total {
+					result = append(result, queue[round])
+				}
+			}
+		}
+		return result
+	}
+}
+
+// uniqueStrings remove vazios e repetidos, mantendo a ordem
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var unique []string
+	for _, value := range values {
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		unique = append(unique, value)
+	}
+	return unique
+}
@@ -0,0 +1,57 @@
+package flashcards
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterleaveQueuesRoundRobin(t *testing.T) {
+	queues := [][]string{{"a1", "a2", "a3"}, {"b1"}, {"c1", "c2"}}
+	queue := interleaveQueues(queues, InterleaveRoundRobin, nil, nil, 100)
+	assert.Equal(t, []string{"a1", "b1", "c1", "a2", "c2", "a3"}, queue)
+
+	assert.Equal(t, []string{"a1", "b1", "c1", "a2"}, interleaveQueues(queues, InterleaveRoundRobin, nil, nil, 4))
+}
+
+func TestInterleaveQueuesWeighted(t *testing.T) {
+	queues := [][]string{{"a1", "a2", "a3", "a4"}, {"b1", "b2", "b3", "b4"}}
+	// O deck com o dobro do peso aparece duas vezes a cada uma do outro
+	queue := interleaveQueues(queues, InterleaveWeighted, []float64{2, 1}, nil, 6)
+	assert.Equal(t, []string{"a1", "b1", "a2", "a3", "b2", "a4"}, queue)
+
+	// Quando um deck acaba, os outros seguem
+	queue = interleaveQueues([][]string{{"a1"}, {"b1", "b2"}}, InterleaveWeighted, []float64{10, 1}, nil, 10)
+	assert.Equal(t, []string{"a1", "b1", "b2"}, queue)
+}
+
+func TestInterleaveQueuesRandom(t *testing.T) {
+	queues := [][]string{{"a1", "a2"}, {"b1", "b2", "b3"}}
+	queue := interleaveQueues(queues, InterleaveRandom, nil, rand.New(rand.NewSource(1)), 100)
+	assert.ElementsMatch(t, []string{"a1", "a2", "b1", "b2", "b3"}, queue)
+	assert.Len(t, interleaveQueues(queues, InterleaveRandom, nil, rand.New(rand.NewSource(1)), 2), 2)
+}
+
+func TestOverdueWeight(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	threeDaysAgo := now.AddDate(0, 0, -3)
+	tomorrow := now.AddDate(0, 0, 1)
+	assert.Equal(t, 1.0, overdueWeight(nil, now))
+	assert.Equal(t, 1.0, overdueWeight(&tomorrow, now))
+	assert.Equal(t, 4.0, overdueWeight(&threeDaysAgo, now))
+}
+
+func TestInterleavedDeckLimit(t *testing.T) {
+	req := InterleavedSessionRequest{PerDeckLimit: 10, DeckLimits: map[string]int{"a": 50, "b": 3, "c": 0}}
+
+	// O limite do deck vence per_deck_limit, mesmo sendo maior
+	assert.Equal(t, 50, interleavedDeckLimit(req, "a", 100))
+	assert.Equal(t, 3, interleavedDeckLimit(req, "b", 100))
+	assert.Equal(t, 0, interleavedDeckLimit(req, "c", 100))
+	assert.Equal(t, 10, interleavedDeckLimit(req, "d", 100))
+	// Tudo fica limitado ao total da sessão
+	assert.Equal(t, 20, interleavedDeckLimit(req, "a", 20))
+	assert.Equal(t, 100, interleavedDeckLimit(InterleavedSessionRequest{}, "a", 100))
+}
@@ -0,0 +1,220 @@
+package flashcards
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Tipos de clipping do arquivo "My Clippings.txt"
+const (
+	ClippingHighlight = "highlight"
+	ClippingNote      = "note"
+	ClippingBookmark  = "bookmark"
+)
+
+const kindleSeparator = "=========="
+
+// KindleClipping representa uma entrada do arquivo "My Clippings.txt"
+type KindleClipping struct {
+	Title    string `json:"title"`
+	Author   string `json:"author,omitempty"`
+	Kind     string `json:"kind"`
+	Page     string `json:"page,omitempty"`
+	Location string `json:"location,omitempty"`
+	AddedOn  string `json:"added_on,omitempty"`
+	Text     string `json:"text"`
+	Note     string `json:"note,omitempty"` // nota do leitor associada ao destaque
+}
+
+var (
+	kindleLocationRegex = regexp.MustCompile(`(?i)(?:location|loc\.|posição|posicao|posición|position)\s*([0-9]+(?:-[0-9]+)?)`)
+	kindlePageRegex     = regexp.MustCompile(`(?i)(?:page|página|pagina|seite)\s*([0-9ivxlcdm]+(?:-[0-9ivxlcdm]+)?)`)
+	kindleAddedRegex    = regexp.MustCompile(`(?i)(?:added on|adicionado:?|adicionado em|añadido el|hinzugefügt am)\s*(.+)$`)
+)
+
+// ParseKindleClippings lê o formato do "My Clippings.txt" do Kindle.
+// Marcadores são descartados e notas são anexadas ao destaque da mesma posição.
+func ParseKindleClippings(r io.Reader) ([]KindleClipping, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var clippings []KindleClipping
+	var block []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		line = strings.TrimPrefix(line, "\ufeff")
+		if strings.TrimSpace(line) == kindleSeparator {
+			if clipping, ok := parseKindleBlock(block); ok {
+				clippings = append(clippings, clipping)
+			}
+			block = block[:0]
+			continue
+		}
+		block = append(block, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read clippings: %w", err)
+	}
+	if clipping, ok := parseKindleBlock(block); ok {
+		clippings = append(clippings, clipping)
+	}
+
+	return attachKindleNotes(clippings), nil
+}
+
+func parseKindleBlock(lines []string) (KindleClipping, bool) {
+	// Remover linhas vazias do início
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	if len(lines) < 2 {
+		return KindleClipping{}, false
+	}
+
+	clipping := KindleClipping{}
+	clipping.Title, clipping.Author = splitKindleTitle(strings.TrimSpace(lines[0]))
+
+	meta := strings.TrimSpace(lines[1])
+	clipping.Kind = kindleClippingKind(meta)
+	if m := kindleLocationRegex.FindStringSubmatch(meta); m != nil {
+		clipping.Location = m[1]
+	}
+	if m := kindlePageRegex.FindStringSubmatch(meta); m != nil {
+		clipping.Page = m[1]
+	}
+	if m := kindleAddedRegex.FindStringSubmatch(meta); m != nil {
+		clipping.AddedOn = strings.TrimSpace(m[1])
+	}
+
+	clipping.Text = strings.TrimSpace(strings.Join(lines[2:], "\n"))
+	if clipping.Title == "" || clipping.Kind == "" {
+		return KindleClipping{}, false
+	}
+	if clipping.Kind != ClippingBookmark && clipping.Text == "" {
+		return KindleClipping{}, false
+	}
+
+	return clipping, true
+}
+
+// splitKindleTitle separa "Título (Autor)" considerando apenas o último parêntese
+func splitKindleTitle(line string) (string, string) {
+	if !strings.HasSuffix(line, ")") {
+		return line, ""
+	}
+	depth := 0
+	for i := len(line) - 1; i >= 0; i-- {
+		switch line[i] {
+		case ')':
+			depth++
+		case '(':
+			depth--
+			if depth == 0 {
+				title := strings.TrimSpace(line[:i])
+				author := strings.TrimSpace(line[i+1 : len(line)-1])
+				if title == "" {
+					return line, ""
+				}
+				return title, author
+			}
+		}
+	}
+	return line, ""
+}
+
+func kindleClippingKind(meta string) string {
+	lower := strings.ToLower(meta)
+	switch {
+	case strings.Contains(lower, "bookmark"), strings.Contains(lower, "marcador"), strings.Contains(lower, "lesezeichen"):
+		return ClippingBookmark
+	case strings.Contains(lower, "highlight"), strings.Contains(lower, "destaque"), strings.Contains(lower, "subrayado"), strings.Contains(lower, "markierung"):
+		return ClippingHighlight
+	case strings.Contains(lower, "note"), strings.Contains(lower, "nota"), strings.Contains(lower, "notiz"):
+		return ClippingNote
+	}
+	return ""
+}
+
+// attachKindleNotes associa cada nota ao destaque do mesmo livro que termina
+// na posição da nota. Notas sem destaque viram clippings próprios.
+func attachKindleNotes(clippings []KindleClipping) []KindleClipping {
+	var result []KindleClipping
+	seen := make(map[string]bool)
+	var notes []KindleClipping
+
+	for _, clipping := range clippings {
+		switch clipping.Kind {
+		case ClippingBookmark:
+			continue
+		case ClippingNote:
+			notes = append(notes, clipping)
+			continue
+		}
+		// O Kindle grava o destaque novamente quando ele é editado
+		key := clipping.Key()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, clipping)
+	}
+
+	for _, note := range notes {
+		attached := false
+		for i := range result {
+			if result[i].Title == note.Title && result[i].Note == "" && kindleLocationContains(result[i].Location, note.Location) {
+				result[i].Note = note.Text
+				attached = true
+				break
+			}
+		}
+		if !attached {
+			result = append(result, note)
+		}
+	}
+
+	return result
+}
+
+func kindleLocationContains(rangeLoc, loc string) bool {
+	if rangeLoc == "" || loc == "" {
+		return false
+	}
+	start, end := parseKindleRange(rangeLoc)
+	point, _ := parseKindleRange(loc)
+	return point >= start && point <= end
+}
+
+func parseKindleRange(loc string) (int, int) {
+	parts := strings.SplitN(loc, "-", 2)
+	start, _ := strconv.Atoi(parts[0])
+	end := start
+	if len(parts) == 2 {
+		end, _ = strconv.Atoi(parts[1])
+		// Alguns firmwares abreviam o fim do intervalo ("1234-56" = 1234 a 1256)
+		if end < start && len(parts[1]) < len(parts[0]) {
+			prefix := parts[0][:len(parts[0])-len(parts[1])]
+			end, _ = strconv.Atoi(prefix + parts[1])
+		}
+	}
+	return start, end
+}
+
+// Key identifica um clipping de forma estável entre importações
+func (k KindleClipping) Key() string {
+	normalized := strings.Join([]string{
+		strings.ToLower(k.Title),
+		strings.ToLower(k.Author),
+		k.Kind,
+		k.Location,
+		strings.Join(strings.Fields(k.Text), " "),
+	}, "|")
+	sum := sha1.Sum([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
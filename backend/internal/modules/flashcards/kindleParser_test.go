@@ -0,0 +1,81 @@
+package flashcards
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const sampleClippings = "\ufeffO Nome da Rosa (Eco, Umberto)\r\n" +
+	"- Seu destaque na página 12 | posição 180-182 | Adicionado: segunda-feira, 5 de março de 2018 22:15:12\r\n" +
+	"\r\n" +
+	"A biblioteca é um grande labirinto, sinal do labirinto do mundo.\r\n" +
+	"==========\r\n" +
+	"O Nome da Rosa (Eco, Umberto)\r\n" +
+	"- Sua nota na página 12 | posição 182 | Adicionado: segunda-feira, 5 de março de 2018 22:16:00\r\n" +
+	"\r\n" +
+	"O que simboliza a biblioteca?\r\n" +
+	"==========\r\n" +
+	"Thinking, Fast and Slow (Kahneman, Daniel)\r\n" +
+	"- Your Bookmark on page 40 | Location 600 | Added on Monday, March 5, 2018 10:15:12 PM\r\n" +
+	"\r\n" +
+	"\r\n" +
+	"==========\r\n" +
+	"Thinking, Fast and Slow (Kahneman, Daniel)\r\n" +
+	"- Your Highlight on page 41 | Location 610-612 | Added on Monday, March 5, 2018 10:20:00 PM\r\n" +
+	"\r\n" +
+	"Nothing in life is as important as you think it is, while you are thinking about it.\r\n" +
+	"==========\r\n" +
+	"Thinking, Fast and Slow (Kahneman, Daniel)\r\n" +
+	"- Your Highlight on page 41 | Location 610-612 | Added on Monday, March 5, 2018 10:20:00 PM\r\n" +
+	"\r\n" +
+	"Nothing in life is as important as you think it is, while you are thinking about it.\r\n" +
+	"==========\r\n"
+
+func TestParseKindleClippings(t *testing.T) {
+	clippings, err := ParseKindleClippings(strings.NewReader(sampleClippings))
+	assert.NoError(t, err)
+	assert.Len(t, clippings, 2)
+
+	rosa := clippings[0]
+	assert.Equal(t, "O Nome da Rosa", rosa.Title)
+	assert.Equal(t, "Eco, Umberto", rosa.Author)
+	assert.Equal(t, ClippingHighlight, rosa.Kind)
+	assert.Equal(t, "180-182", rosa.Location)
+	assert.Equal(t, "12", rosa.Page)
+	assert.Equal(t, "O que simboliza a biblioteca?", rosa.Note)
+
+	thinking := clippings[1]
+	assert.Equal(t, "Thinking, Fast and Slow", thinking.Title)
+	assert.Equal(t, "610-612", thinking.Location)
+	assert.Equal(t, "Monday, March 5, 2018 10:20:00 PM", thinking.AddedOn)
+}
+
+func TestKindleCardContent(t *testing.T) {
+	highlight := KindleClipping{
+		Title:    "O Nome da Rosa",
+		Author:   "Eco, Umberto",
+		Kind:     ClippingHighlight,
+		Location: "180-182",
+		Text:     "A biblioteca é um grande labirinto.",
+	}
+
+	question, answer := kindleCardContent(highlight, ImportModeCloze)
+	assert.Equal(t, "A {{c1::biblioteca}} é um grande labirinto.", question)
+	assert.Equal(t, highlight.Text, answer)
+
+	question, answer = kindleCardContent(highlight, ImportModeQA)
+	assert.Equal(t, "“O Nome da Rosa” (Eco, Umberto), posição 180-182", question)
+	assert.Equal(t, highlight.Text, answer)
+
+	highlight.Note = "O que simboliza a biblioteca?"
+	question, _ = kindleCardContent(highlight, ImportModeQA)
+	assert.Equal(t, highlight.Note, question)
+}
+
+func TestKindleClippingKeyIgnoresWhitespace(t *testing.T) {
+	a := KindleClipping{Title: "Livro", Kind: ClippingHighlight, Location: "1-2", Text: "um  trecho\nqualquer"}
+	b := KindleClipping{Title: "Livro", Kind: ClippingHighlight, Location: "1-2", Text: "um trecho qualquer"}
+	assert.Equal(t, a.Key(), b.Key())
+}
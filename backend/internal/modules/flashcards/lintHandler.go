@@ -0,0 +1,37 @@
+package flashcards
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LintDeck aponta problemas nos cards do deck
+// GET /api/decks/:id/lint?check_links=true
+func (h *Handler) LintDeck(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	checkLinks, err := strconv.ParseBool(c.DefaultQuery("check_links", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid check_links: use true or false"})
+		return
+	}
+
+	report, err := h.service.LintDeck(userID, c.Param("id"), checkLinks)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// lintWarnOnly indica se a requisição pediu lint=warn: problemas graves
+// viram avisos e o card é salvo mesmo assim
+func lintWarnOnly(c *gin.Context) bool {
+	return c.Query("lint") == "warn"
+}
@@ -0,0 +1,212 @@
+package flashcards
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+	"flashcard-backend/internal/infrastructure/mediaclient"
+)
+
+const (
+	// linkCheckTimeout limita cada checagem de link de mídia
+	linkCheckTimeout = 5 * time.Second
+	// linkCheckWorkers é quantos links são checados ao mesmo tempo
+	linkCheckWorkers = 8
+)
+
+// LintFinding junta os cards com o mesmo problema
+type LintFinding struct {
+	Rule     string   `json:"rule"`
+	Severity string   `json:"severity"`
+	Message  string   `json:"message"` // mensagem do primeiro card encontrado
+	CardIDs  []string `json:"card_ids"`
+}
+
+// DeckLintReport é o resultado da checagem de um deck
+type DeckLintReport struct {
+	DeckID   string        `json:"deck_id"`
+	Checked  int           `json:"checked"`
+	Errors   int           `json:"errors"`
+	Warnings int           `json:"warnings"`
+	Findings []LintFinding `json:"findings"`
+	// LinksNotChecked conta as URLs de mídia fora do armazenamento de envio,
+	// que o servidor não busca
+	LinksNotChecked int `json:"links_not_checked,omitempty"`
+}
+
+// CardLintError é devolvido quando um card que seria salvo tem problemas de
+// severidade error
+type CardLintError struct {
+	Issues []CardLintIssue
+}
+
+func (e *CardLintError) Error() string {
+	messages := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		messages[i] = issue.Message
+	}
+	return "card has quality errors: " + strings.Join(messages, "; ")
+}
+
+// CheckCard aplica as regras de qualidade a um card antes de salvá-lo. Os
+// erros bloqueiam com *CardLintError, a menos que allowErrors seja true;
+// nesse caso voltam como avisos junto com o restante.
+func (s *Service) CheckCard(card *entities.Flashcard, allowErrors bool) ([]CardLintIssue, error) {
+	issues := lintCard(card, time.Now())
+	if errs := lintErrors(issues); len(errs) > 0 && !allowErrors {
+		return nil, &CardLintError{Issues: errs}
+	}
+	return issues, nil
+}
+
+// LintDeck aplica as regras de qualidade a todos os cards do deck. Com
+// checkLinks, as URLs de imagem e áudio do armazenamento de envio são
+// buscadas para achar links quebrados; as de outros hosts não são buscadas.
+func (s *Service) LintDeck(userID, deckID string, checkLinks bool) (*DeckLintReport, error) {
+	deck, err := s.getEditableDeck(userID, deckID)
+	if err != nil {
+		return nil, err
+	}
+	cards, err := s.repo.FindFlashcards([]string{deck.ID.Hex()}, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cards: %w", err)
+	}
+
+	now := time.Now()
+	report := &DeckLintReport{DeckID: deck.ID.Hex(), Checked: len(cards), Findings: []LintFinding{}}
+	findings := make(map[string]*LintFinding)
+	var order []string
+	addIssue := func(cardID string, issue CardLintIssue) {
+		finding, ok := findings[issue.Rule]
+		if !ok {
+			finding = &LintFinding{Rule: issue.Rule, Severity: issue.Severity, Message: issue.Message}
+			findings[issue.Rule] = finding
+			order = append(order, issue.Rule)
+		}
+		if n := len(finding.CardIDs); n == 0 || finding.CardIDs[n-1] != cardID {
+			finding.CardIDs = append(finding.CardIDs, cardID)
+		}
+	}
+
+	for i := range cards {
+		for _, issue := range lintCard(&cards[i], now) {
+			addIssue(cards[i].ID.Hex(), issue)
+		}
+	}
+	if checkLinks {
+		var hosts []string
+		if s.media != nil {
+			hosts = s.media.MediaHosts()
+		}
+		broken, notChecked := checkMediaLinks(cards, hosts)
+		report.LinksNotChecked = notChecked
+		for i := range cards {
+			for _, media := range []struct {
+				field string
+				url   *string
+			}{{"image_url", cards[i].ImageURL}, {"audio_url", cards[i].AudioURL}} {
+				if media.url == nil || !broken[*media.url] {
+					continue
+				}
+				addIssue(cards[i].ID.Hex(), CardLintIssue{
+					Rule:     LintBrokenMediaLink,
+					Severity: LintError,
+					Field:    media.field,
+					Message:  fmt.Sprintf("%s %s could not be loaded", media.field, *media.url),
+				})
+			}
+		}
+	}
+
+	severityRank := map[string]int{LintError: 0, LintWarning: 1, LintInfo: 2}
+	sort.SliceStable(order, func(a, b int) bool {
+		return severityRank[findings[order[a]].Severity] < severityRank[findings[order[b]].Severity]
+	})
+	for _, rule := range order {
+		finding := findings[rule]
+		switch finding.Severity {
+		case LintError:
+			report.Errors += len(finding.CardIDs)
+		case LintWarning:
+			report.Warnings += len(finding.CardIDs)
+		}
+		report.Findings = append(report.Findings, *finding)
+	}
+	return report, nil
+}
+
+// checkMediaLinks busca uma única vez cada URL de mídia válida dos hosts
+// permitidos e retorna as que não responderam com sucesso, junto com quantas
+// URLs ficaram sem checar por serem de outros hosts
+func checkMediaLinks(cards []entities.Flashcard, hosts []string) (map[string]bool, int) {
+	seen := make(map[string]bool)
+	var urls []string
+	notChecked := 0
+	for _, card := range cards {
+		for _, url := range []*string{card.ImageURL, card.AudioURL} {
+			if url == nil || !isValidMediaURL(*url) || seen[*url] {
+				continue
+			}
+			seen[*url] = true
+			if !mediaclient.Allowed(*url, hosts) {
+				notChecked++
+				continue
+			}
+			urls = append(urls, *url)
+		}
+	}
+
+	client := mediaclient.NewClient(hosts, linkCheckTimeout)
+	broken := make(map[string]bool)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	queue := make(chan string)
+	for i := 0; i < linkCheckWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for url := range queue {
+				if !mediaLinkWorks(client, url) {
+					mu.Lock()
+					broken[url] = true
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, url := range urls {
+		queue <- url
+	}
+	close(queue)
+	wg.Wait()
+	return broken, notChecked
+}
+
+// mediaLinkWorks tenta HEAD e, se o servidor não aceitar, GET
+func mediaLinkWorks(client *http.Client, url string) bool {
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		ctx, cancel := context.WithTimeout(context.Background(), linkCheckTimeout)
+		req, err := http.NewRequestWithContext(ctx, method, url, nil)
+		if err != nil {
+			cancel()
+			return false
+		}
+		resp, err := client.Do(req)
+		cancel()
+		if err != nil {
+			return false
+		}
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented {
+			continue
+		}
+		return resp.StatusCode < 400
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package flashcards
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSharedDecks lista os decks compartilhados com o usuário e o papel dele
+// GET /api/decks/shared
+func (h *Handler) GetSharedDecks(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	decks, err := h.service.GetSharedDecks(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, decks)
+}
+
+// GetDeckMembers lista o dono, os membros e os convites pendentes do deck
+// GET /api/decks/:id/members
+func (h *Handler) GetDeckMembers(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	members, err := h.service.GetDeckMembers(userID, c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+// InviteDeckMember convida um usuário por email
+// POST /api/decks/:id/members
+func (h *Handler) InviteDeckMember(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Email string `json:"email" binding:"required"`
+		Role  string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	member, err := h.service.InviteDeckMember(userID, c.Param("id"), req.Email, req.Role)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, member)
+}
+
+// UpdateDeckMember troca o papel de um membro
+// PUT /api/decks/:id/members/:memberId
+func (h *Handler) UpdateDeckMember(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Role string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	member, err := h.service.UpdateDeckMemberRole(userID, c.Param("id"), c.Param("memberId"), req.Role)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// RemoveDeckMember remove um membro, cancela um convite ou sai do deck
+// DELETE /api/decks/:id/members/:memberId
+func (h *Handler) RemoveDeckMember(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.RemoveDeckMember(userID, c.Param("id"), c.Param("memberId")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Member removed successfully"})
+}
+
+// GetInvitations lista os convites pendentes para o email do usuário
+// GET /api/invitations
+func (h *Handler) GetInvitations(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	invitations, err := h.service.GetInvitations(userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invitations)
+}
+
+// AcceptInvitation torna o usuário membro do deck
+// POST /api/invitations/:id/accept
+func (h *Handler) AcceptInvitation(c *gin.Context) {
+	h.respondInvitation(c, true)
+}
+
+// DeclineInvitation recusa o convite
+// POST /api/invitations/:id/decline
+func (h *Handler) DeclineInvitation(c *gin.Context) {
+	h.respondInvitation(c, false)
+}
+
+func (h *Handler) respondInvitation(c *gin.Context, accept bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	member, err := h.service.RespondInvitation(userID, c.Param("id"), accept)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
@@ -0,0 +1,172 @@
+package flashcards
+
+import (
+	"context"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (r *MongoRepository) CreateDeckMember(member *entities.DeckMember) error {
+	result, err := r.db.GetCollection("deck_members").InsertOne(context.Background(), member)
+	if err != nil {
+		return err
+	}
+	member.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *MongoRepository) GetDeckMemberByID(memberID primitive.ObjectID) (*entities.DeckMember, error) {
+	return r.findDeckMember(bson.M{"_id": memberID})
+}
+
+// GetDeckMemberByEmail retorna o convite ou a participação de um email no deck
+func (r *MongoRepository) GetDeckMemberByEmail(deckID, email string) (*entities.DeckMember, error) {
+	return r.findDeckMember(bson.M{"deck_id": deckID, "email": email})
+}
+
+func (r *MongoRepository) findDeckMember(filter bson.M) (*entities.DeckMember, error) {
+	var member entities.DeckMember
+	err := r.db.GetCollection("deck_members").FindOne(context.Background(), filter).Decode(&member)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *MongoRepository) GetDeckMembers(deckID string) ([]entities.DeckMember, error) {
+	return r.findDeckMembers(bson.M{"deck_id": deckID})
+}
+
+// GetUserMemberships retorna as participações ativas do usuário. Sem deckIDs,
+// retorna todas.
+func (r *MongoRepository) GetUserMemberships(userID string, deckIDs []string) ([]entities.DeckMember, error) {
+	filter := bson.M{"user_id": userID, "status": entities.MemberActive}
+	if deckIDs != nil {
+		filter["deck_id"] = bson.M{"$in": deckIDs}
+	}
+	return r.findDeckMembers(filter)
+}
+
+func (r *MongoRepository) GetPendingInvitations(email string) ([]entities.DeckMember, error) {
+	return r.findDeckMembers(bson.M{"email": email, "status": entities.MemberPending})
+}
+
+func (r *MongoRepository) findDeckMembers(filter bson.M) ([]entities.DeckMember, error) {
+	opts := options.Find().SetSort(bson.M{"created_at": 1})
+	cursor, err := r.db.GetCollection("deck_members").Find(context.Background(), filter, opts)
+	if err != nil {
+		return []entities.DeckMember{}, err
+	}
+	defer cursor.Close(context.Background())
+
+	var members []entities.DeckMember
+	if err = cursor.All(context.Background(), &members); err != nil {
+		return []entities.DeckMember{}, err
+	}
+	if members == nil {
+		members = []entities.DeckMember{}
+	}
+	return members, nil
+}
+
+func (r *MongoRepository) UpdateDeckMember(member *entities.DeckMember) error {
+	_, err := r.db.GetCollection("deck_members").UpdateOne(
+		context.Background(),
+		bson.M{"_id": member.ID},
+		bson.M{"$set": bson.M{
+			"user_id":     member.UserID,
+			"name":        member.Name,
+			"role":        member.Role,
+			"status":      member.Status,
+			"accepted_at": member.AcceptedAt,
+		}},
+	)
+	return err
+}
+
+func (r *MongoRepository) DeleteDeckMember(memberID primitive.ObjectID) error {
+	_, err := r.db.GetCollection("deck_members").DeleteOne(context.Background(), bson.M{"_id": memberID})
+	return err
+}
+
+func (r *MongoRepository) DeleteDeckMembers(deckID string) error {
+	_, err := r.db.GetCollection("deck_members").DeleteMany(context.Background(), bson.M{"deck_id": deckID})
+	return err
+}
+
+// GetCardSchedules retorna o estado de revisão do membro nos decks
+// informados, indexado pelo ID do card
+func (r *MongoRepository) GetCardSchedules(userID string, deckIDs []string) (map[string]entities.CardSchedule, error) {
+	ctx := context.Background()
+	filter := bson.M{"user_id": userID, "deck_id": bson.M{"$in": deckIDs}}
+	cursor, err := r.db.GetCollection("card_schedules").Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var schedules []entities.CardSchedule
+	if err = cursor.All(ctx, &schedules); err != nil {
+		return nil, err
+	}
+	byCard := make(map[string]entities.CardSchedule, len(schedules))
+	for _, schedule := range schedules {
+		byCard[schedule.CardID] = schedule
+	}
+	return byCard, nil
+}
+
+func (r *MongoRepository) GetCardSchedule(userID, cardID string) (*entities.CardSchedule, error) {
+	var schedule entities.CardSchedule
+	err := r.db.GetCollection("card_schedules").FindOne(context.Background(), bson.M{"user_id": userID, "card_id": cardID}).Decode(&schedule)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (r *MongoRepository) UpsertCardSchedule(schedule *entities.CardSchedule) error {
+	schedule.UpdatedAt = time.Now()
+	_, err := r.db.GetCollection("card_schedules").UpdateOne(
+		context.Background(),
+		bson.M{"user_id": schedule.UserID, "card_id": schedule.CardID},
+		bson.M{"$set": bson.M{
+			"deck_id":       schedule.DeckID,
+			"review_count":  schedule.ReviewCount,
+			"last_reviewed": schedule.LastReviewed,
+			"next_review":   schedule.NextReview,
+			"lapses":        schedule.Lapses,
+			"ease":          schedule.Ease,
+			"updated_at":    schedule.UpdatedAt,
+		}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// MoveCardSchedules acompanha cards movidos para outro deck
+func (r *MongoRepository) MoveCardSchedules(cardIDs []string, deckID string) error {
+	_, err := r.db.GetCollection("card_schedules").UpdateMany(
+		context.Background(),
+		bson.M{"card_id": bson.M{"$in": cardIDs}},
+		bson.M{"$set": bson.M{"deck_id": deckID}},
+	)
+	return err
+}
+
+func (r *MongoRepository) DeleteCardSchedules(cardID string) error {
+	_, err := r.db.GetCollection("card_schedules").DeleteMany(context.Background(), bson.M{"card_id": cardID})
+	return err
+}
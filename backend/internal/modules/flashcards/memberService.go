@@ -0,0 +1,296 @@
+package flashcards
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DeckMembers é a lista de participantes de um deck
+type DeckMembers struct {
+	Owner   entities.DeckMember   `json:"owner"`
+	Members []entities.DeckMember `json:"members"`
+}
+
+// DeckInvitation é um convite pendente com o nome do deck
+type DeckInvitation struct {
+	entities.DeckMember
+	DeckName string `json:"deck_name"`
+}
+
+func validMemberRole(role string) error {
+	if role != entities.DeckRoleEditor && role != entities.DeckRoleViewer {
+		return fmt.Errorf("invalid role: %s (use editor or viewer)", role)
+	}
+	return nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// getUser resolve o usuário autenticado, necessário para convites por email
+func (s *Service) getUser(userID string) (*entities.User, error) {
+	if s.authService == nil {
+		return nil, fmt.Errorf("user service unavailable")
+	}
+	user, err := s.authService.GetUserByID(userID)
+	if err != nil || user == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+	return user, nil
+}
+
+// GetSharedDecks lista os decks de outras pessoas dos quais o usuário é membro
+func (s *Service) GetSharedDecks(userID string) ([]entities.Deck, error) {
+	memberships, err := s.repo.GetUserMemberships(userID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get memberships: %w", err)
+	}
+
+	decks := []entities.Deck{}
+	for _, membership := range memberships {
+		deck, err := s.getDeck(membership.DeckID)
+		if err != nil {
+			continue
+		}
+		deck.Role = membership.Role
+		decks = append(decks, *deck)
+	}
+	return decks, nil
+}
+
+// getEditableDecks retorna os decks do usuário e os compartilhados com ele
+// como editor, incluindo os subdecks destes
+func (s *Service) getEditableDecks(userID string) ([]entities.Deck, error) {
+	decks, err := s.repo.GetDecksByUserEmail(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get decks: %w", err)
+	}
+
+	shared, err := s.GetSharedDecks(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, deck := range shared {
+		if !canEditDeck(deck.Role) {
+			continue
+		}
+		decks = append(decks, deck)
+		descendants, err := s.repo.GetDescendantDecks(deck.UserID, deck.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get subdecks: %w", err)
+		}
+		decks = append(decks, descendants...)
+	}
+	return decks, nil
+}
+
+// GetDeckMembers lista o dono e os membros do deck; só participantes veem
+func (s *Service) GetDeckMembers(userID, deckID string) (*DeckMembers, error) {
+	deck, err := s.getReadableDeck(userID, deckID)
+	if err != nil {
+		return nil, err
+	}
+	if deck.Role == "" {
+		return nil, ErrForbidden
+	}
+
+	members, err := s.repo.GetDeckMembers(deck.ID.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get members: %w", err)
+	}
+
+	result := &DeckMembers{
+		Owner: entities.DeckMember{
+			DeckID:    deck.ID.Hex(),
+			UserID:    deck.UserID,
+			Role:      entities.DeckRoleOwner,
+			Status:    entities.MemberActive,
+			CreatedAt: deck.CreatedAt,
+		},
+		Members: members,
+	}
+	if owner, err := s.getUser(deck.UserID); err == nil {
+		result.Owner.Email = owner.Email
+		result.Owner.Name = owner.Name
+	}
+	return result, nil
+}
+
+// InviteDeckMember convida um email para o deck. O convite fica pendente até
+// ser aceito pelo usuário com esse email.
+func (s *Service) InviteDeckMember(userID, deckID, email, role string) (*entities.DeckMember, error) {
+	deck, err := s.getOwnedDeck(userID, deckID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validMemberRole(role); err != nil {
+		return nil, err
+	}
+	email = normalizeEmail(email)
+	if email == "" || !strings.Contains(email, "@") {
+		return nil, fmt.Errorf("a valid email is required")
+	}
+
+	inviter, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if normalizeEmail(inviter.Email) == email {
+		return nil, fmt.Errorf("you already own this deck")
+	}
+
+	existing, err := s.repo.GetDeckMemberByEmail(deck.ID.Hex(), email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check members: %w", err)
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("%s is already a member or has a pending invitation", email)
+	}
+
+	member := &entities.DeckMember{
+		DeckID:    deck.ID.Hex(),
+		Email:     email,
+		Role:      role,
+		Status:    entities.MemberPending,
+		InvitedBy: userID,
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.CreateDeckMember(member); err != nil {
+		return nil, fmt.Errorf("failed to create invitation: %w", err)
+	}
+
+	// O convite também aparece em GET /api/invitations; o email é só um aviso
+	go func() {
+		if err := s.authService.SendDeckInvitation(email, inviter.Name, deck.Name); err != nil {
+			fmt.Printf("Failed to send deck invitation to %s: %v\n", email, err)
+		}
+	}()
+
+	return member, nil
+}
+
+// getDeckMember retorna um membro do deck informado
+func (s *Service) getDeckMember(deckID, memberID string) (*entities.DeckMember, error) {
+	memberObjectID, err := primitive.ObjectIDFromHex(memberID)
+	if err != nil {
+		return nil, ErrMemberNotFound
+	}
+	member, err := s.repo.GetDeckMemberByID(memberObjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get member: %w", err)
+	}
+	if member == nil || member.DeckID != deckID {
+		return nil, ErrMemberNotFound
+	}
+	return member, nil
+}
+
+// UpdateDeckMemberRole troca o papel de um membro; só o dono pode
+func (s *Service) UpdateDeckMemberRole(userID, deckID, memberID, role string) (*entities.DeckMember, error) {
+	deck, err := s.getOwnedDeck(userID, deckID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validMemberRole(role); err != nil {
+		return nil, err
+	}
+	member, err := s.getDeckMember(deck.ID.Hex(), memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	member.Role = role
+	if err := s.repo.UpdateDeckMember(member); err != nil {
+		return nil, fmt.Errorf("failed to update member: %w", err)
+	}
+	return member, nil
+}
+
+// RemoveDeckMember remove um membro ou cancela um convite. O dono remove
+// qualquer membro; um membro pode sair do deck. O agendamento do membro é
+// mantido caso ele volte.
+func (s *Service) RemoveDeckMember(userID, deckID, memberID string) error {
+	deck, err := s.getDeck(deckID)
+	if err != nil {
+		return err
+	}
+	member, err := s.getDeckMember(deck.ID.Hex(), memberID)
+	if err != nil {
+		return err
+	}
+	if deck.UserID != userID && member.UserID != userID {
+		return ErrForbidden
+	}
+	if err := s.repo.DeleteDeckMember(member.ID); err != nil {
+		return err
+	}
+	now := time.Now()
+	if err := s.repo.RecordDeckAccessLost([]entities.DeckMember{*member}, now, s.trashExpiry(now)); err != nil {
+		fmt.Printf("Failed to record lost access to deck %s: %v\n", deck.ID.Hex(), err)
+	}
+	return nil
+}
+
+// GetInvitations lista os convites pendentes para o email do usuário
+func (s *Service) GetInvitations(userID string) ([]DeckInvitation, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := s.repo.GetPendingInvitations(normalizeEmail(user.Email))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invitations: %w", err)
+	}
+
+	invitations := []DeckInvitation{}
+	for _, member := range pending {
+		deck, err := s.getDeck(member.DeckID)
+		if err != nil {
+			continue
+		}
+		invitations = append(invitations, DeckInvitation{DeckMember: member, DeckName: deck.Name})
+	}
+	return invitations, nil
+}
+
+// RespondInvitation aceita ou recusa um convite feito para o email do usuário
+func (s *Service) RespondInvitation(userID, invitationID string, accept bool) (*entities.DeckMember, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	invitationObjectID, err := primitive.ObjectIDFromHex(invitationID)
+	if err != nil {
+		return nil, ErrMemberNotFound
+	}
+	member, err := s.repo.GetDeckMemberByID(invitationObjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invitation: %w", err)
+	}
+	if member == nil || member.Status != entities.MemberPending || member.Email != normalizeEmail(user.Email) {
+		return nil, ErrMemberNotFound
+	}
+
+	if !accept {
+		if err := s.repo.DeleteDeckMember(member.ID); err != nil {
+			return nil, fmt.Errorf("failed to decline invitation: %w", err)
+		}
+		return member, nil
+	}
+
+	now := time.Now()
+	member.UserID = userID
+	member.Name = user.Name
+	member.Status = entities.MemberActive
+	member.AcceptedAt = &now
+	if err := s.repo.UpdateDeckMember(member); err != nil {
+		return nil, fmt.Errorf("failed to accept invitation: %w", err)
+	}
+	return member, nil
+}
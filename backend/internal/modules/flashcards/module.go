@@ -22,9 +22,14 @@ func NewModule(db *database.MongoDB, cfg *config.Config, adminService interface
 	ValidatePublicCardLimit(ctx context.Context, userPlan string, currentPublicCardCount int) error
 }, authService interface {
 	GetUserByID(userID string) (*entities.User, error)
+	SendDeckInvitation(to, inviterName, deckName string) error
+}, media interface {
+	IsConfigured() bool
+	DeleteFile(url string) error
+	MediaHosts() []string
 }) *Module {
 	repo := NewMongoRepository(db)
-	service := NewService(repo, cfg, adminService, authService)
+	service := NewService(repo, cfg, adminService, authService, media)
 	statsService := gamification.NewStatsService(db)
 	handler := NewHandler(service, statsService, cfg)
 
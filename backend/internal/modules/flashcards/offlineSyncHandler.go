@@ -0,0 +1,50 @@
+package flashcards
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Sync recebe as revisões e edições feitas offline e devolve as mudanças
+// desde o último cursor do cliente
+// POST /api/sync
+func (h *Handler) Sync(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req SyncRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.service.Sync(userID, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Revisões offline entram nas estatísticas com o horário do cliente;
+	// reenvios já foram registrados na primeira vez
+	difficulties := make(map[string]string, len(req.Operations))
+	for _, op := range req.Operations {
+		difficulties[op.Key] = op.Difficulty
+	}
+	for _, result := range response.Results {
+		if result.Type != SyncOpReview || result.Duplicate || (result.Status != SyncApplied && result.Status != SyncSuperseded) {
+			continue
+		}
+		err := h.statsService.LogCardReviewAt(c.Request.Context(), userID, result.DeckID, result.CardID,
+			difficulties[result.Key], result.IsCorrect, result.TimeSpent, result.XP, result.ReviewedAt)
+		if err != nil {
+			fmt.Printf("Failed to log card review: %v\n", err)
+			// Não falhar a operação principal por causa do log
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,155 @@
+package flashcards
+
+import (
+	"context"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// syncReceipt registra uma operação offline já recebida, pela chave de
+// idempotência enviada pelo cliente
+type syncReceipt struct {
+	ID        primitive.ObjectID   `bson:"_id,omitempty"`
+	UserID    string               `bson:"user_id"`
+	Key       string               `bson:"key"`
+	Pending   bool                 `bson:"pending,omitempty"` // operação sendo aplicada
+	Result    *SyncOperationResult `bson:"result,omitempty"`
+	CreatedAt time.Time            `bson:"created_at"`
+}
+
+// ClaimSyncOperation reserva a chave de idempotência antes de a operação ser
+// aplicada. Se a chave já foi usada, retorna false com o recibo existente.
+func (r *MongoRepository) ClaimSyncOperation(userID, key string, now time.Time) (bool, *syncReceipt, error) {
+	ctx := context.Background()
+	collection := r.db.GetCollection("sync_operations")
+	_, err := collection.InsertOne(ctx, syncReceipt{UserID: userID, Key: key, Pending: true, CreatedAt: now})
+	if err == nil {
+		return true, nil, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return false, nil, err
+	}
+
+	var receipt syncReceipt
+	if err := collection.FindOne(ctx, bson.M{"user_id": userID, "key": key}).Decode(&receipt); err != nil {
+		return false, nil, err
+	}
+	return false, &receipt, nil
+}
+
+// CompleteSyncOperation guarda o resultado da operação no recibo
+func (r *MongoRepository) CompleteSyncOperation(userID, key string, result *SyncOperationResult) error {
+	_, err := r.db.GetCollection("sync_operations").UpdateOne(context.Background(),
+		bson.M{"user_id": userID, "key": key},
+		bson.M{"$set": bson.M{"result": result}, "$unset": bson.M{"pending": ""}},
+	)
+	return err
+}
+
+// ReleaseSyncOperation libera a chave de uma operação que falhou por erro do
+// servidor, para o cliente poder reenviá-la
+func (r *MongoRepository) ReleaseSyncOperation(userID, key string) error {
+	_, err := r.db.GetCollection("sync_operations").DeleteOne(context.Background(),
+		bson.M{"user_id": userID, "key": key, "pending": true})
+	return err
+}
+
+// GetCardsChangedSince lista os cards dos decks alterados desde since, dos
+// mais antigos para os mais novos. Com afterID continua uma paginação: só
+// entram os cards depois de (since, afterID) na ordem (updatedAt, _id).
+func (r *MongoRepository) GetCardsChangedSince(deckIDs []string, since time.Time, afterID primitive.ObjectID, limit int64) ([]entities.Flashcard, error) {
+	filter := bson.M{"deckId": bson.M{"$in": deckIDs}}
+	switch {
+	case !afterID.IsZero():
+		filter["$or"] = []bson.M{
+			{"updatedAt": bson.M{"$gt": since}},
+			{"updatedAt": since, "_id": bson.M{"$gt": afterID}},
+		}
+	case !since.IsZero():
+		filter["updatedAt"] = bson.M{"$gte": since}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "updatedAt", Value: 1}, {Key: "_id", Value: 1}}).SetLimit(limit)
+	return r.findFlashcards(filter, opts)
+}
+
+// GetScheduleChangesSince retorna os IDs dos cards que o usuário revisou nos
+// decks compartilhados desde since
+func (r *MongoRepository) GetScheduleChangesSince(userID string, deckIDs []string, since time.Time) ([]primitive.ObjectID, error) {
+	values, err := r.db.GetCollection("card_schedules").Distinct(context.Background(), "card_id",
+		bson.M{"user_id": userID, "deck_id": bson.M{"$in": deckIDs}, "updated_at": bson.M{"$gte": since}})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, 0, len(values))
+	for _, value := range values {
+		if hex, ok := value.(string); ok {
+			if id, err := primitive.ObjectIDFromHex(hex); err == nil {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// deckAccessLoss marca que um membro perdeu o acesso a um deck compartilhado,
+// por ter sido removido ou porque o dono apagou o deck, para a sincronização
+// avisar o aparelho dele
+type deckAccessLoss struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	UserID    string             `bson:"user_id"`
+	DeckID    string             `bson:"deck_id"`
+	LostAt    time.Time          `bson:"lost_at"`
+	ExpiresAt time.Time          `bson:"expires_at"`
+}
+
+// RecordDeckAccessLost registra a perda de acesso dos membros ativos. Convites
+// pendentes são ignorados.
+func (r *MongoRepository) RecordDeckAccessLost(members []entities.DeckMember, at, expiresAt time.Time) error {
+	var docs []interface{}
+	for _, member := range members {
+		if member.Status != entities.MemberActive || member.UserID == "" {
+			continue
+		}
+		docs = append(docs, deckAccessLoss{UserID: member.UserID, DeckID: member.DeckID, LostAt: at, ExpiresAt: expiresAt})
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+	_, err := r.db.GetCollection("deck_access_losses").InsertMany(context.Background(), docs)
+	return err
+}
+
+// GetDeckAccessLostSince retorna os decks compartilhados aos quais o usuário
+// perdeu o acesso desde since
+func (r *MongoRepository) GetDeckAccessLostSince(userID string, since time.Time) ([]string, error) {
+	values, err := r.db.GetCollection("deck_access_losses").Distinct(context.Background(), "deck_id",
+		bson.M{"user_id": userID, "lost_at": bson.M{"$gte": since}})
+	if err != nil {
+		return nil, err
+	}
+	deckIDs := make([]string, 0, len(values))
+	for _, value := range values {
+		if deckID, ok := value.(string); ok {
+			deckIDs = append(deckIDs, deckID)
+		}
+	}
+	return deckIDs, nil
+}
+
+// GetTrashedSince lista os itens do tipo informado apagados desde since pelo
+// usuário ou nos decks informados
+func (r *MongoRepository) GetTrashedSince(userID, itemType string, deckIDs []string, since time.Time) ([]entities.TrashItem, error) {
+	owners := []bson.M{{"user_id": userID}}
+	if len(deckIDs) > 0 {
+		owners = append(owners, bson.M{"deck_id": bson.M{"$in": deckIDs}})
+	}
+	filter := bson.M{"type": itemType, "deleted_at": bson.M{"$gte": since}, "$or": owners}
+	opts := options.Find().SetProjection(bson.M{"document": 0})
+	return r.findTrashItems(filter, opts)
+}
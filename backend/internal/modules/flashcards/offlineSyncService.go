@@ -0,0 +1,523 @@
+package flashcards
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+	"flashcard-backend/internal/modules/gamification"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrReviewSuperseded = errors.New("a later review of this card is already recorded")
+
+// Tipos de operação feitas offline
+const (
+	SyncOpReview = "review"
+	SyncOpEdit   = "edit"
+)
+
+// Resultado de cada operação
+const (
+	SyncApplied    = "applied"
+	SyncMerged     = "merged"     // edição aplicada depois de resolver conflitos
+	SyncSuperseded = "superseded" // revisão mais antiga que a última registrada; não reagenda
+	SyncRejected   = "rejected"
+	SyncPending    = "pending" // a mesma chave ainda está sendo aplicada em outra requisição
+)
+
+// Quem venceu um conflito de edição
+const (
+	SyncResolutionClient = "client"
+	SyncResolutionServer = "server"
+)
+
+const (
+	maxSyncOperations = 500
+	maxSyncChanges    = 2000
+	// syncCursorOverlap volta o cursor um pouco no tempo para não perder
+	// gravações em andamento durante a sincronização anterior; o cliente
+	// recebe alguns itens repetidos, identificados pelo ID. Não vale para a
+	// continuação de uma paginação, que segue do último card enviado.
+	syncCursorOverlap = 30 * time.Second
+)
+
+// SyncOperation é uma revisão ou edição feita offline. Edições mandam o
+// conteúdo que o cliente tinha antes de editar (base) e o conteúdo editado.
+type SyncOperation struct {
+	Key        string                `json:"idempotency_key"`
+	Type       string                `json:"type"` // "review" ou "edit"
+	CardID     string                `json:"card_id"`
+	ClientTime time.Time             `json:"client_time"`
+	Difficulty string                `json:"difficulty,omitempty"` // revisões: "again", "hard", "good", "easy"
+	TimeSpent  int                   `json:"time_spent,omitempty"` // revisões, em segundos (no máximo 300)
+	Base       *entities.CardContent `json:"base,omitempty"`
+	Content    *entities.CardContent `json:"content,omitempty"`
+}
+
+// SyncRequest é um lote de operações offline com o cursor da última
+// sincronização; sem cursor, todos os dados do usuário são enviados
+type SyncRequest struct {
+	Cursor     string          `json:"cursor"`
+	Operations []SyncOperation `json:"operations"`
+}
+
+// SyncOperationResult é o resultado de uma operação. Reenviar a mesma chave
+// devolve o mesmo resultado, marcado como duplicado.
+type SyncOperationResult struct {
+	Key        string         `bson:"key" json:"idempotency_key"`
+	Type       string         `bson:"type" json:"type"`
+	CardID     string         `bson:"card_id" json:"card_id"`
+	Status     string         `bson:"status" json:"status"`
+	Error      string         `bson:"error,omitempty" json:"error,omitempty"`
+	Duplicate  bool           `bson:"-" json:"duplicate,omitempty"`
+	DeckID     string         `bson:"deck_id,omitempty" json:"deck_id,omitempty"`
+	IsCorrect  bool           `bson:"is_correct,omitempty" json:"-"`
+	TimeSpent  int            `bson:"time_spent,omitempty" json:"-"`
+	ReviewedAt time.Time      `bson:"reviewed_at,omitempty" json:"-"` // horário do cliente, usado nas estatísticas
+	XP         int            `bson:"xp,omitempty" json:"xp,omitempty"`
+	NextReview *time.Time     `bson:"next_review,omitempty" json:"next_review,omitempty"`
+	Conflicts  []SyncConflict `bson:"conflicts,omitempty" json:"conflicts,omitempty"`
+}
+
+// SyncConflict descreve como um conflito foi resolvido
+type SyncConflict struct {
+	Field       string      `bson:"field" json:"field"`
+	Resolution  string      `bson:"resolution" json:"resolution"` // "client" ou "server"
+	ClientValue interface{} `bson:"client_value,omitempty" json:"client_value,omitempty"`
+	ServerValue interface{} `bson:"server_value,omitempty" json:"server_value,omitempty"`
+	Reason      string      `bson:"reason" json:"reason"`
+}
+
+// SyncChanges é tudo o que mudou no servidor desde o cursor do cliente
+type SyncChanges struct {
+	Full           bool                 `json:"full"` // sem cursor: os dados completos, que substituem os locais
+	Decks          []entities.Deck      `json:"decks"`
+	DeletedDeckIDs []string             `json:"deleted_deck_ids"`
+	Cards          []entities.Flashcard `json:"cards"`
+	DeletedCardIDs []string             `json:"deleted_card_ids"`
+	HasMore        bool                 `json:"has_more"` // sincronizar de novo com o novo cursor para o restante
+}
+
+// SyncResponse é a resposta de uma sincronização
+type SyncResponse struct {
+	Results []SyncOperationResult `json:"results"`
+	Changes SyncChanges           `json:"changes"`
+	Cursor  string                `json:"cursor"`
+}
+
+// Sync aplica as operações offline na ordem em que foram feitas no cliente e
+// devolve as mudanças desde o cursor, já incluindo o efeito das operações.
+func (s *Service) Sync(userID string, req SyncRequest) (*SyncResponse, error) {
+	if len(req.Operations) > maxSyncOperations {
+		return nil, fmt.Errorf("too many operations: the limit is %d per sync", maxSyncOperations)
+	}
+	cursor, err := decodeSyncCursor(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	// O cursor novo é tirado antes de aplicar e ler, para a próxima
+	// sincronização incluir tudo o que for gravado a partir daqui
+	now := time.Now()
+	// Um cursor mais antigo que a retenção da lixeira pode ter perdido cards
+	// apagados de vez: o cliente recebe tudo de novo
+	if !cursor.Time.IsZero() && s.trashExpiry(cursor.Time).Before(now) {
+		cursor = syncCursor{}
+	}
+
+	response := &SyncResponse{Results: make([]SyncOperationResult, 0, len(req.Operations))}
+	for _, op := range orderSyncOperations(req.Operations, now) {
+		result, err := s.syncOperation(userID, op, now)
+		if err != nil {
+			return nil, err
+		}
+		response.Results = append(response.Results, *result)
+	}
+
+	changes, next, err := s.syncChanges(userID, cursor, now)
+	if err != nil {
+		return nil, err
+	}
+	response.Changes = *changes
+	response.Cursor = encodeSyncCursor(next)
+	return response, nil
+}
+
+// orderSyncOperations ordena as operações pelo horário do cliente, mantendo
+// a ordem do lote em empates. Horários no futuro (relógio adiantado) viram o
+// horário do servidor.
+func orderSyncOperations(operations []SyncOperation, now time.Time) []SyncOperation {
+	ordered := make([]SyncOperation, len(operations))
+	copy(ordered, operations)
+	for i := range ordered {
+		if ordered[i].ClientTime.After(now) {
+			ordered[i].ClientTime = now
+		}
+	}
+	sort.SliceStable(ordered, func(a, b int) bool {
+		return ordered[a].ClientTime.Before(ordered[b].ClientTime)
+	})
+	return ordered
+}
+
+// syncOperation aplica uma operação uma única vez por chave. Erros do
+// servidor liberam a chave e interrompem a sincronização; operações
+// inválidas ficam registradas como rejeitadas.
+func (s *Service) syncOperation(userID string, op SyncOperation, now time.Time) (*SyncOperationResult, error) {
+	result := &SyncOperationResult{Key: op.Key, Type: op.Type, CardID: op.CardID}
+	if op.Key == "" {
+		result.Status = SyncRejected
+		result.Error = "idempotency_key is required"
+		return result, nil
+	}
+
+	claimed, receipt, err := s.repo.ClaimSyncOperation(userID, op.Key, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register operation: %w", err)
+	}
+	if !claimed {
+		if receipt.Result == nil {
+			result.Status = SyncPending
+			return result, nil
+		}
+		previous := *receipt.Result
+		previous.Duplicate = true
+		return &previous, nil
+	}
+
+	var applyErr error
+	switch op.Type {
+	case SyncOpReview:
+		applyErr = s.syncReview(userID, op, now, result)
+	case SyncOpEdit:
+		applyErr = s.syncEdit(userID, op, result)
+	default:
+		applyErr = fmt.Errorf("invalid operation type: %s (use review or edit)", op.Type)
+	}
+	if applyErr != nil {
+		var syncErr *syncServerError
+		if errors.As(applyErr, &syncErr) {
+			if err := s.repo.ReleaseSyncOperation(userID, op.Key); err != nil {
+				fmt.Printf("Failed to release sync operation %s: %v\n", op.Key, err)
+			}
+			return nil, syncErr.err
+		}
+		result.Status = SyncRejected
+		result.Error = applyErr.Error()
+	}
+
+	if err := s.repo.CompleteSyncOperation(userID, op.Key, result); err != nil {
+		return nil, fmt.Errorf("failed to register operation: %w", err)
+	}
+	return result, nil
+}
+
+// syncServerError separa falhas do servidor, que o cliente deve reenviar,
+// das operações inválidas
+type syncServerError struct {
+	err error
+}
+
+func (e *syncServerError) Error() string { return e.err.Error() }
+
+// syncReview repete a revisão no agendador com o horário do cliente.
+// Revisões mais antigas que gamification.MaxReviewBackdate são recusadas
+// para um relógio errado ou forjado não reescrever o histórico.
+func (s *Service) syncReview(userID string, op SyncOperation, now time.Time, result *SyncOperationResult) error {
+	if !isSessionDifficulty(op.Difficulty) {
+		return fmt.Errorf("invalid difficulty: %s (use again, hard, good or easy)", op.Difficulty)
+	}
+	if op.ClientTime.IsZero() {
+		return fmt.Errorf("client_time is required")
+	}
+	if op.ClientTime.Before(now.Add(-gamification.MaxReviewBackdate)) {
+		return fmt.Errorf("client_time is more than %d days old", int(gamification.MaxReviewBackdate.Hours()/24))
+	}
+
+	isCorrect := op.Difficulty != "again"
+	card, err := s.reviewCardAt(userID, op.CardID, isCorrect, op.ClientTime)
+	switch {
+	case errors.Is(err, ErrReviewSuperseded):
+		result.Status = SyncSuperseded
+		result.Conflicts = []SyncConflict{{
+			Field:       "schedule",
+			Resolution:  SyncResolutionServer,
+			ServerValue: card.LastReviewed,
+			Reason:      "the card was reviewed later on another device; this answer does not change the schedule",
+		}}
+	case err == nil:
+		result.Status = SyncApplied
+	case errors.Is(err, ErrCardNotFound), errors.Is(err, ErrDeckNotFound), errors.Is(err, ErrForbidden):
+		return err
+	default:
+		return &syncServerError{err}
+	}
+
+	result.DeckID = card.DeckID
+	result.IsCorrect = isCorrect
+	result.TimeSpent = clampAnswerSeconds(op.TimeSpent)
+	result.ReviewedAt = op.ClientTime
+	result.XP = reviewXP(op.Difficulty, isCorrect)
+	result.NextReview = card.NextReview
+	return nil
+}
+
+// syncEdit aplica uma edição offline com merge de três vias contra o
+// conteúdo atual do card: campos que só o cliente mudou são aplicados e
+// campos que os dois mudaram ficam com a alteração mais recente. Empates
+// ficam com o servidor.
+func (s *Service) syncEdit(userID string, op SyncOperation, result *SyncOperationResult) error {
+	if op.Base == nil || op.Content == nil {
+		return fmt.Errorf("base and content are required for edits")
+	}
+	ctx := context.Background()
+	card, err := s.repo.GetByID(ctx, op.CardID)
+	if err != nil {
+		return ErrCardNotFound
+	}
+	if _, err := s.getEditableDeck(userID, card.DeckID); err != nil {
+		return err
+	}
+
+	serverChangedAt, err := s.lastContentChange(card)
+	if err != nil {
+		return &syncServerError{err}
+	}
+	before := cardContent(card)
+	merged, conflicts := resolveOfflineEdit(*op.Base, before, *op.Content, op.ClientTime.After(serverChangedAt))
+
+	result.DeckID = card.DeckID
+	result.Conflicts = conflicts
+	result.Status = SyncApplied
+	if len(conflicts) > 0 {
+		result.Status = SyncMerged
+	}
+	if len(diffCardContent(before, merged)) == 0 {
+		return nil
+	}
+
+	applyCardContent(card, merged)
+	if _, err := s.CheckCard(card, false); err != nil {
+		return err
+	}
+	if err := s.repo.Update(ctx, card); err != nil {
+		return &syncServerError{fmt.Errorf("failed to update flashcard: %w", err)}
+	}
+	s.recordRevision(card, &before, s.revisionAuthorFor(userID), entities.RevisionUpdate, "")
+	return nil
+}
+
+// lastContentChange é quando o conteúdo do card mudou pela última vez no
+// servidor: a revisão mais recente ou, sem histórico, a criação do card
+func (s *Service) lastContentChange(card *entities.Flashcard) (time.Time, error) {
+	revisions, err := s.repo.GetCardRevisions(card.ID.Hex(), 1)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get revisions: %w", err)
+	}
+	if len(revisions) > 0 {
+		return revisions[0].CreatedAt, nil
+	}
+	return card.CreatedAt, nil
+}
+
+// resolveOfflineEdit faz o merge de uma edição offline. clientNewer diz se a
+// edição do cliente é mais recente que a última mudança no servidor e decide
+// os campos alterados pelos dois lados.
+func resolveOfflineEdit(base, server, client entities.CardContent, clientNewer bool) (entities.CardContent, []SyncConflict) {
+	merged, _, conflicted := mergeCardContent(base, server, client)
+	var conflicts []SyncConflict
+	for _, name := range conflicted {
+		for _, field := range cardContentFields {
+			if field.name != name {
+				continue
+			}
+			conflict := SyncConflict{
+				Field:       name,
+				Resolution:  SyncResolutionServer,
+				ClientValue: field.get(&client),
+				ServerValue: field.get(&server),
+				Reason:      "changed on the server after the offline edit",
+			}
+			if clientNewer {
+				field.copy(&merged, &client)
+				conflict.Resolution = SyncResolutionClient
+				conflict.Reason = "offline edit is newer than the server change"
+			}
+			conflicts = append(conflicts, conflict)
+		}
+	}
+	return merged, conflicts
+}
+
+// syncChanges reúne os decks e cards alterados ou apagados desde o cursor.
+// Sem cursor é uma sincronização completa, sem apagados. Se houver mais cards
+// que o limite, o cursor devolvido aponta para o último card enviado.
+func (s *Service) syncChanges(userID string, cursor syncCursor, now time.Time) (*SyncChanges, syncCursor, error) {
+	changes := &SyncChanges{Full: cursor.Time.IsZero(), DeletedDeckIDs: []string{}, DeletedCardIDs: []string{}}
+	next := syncCursor{Time: now}
+	from := cursor.Time
+	if !changes.Full && cursor.AfterID.IsZero() {
+		from = cursor.Time.Add(-syncCursorOverlap)
+	}
+
+	owned, err := s.repo.GetDecksByUserEmail(userID)
+	if err != nil {
+		return nil, next, fmt.Errorf("failed to get decks: %w", err)
+	}
+	shared, err := s.GetSharedDecks(userID)
+	if err != nil {
+		return nil, next, err
+	}
+	var deckIDs, sharedIDs []string
+	isShared := make(map[string]bool, len(shared))
+	changes.Decks = []entities.Deck{}
+	for _, deck := range append(owned, shared...) {
+		deckIDs = append(deckIDs, deck.ID.Hex())
+		if deck.UserID != userID {
+			sharedIDs = append(sharedIDs, deck.ID.Hex())
+			isShared[deck.ID.Hex()] = true
+		}
+		if changes.Full || !deck.UpdatedAt.Before(from) {
+			changes.Decks = append(changes.Decks, deck)
+		}
+	}
+
+	changes.Cards = []entities.Flashcard{}
+	if len(deckIDs) > 0 {
+		cards, err := s.repo.GetCardsChangedSince(deckIDs, from, cursor.AfterID, maxSyncChanges+1)
+		if err != nil {
+			return nil, next, fmt.Errorf("failed to get changed cards: %w", err)
+		}
+		if len(cards) > maxSyncChanges {
+			cards = cards[:maxSyncChanges]
+			changes.HasMore = true
+			last := cards[len(cards)-1]
+			next = syncCursor{Time: last.UpdatedAt, AfterID: last.ID}
+		}
+		changes.Cards = cards
+	}
+
+	// Nos decks compartilhados o agendamento é do membro: revisões dele
+	// também contam como mudança do card
+	if len(sharedIDs) > 0 {
+		if !changes.Full {
+			reviewed, err := s.repo.GetScheduleChangesSince(userID, sharedIDs, from)
+			if err != nil {
+				return nil, next, fmt.Errorf("failed to get schedule changes: %w", err)
+			}
+			included := make(map[primitive.ObjectID]bool, len(changes.Cards))
+			for _, card := range changes.Cards {
+				included[card.ID] = true
+			}
+			var missing []primitive.ObjectID
+			for _, id := range reviewed {
+				if !included[id] {
+					missing = append(missing, id)
+				}
+			}
+			if len(missing) > 0 {
+				cards, err := s.repo.GetFlashcardsByIDs(missing)
+				if err != nil {
+					return nil, next, fmt.Errorf("failed to get cards: %w", err)
+				}
+				changes.Cards = append(changes.Cards, cards...)
+			}
+		}
+
+		var sharedCards []entities.Flashcard
+		var positions []int
+		for i, card := range changes.Cards {
+			if isShared[card.DeckID] {
+				sharedCards = append(sharedCards, card)
+				positions = append(positions, i)
+			}
+		}
+		if err := s.applyMemberSchedules(userID, sharedIDs, sharedCards); err != nil {
+			return nil, next, err
+		}
+		for i, position := range positions {
+			changes.Cards[position] = sharedCards[i]
+		}
+	}
+
+	if !changes.Full {
+		deletedCards, err := s.repo.GetTrashedSince(userID, entities.TrashCard, sharedIDs, from)
+		if err != nil {
+			return nil, next, fmt.Errorf("failed to get deleted cards: %w", err)
+		}
+		for _, item := range deletedCards {
+			changes.DeletedCardIDs = append(changes.DeletedCardIDs, item.ItemID.Hex())
+		}
+		deletedDecks, err := s.repo.GetTrashedSince(userID, entities.TrashDeck, nil, from)
+		if err != nil {
+			return nil, next, fmt.Errorf("failed to get deleted decks: %w", err)
+		}
+		lostDeckIDs, err := s.repo.GetDeckAccessLostSince(userID, from)
+		if err != nil {
+			return nil, next, fmt.Errorf("failed to get lost deck access: %w", err)
+		}
+		// Um deck pode ter sido apagado e restaurado, ou o membro convidado de
+		// novo: só sai do aparelho o que continua inacessível
+		accessible := make(map[string]bool, len(deckIDs))
+		for _, deckID := range deckIDs {
+			accessible[deckID] = true
+		}
+		for _, item := range deletedDecks {
+			lostDeckIDs = append(lostDeckIDs, item.ItemID.Hex())
+		}
+		for _, deckID := range lostDeckIDs {
+			if !accessible[deckID] {
+				accessible[deckID] = true // evita repetir o ID
+				changes.DeletedDeckIDs = append(changes.DeletedDeckIDs, deckID)
+			}
+		}
+	}
+	return changes, next, nil
+}
+
+// syncCursor marca onde a próxima sincronização começa: o instante da
+// sincronização anterior ou, no meio de uma paginação, o updatedAt e o ID do
+// último card enviado
+type syncCursor struct {
+	Time    time.Time
+	AfterID primitive.ObjectID
+}
+
+// O cursor vai para o cliente como "<milissegundos>" ou
+// "<milissegundos>.<id>", em base64, e é opaco para ele
+func encodeSyncCursor(cursor syncCursor) string {
+	raw := strconv.FormatInt(cursor.Time.UnixMilli(), 10)
+	if !cursor.AfterID.IsZero() {
+		raw += "." + cursor.AfterID.Hex()
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeSyncCursor(encoded string) (syncCursor, error) {
+	if encoded == "" {
+		return syncCursor{}, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return syncCursor{}, fmt.Errorf("invalid cursor")
+	}
+	millisPart, idPart, paged := strings.Cut(string(raw), ".")
+	millis, err := strconv.ParseInt(millisPart, 10, 64)
+	if err != nil || millis <= 0 {
+		return syncCursor{}, fmt.Errorf("invalid cursor")
+	}
+	cursor := syncCursor{Time: time.UnixMilli(millis)}
+	if paged {
+		if cursor.AfterID, err = primitive.ObjectIDFromHex(idPart); err != nil {
+			return syncCursor{}, fmt.Errorf("invalid cursor")
+		}
+	}
+	return cursor, nil
+}
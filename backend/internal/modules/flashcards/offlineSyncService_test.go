@@ -0,0 +1,81 @@
+package flashcards
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestOrderSyncOperations(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	ordered := orderSyncOperations([]SyncOperation{
+		{Key: "c", ClientTime: now.Add(-time.Minute)},
+		{Key: "future", ClientTime: now.Add(time.Hour)},
+		{Key: "a", ClientTime: now.Add(-time.Hour)},
+		{Key: "b", ClientTime: now.Add(-time.Minute)},
+	}, now)
+
+	keys := make([]string, len(ordered))
+	for i, op := range ordered {
+		keys[i] = op.Key
+	}
+	// Empates mantêm a ordem do lote; relógio adiantado vira o do servidor
+	assert.Equal(t, []string{"a", "c", "b", "future"}, keys)
+	assert.Equal(t, now, ordered[3].ClientTime)
+}
+
+func TestResolveOfflineEdit(t *testing.T) {
+	base := entities.CardContent{Question: "Capital da França?", Answer: "Paris", Tags: []string{"geo"}}
+	server := base
+	server.Answer = "Paris."
+	server.Tags = []string{"geo", "europa"}
+	client := base
+	client.Question = "Qual a capital da França?"
+	client.Answer = "Paris, França"
+
+	// Só o cliente mudou a pergunta e só o servidor mudou as tags: sem conflito.
+	// Os dois mudaram a resposta: vale a alteração mais antiga do servidor.
+	merged, conflicts := resolveOfflineEdit(base, server, client, false)
+	assert.Equal(t, "Qual a capital da França?", merged.Question)
+	assert.Equal(t, "Paris.", merged.Answer)
+	assert.Equal(t, []string{"geo", "europa"}, merged.Tags)
+	require.Len(t, conflicts, 1)
+	assert.Equal(t, "answer", conflicts[0].Field)
+	assert.Equal(t, SyncResolutionServer, conflicts[0].Resolution)
+
+	// Edição offline mais recente vence o conflito
+	merged, conflicts = resolveOfflineEdit(base, server, client, true)
+	assert.Equal(t, "Paris, França", merged.Answer)
+	require.Len(t, conflicts, 1)
+	assert.Equal(t, SyncResolutionClient, conflicts[0].Resolution)
+}
+
+func TestSyncCursorRoundTrip(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 30, 15, 250*int(time.Millisecond), time.UTC)
+	decoded, err := decodeSyncCursor(encodeSyncCursor(syncCursor{Time: at}))
+	require.NoError(t, err)
+	assert.True(t, at.Equal(decoded.Time))
+	assert.True(t, decoded.AfterID.IsZero())
+
+	// Cursor de paginação guarda também o último card enviado
+	lastID := primitive.NewObjectID()
+	decoded, err = decodeSyncCursor(encodeSyncCursor(syncCursor{Time: at, AfterID: lastID}))
+	require.NoError(t, err)
+	assert.True(t, at.Equal(decoded.Time))
+	assert.Equal(t, lastID, decoded.AfterID)
+
+	empty, err := decodeSyncCursor("")
+	require.NoError(t, err)
+	assert.True(t, empty.Time.IsZero())
+
+	_, err = decodeSyncCursor("not a cursor")
+	assert.Error(t, err)
+	_, err = decodeSyncCursor(base64.RawURLEncoding.EncodeToString([]byte("1709296215250.xyz")))
+	assert.Error(t, err)
+}
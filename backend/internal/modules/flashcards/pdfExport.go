@@ -0,0 +1,345 @@
+package flashcards
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"regexp"
+	"sort"
+	"strings"
+
+	"flashcard-backend/internal/domain/entities"
+	"flashcard-backend/internal/infrastructure/pdf"
+)
+
+// Layouts disponíveis para exportação em PDF
+const (
+	PDFLayoutCards = "cards" // grade frente/verso para impressão duplex
+	PDFLayoutList  = "list"  // lista de perguntas e respostas
+	PDFLayoutQuiz  = "quiz"  // prova de múltipla escolha com gabarito
+)
+
+const (
+	pdfMargin     = 36.0
+	pdfGridCols   = 2
+	pdfGridRows   = 4
+	pdfCellMargin = 12.0
+)
+
+var clozeRegex = regexp.MustCompile(`\{\{c\d+::(.*?)(?:::(.*?))?\}\}`)
+
+// renderCloze troca as lacunas {{c1::texto::dica}} por "[...]" (ou pela dica)
+// na frente do card, e pelo texto original no verso
+func renderCloze(text string, reveal bool) string {
+	return clozeRegex.ReplaceAllStringFunc(text, func(match string) string {
+		parts := clozeRegex.FindStringSubmatch(match)
+		if reveal {
+			return parts[1]
+		}
+		if parts[2] != "" {
+			return "[" + parts[2] + "]"
+		}
+		return "[...]"
+	})
+}
+
+// PDFExport é o PDF gerado de um deck. UnsupportedChars conta os caracteres
+// que o PDF não representa (fora do WinAnsi) e saíram como '?'.
+type PDFExport struct {
+	Deck             *entities.Deck
+	Content          []byte
+	UnsupportedChars int
+}
+
+// ExportDeckPDF gera o PDF de um deck do usuário ou público
+func (s *Service) ExportDeckPDF(userID, deckID, layout string) (*PDFExport, error) {
+	deck, err := s.getReadableDeck(userID, deckID)
+	if err != nil {
+		return nil, err
+	}
+
+	cards, err := s.repo.GetFlashcardsByDeckIDString(deckID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cards: %w", err)
+	}
+	if len(cards) == 0 {
+		return nil, fmt.Errorf("deck has no cards to export")
+	}
+	sort.SliceStable(cards, func(i, j int) bool {
+		return cards[i].CreatedAt.Before(cards[j].CreatedAt)
+	})
+
+	var doc *pdf.Document
+	switch layout {
+	case "", PDFLayoutCards:
+		doc = renderCardGrid(cards)
+	case PDFLayoutList:
+		doc = renderCardList(deck, cards)
+	case PDFLayoutQuiz:
+		doc = renderQuizSheet(deck, cards)
+	default:
+		return nil, fmt.Errorf("invalid layout: %s", layout)
+	}
+
+	return &PDFExport{Deck: deck, Content: doc.Bytes(), UnsupportedChars: doc.ReplacedChars()}, nil
+}
+
+func cardFront(card entities.Flashcard) string {
+	front := renderCloze(card.Question, false)
+	for i, alternative := range card.Alternatives {
+		front += fmt.Sprintf("\n%c) %s", 'A'+i, alternative)
+	}
+	return front
+}
+
+func cardBack(card entities.Flashcard) string {
+	if clozeRegex.MatchString(card.Question) && card.Answer == "" {
+		return renderCloze(card.Question, true)
+	}
+	if card.CorrectAlternative != nil && *card.CorrectAlternative >= 0 && *card.CorrectAlternative < len(card.Alternatives) {
+		back := fmt.Sprintf("%c) %s", 'A'+*card.CorrectAlternative, card.Alternatives[*card.CorrectAlternative])
+		if card.Answer != "" {
+			back += "\n\n" + card.Answer
+		}
+		return back
+	}
+	return card.Answer
+}
+
+// renderCardGrid gera páginas alternadas de frente e verso. No verso as
+// colunas são espelhadas para que cada resposta caia atrás da sua pergunta
+// na impressão duplex pela borda longa.
+func renderCardGrid(cards []entities.Flashcard) *pdf.Document {
+	doc := pdf.New()
+	cellW := (doc.Width - 2*pdfMargin) / pdfGridCols
+	cellH := (doc.Height - 2*pdfMargin) / pdfGridRows
+	perPage := pdfGridCols * pdfGridRows
+
+	for start := 0; start < len(cards); start += perPage {
+		end := start + perPage
+		if end > len(cards) {
+			end = len(cards)
+		}
+		batch := cards[start:end]
+
+		front := doc.AddPage()
+		drawCutLines(front, cellW, cellH)
+		for i, card := range batch {
+			row, col := gridCell(i, false)
+			x := pdfMargin + float64(col)*cellW
+			y := pdfMargin + float64(row)*cellH
+			front.Text(x+pdfCellMargin, y+pdfCellMargin+6, 7, false, fmt.Sprintf("#%d", start+i+1))
+			drawFittedText(front, x+pdfCellMargin, y+pdfCellMargin+14, cellW-2*pdfCellMargin, cellH-2*pdfCellMargin-14, cardFront(card), true)
+		}
+
+		back := doc.AddPage()
+		drawCutLines(back, cellW, cellH)
+		for i, card := range batch {
+			row, col := gridCell(i, true)
+			x := pdfMargin + float64(col)*cellW
+			y := pdfMargin + float64(row)*cellH
+			back.Text(x+pdfCellMargin, y+pdfCellMargin+6, 7, false, fmt.Sprintf("#%d", start+i+1))
+			drawFittedText(back, x+pdfCellMargin, y+pdfCellMargin+14, cellW-2*pdfCellMargin, cellH-2*pdfCellMargin-14, cardBack(card), false)
+		}
+	}
+
+	return doc
+}
+
+// gridCell é a linha e a coluna do i-ésimo card da página; no verso a
+// coluna é espelhada
+func gridCell(i int, back bool) (row, col int) {
+	row, col = i/pdfGridCols, i%pdfGridCols
+	if back {
+		col = pdfGridCols - 1 - col
+	}
+	return row, col
+}
+
+func drawCutLines(page *pdf.Page, cellW, cellH float64) {
+	for col := 0; col <= pdfGridCols; col++ {
+		x := pdfMargin + float64(col)*cellW
+		page.Line(x, pdfMargin, x, pdfMargin+cellH*pdfGridRows, 0.5, true)
+	}
+	for row := 0; row <= pdfGridRows; row++ {
+		y := pdfMargin + float64(row)*cellH
+		page.Line(pdfMargin, y, pdfMargin+cellW*pdfGridCols, y, 0.5, true)
+	}
+}
+
+// drawFittedText reduz a fonte até o texto caber na caixa; se ainda assim
+// não couber, o texto é truncado com reticências
+func drawFittedText(page *pdf.Page, x, y, w, h float64, text string, bold bool) {
+	size := 14.0
+	var lines []string
+	for ; size >= 8; size -= 1 {
+		lines = pdf.Wrap(text, size, bold, w)
+		if float64(len(lines))*size*1.25 <= h {
+			break
+		}
+	}
+	if size < 8 {
+		size = 8
+	}
+
+	maxLines := int(h / (size * 1.25))
+	if len(lines) > maxLines && maxLines > 0 {
+		lines = lines[:maxLines]
+		lines[maxLines-1] = strings.TrimRight(lines[maxLines-1], " ") + "…"
+	}
+
+	for i, line := range lines {
+		page.Text(x, y+size+float64(i)*size*1.25, size, bold, line)
+	}
+}
+
+// pdfFlow posiciona blocos de texto em sequência, abrindo páginas novas
+type pdfFlow struct {
+	doc  *pdf.Document
+	page *pdf.Page
+	y    float64
+}
+
+func newPDFFlow(title, subtitle string) *pdfFlow {
+	flow := &pdfFlow{doc: pdf.New()}
+	flow.newPage()
+	flow.write(title, 18, true, 0)
+	if subtitle != "" {
+		flow.write(subtitle, 10, false, 0)
+	}
+	flow.y += 10
+	return flow
+}
+
+func (f *pdfFlow) newPage() {
+	f.page = f.doc.AddPage()
+	f.y = pdfMargin
+}
+
+// write escreve um parágrafo quebrando linhas e páginas quando necessário
+func (f *pdfFlow) write(text string, size float64, bold bool, indent float64) {
+	width := f.doc.Width - 2*pdfMargin - indent
+	for _, line := range pdf.Wrap(text, size, bold, width) {
+		if f.y+size*1.3 > f.doc.Height-pdfMargin {
+			f.newPage()
+		}
+		f.y += size * 1.3
+		f.page.Text(pdfMargin+indent, f.y, size, bold, line)
+	}
+}
+
+// ensure abre uma nova página se não houver espaço para height pontos
+func (f *pdfFlow) ensure(height float64) {
+	if f.y+height > f.doc.Height-pdfMargin {
+		f.newPage()
+	}
+}
+
+func renderCardList(deck *entities.Deck, cards []entities.Flashcard) *pdf.Document {
+	flow := newPDFFlow(deck.Name, deck.Description)
+	for i, card := range cards {
+		flow.ensure(60)
+		flow.write(fmt.Sprintf("%d. %s", i+1, cardFront(card)), 11, true, 0)
+		flow.write(cardBack(card), 11, false, 18)
+		flow.y += 10
+	}
+	return flow.doc
+}
+
+type quizQuestion struct {
+	text    string
+	options []string
+	correct int
+	answer  string
+}
+
+// buildQuizQuestions usa as alternativas dos cards de múltipla escolha e,
+// para cards abertos, sorteia distratores entre as respostas dos outros cards
+func buildQuizQuestions(seed string, cards []entities.Flashcard) []quizQuestion {
+	hash := fnv.New64a()
+	hash.Write([]byte(seed))
+	rng := rand.New(rand.NewSource(int64(hash.Sum64())))
+
+	var answers []string
+	for _, card := range cards {
+		if len(card.Alternatives) == 0 && card.Answer != "" {
+			answers = append(answers, card.Answer)
+		}
+	}
+
+	var questions []quizQuestion
+	for _, card := range cards {
+		question := quizQuestion{text: renderCloze(card.Question, false), correct: -1}
+
+		if len(card.Alternatives) >= 2 && card.CorrectAlternative != nil {
+			question.options = card.Alternatives
+			question.correct = *card.CorrectAlternative
+			questions = append(questions, question)
+			continue
+		}
+
+		question.answer = cardBack(card)
+		var distractors []string
+		seen := map[string]bool{strings.ToLower(card.Answer): true}
+		for _, idx := range rng.Perm(len(answers)) {
+			candidate := answers[idx]
+			if seen[strings.ToLower(candidate)] {
+				continue
+			}
+			seen[strings.ToLower(candidate)] = true
+			distractors = append(distractors, candidate)
+			if len(distractors) == 3 {
+				break
+			}
+		}
+
+		if len(distractors) == 3 && card.Answer != "" {
+			options := append([]string{card.Answer}, distractors...)
+			rng.Shuffle(len(options), func(i, j int) { options[i], options[j] = options[j], options[i] })
+			for i, option := range options {
+				if option == card.Answer {
+					question.correct = i
+				}
+			}
+			question.options = options
+		}
+		questions = append(questions, question)
+	}
+
+	return questions
+}
+
+func renderQuizSheet(deck *entities.Deck, cards []entities.Flashcard) *pdf.Document {
+	questions := buildQuizQuestions(deck.ID.Hex(), cards)
+
+	flow := newPDFFlow(deck.Name, "Nome: ______________________________    Data: ____/____/______")
+	for i, question := range questions {
+		flow.ensure(80)
+		flow.write(fmt.Sprintf("%d. %s", i+1, question.text), 11, true, 0)
+		if len(question.options) == 0 {
+			for line := 0; line < 3; line++ {
+				flow.ensure(20)
+				flow.y += 18
+				flow.page.Line(pdfMargin+18, flow.y, flow.doc.Width-pdfMargin, flow.y, 0.5, false)
+			}
+		}
+		for j, option := range question.options {
+			flow.write(fmt.Sprintf("(   ) %c) %s", 'A'+j, option), 11, false, 18)
+		}
+		flow.y += 12
+	}
+
+	// Gabarito em página separada
+	flow.newPage()
+	flow.write(fmt.Sprintf("Gabarito — %s", deck.Name), 16, true, 0)
+	flow.y += 8
+	for i, question := range questions {
+		if question.correct >= 0 && question.correct < len(question.options) {
+			flow.write(fmt.Sprintf("%d. %c) %s", i+1, 'A'+question.correct, question.options[question.correct]), 11, false, 0)
+		} else {
+			flow.write(fmt.Sprintf("%d. %s", i+1, question.answer), 11, false, 0)
+		}
+	}
+
+	return flow.doc
+}
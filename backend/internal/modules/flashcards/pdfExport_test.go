@@ -0,0 +1,72 @@
+package flashcards
+
+import (
+	"testing"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGridCellMirrorsBackColumns(t *testing.T) {
+	cases := []struct {
+		i, row, front, back int
+	}{
+		{0, 0, 0, 1},
+		{1, 0, 1, 0},
+		{2, 1, 0, 1},
+		{7, 3, 1, 0},
+	}
+	for _, tc := range cases {
+		row, col := gridCell(tc.i, false)
+		assert.Equal(t, tc.row, row)
+		assert.Equal(t, tc.front, col)
+		row, col = gridCell(tc.i, true)
+		assert.Equal(t, tc.row, row)
+		assert.Equal(t, tc.back, col, "card %d", tc.i)
+	}
+}
+
+func TestBuildQuizQuestions(t *testing.T) {
+	correct := 2
+	cards := []entities.Flashcard{
+		{Question: "Capital da França?", Alternatives: []string{"Roma", "Madri", "Paris"}, CorrectAlternative: &correct},
+		{Question: "2+2", Answer: "4"},
+		{Question: "3+3", Answer: "6"},
+		{Question: "4+4", Answer: "8"},
+		{Question: "5+5", Answer: "10"},
+	}
+
+	questions := buildQuizQuestions("deck", cards)
+	require.Len(t, questions, 5)
+
+	// Múltipla escolha mantém as alternativas do card
+	assert.Equal(t, cards[0].Alternatives, questions[0].options)
+	assert.Equal(t, 2, questions[0].correct)
+
+	// Cards abertos ganham três distratores e o gabarito aponta a resposta
+	for i, question := range questions[1:] {
+		require.Len(t, question.options, 4)
+		assert.Equal(t, cards[i+1].Answer, question.options[question.correct])
+		assert.Equal(t, cards[i+1].Answer, question.answer)
+	}
+
+	// A mesma semente gera a mesma prova
+	assert.Equal(t, questions, buildQuizQuestions("deck", cards))
+}
+
+func TestBuildQuizQuestionsWithFewDistractors(t *testing.T) {
+	cards := []entities.Flashcard{
+		{Question: "2+2", Answer: "4"},
+		{Question: "3+3", Answer: "6"},
+		{Question: "Dobro de 3", Answer: "6"}, // repetida não conta como distrator
+	}
+
+	for _, question := range buildQuizQuestions("deck", cards) {
+		// Sem três distratores a questão fica aberta, com a resposta no gabarito
+		assert.Empty(t, question.options)
+		assert.Equal(t, -1, question.correct)
+		assert.NotEmpty(t, question.answer)
+	}
+}
@@ -0,0 +1,136 @@
+package flashcards
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StartQuiz sorteia um quiz do deck
+// POST /api/decks/:id/quiz
+func (h *Handler) StartQuiz(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req QuizRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	attempt, err := h.service.StartQuiz(userID, c.Param("id"), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, attempt)
+}
+
+// GetDeckQuizResults lista as tentativas de quiz feitas no deck
+// GET /api/decks/:id/quiz/results?since=
+func (h *Handler) GetDeckQuizResults(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	since, err := queryDate(c, "since", false)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var from time.Time
+	if since != nil {
+		from = *since
+	}
+
+	attempts, err := h.service.GetDeckQuizResults(userID, c.Param("id"), from)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"attempts": attempts})
+}
+
+// GetQuizAttempts lista as tentativas de quiz do usuário
+// GET /api/quiz?deck_id=
+func (h *Handler) GetQuizAttempts(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	attempts, err := h.service.GetQuizAttempts(userID, c.Query("deck_id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"attempts": attempts})
+}
+
+// GetQuiz retorna a tentativa em andamento ou o relatório, se encerrada
+// GET /api/quiz/:id
+func (h *Handler) GetQuiz(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	quiz, err := h.service.GetQuiz(userID, c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quiz)
+}
+
+// AnswerQuiz responde uma questão do quiz
+// POST /api/quiz/:id/answer
+func (h *Handler) AnswerQuiz(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		CardID      string `json:"card_id" binding:"required"`
+		Alternative *int   `json:"alternative"` // questões de múltipla escolha
+		Answer      string `json:"answer"`      // questões abertas
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.AnswerQuiz(userID, c.Param("id"), req.CardID, req.Alternative, req.Answer)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// FinishQuiz entrega o quiz e devolve o relatório com a correção
+// POST /api/quiz/:id/finish
+func (h *Handler) FinishQuiz(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.service.FinishQuiz(userID, c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,139 @@
+package flashcards
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SampleFlashcards sorteia até size cards dos decks. Com multipleChoiceOnly,
+// só entram cards com alternativas.
+func (r *MongoRepository) SampleFlashcards(deckIDs []string, size int, multipleChoiceOnly bool) ([]entities.Flashcard, error) {
+	match := bson.M{"deckId": bson.M{"$in": deckIDs}}
+	if multipleChoiceOnly {
+		match["alternatives.1"] = bson.M{"$exists": true}
+		match["correctAlternative"] = bson.M{"$exists": true}
+	}
+	ctx := context.Background()
+	cursor, err := r.collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$sample", Value: bson.M{"size": size}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	cards := []entities.Flashcard{}
+	if err := cursor.All(ctx, &cards); err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
+
+func (r *MongoRepository) CreateQuizAttempt(attempt *entities.QuizAttempt) error {
+	result, err := r.db.GetCollection("quiz_attempts").InsertOne(context.Background(), attempt)
+	if err != nil {
+		return err
+	}
+	attempt.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *MongoRepository) GetQuizAttempt(id primitive.ObjectID) (*entities.QuizAttempt, error) {
+	var attempt entities.QuizAttempt
+	err := r.db.GetCollection("quiz_attempts").FindOne(context.Background(), bson.M{"_id": id}).Decode(&attempt)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrQuizNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// RecordQuizResponse grava a resposta da questão se a tentativa ainda estiver
+// em andamento e a questão não tiver sido respondida. Retorna false caso
+// contrário.
+func (r *MongoRepository) RecordQuizResponse(id primitive.ObjectID, index int, response entities.QuizResponse) (bool, error) {
+	field := fmt.Sprintf("questions.%d.response", index)
+	result, err := r.db.GetCollection("quiz_attempts").UpdateOne(context.Background(),
+		bson.M{"_id": id, "status": entities.QuizInProgress, field: bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{field: response}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// FinishQuizAttempt grava o resultado de uma tentativa ainda em andamento.
+// Retorna false se ela já tinha sido encerrada.
+func (r *MongoRepository) FinishQuizAttempt(attempt *entities.QuizAttempt) (bool, error) {
+	result, err := r.db.GetCollection("quiz_attempts").UpdateOne(context.Background(),
+		bson.M{"_id": attempt.ID, "status": entities.QuizInProgress},
+		bson.M{"$set": bson.M{
+			"status":           attempt.Status,
+			"finished_at":      attempt.FinishedAt,
+			"duration_seconds": attempt.DurationSeconds,
+			"correct_count":    attempt.CorrectCount,
+			"score":            attempt.Score,
+		}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// GetOverdueQuizAttempts lista as tentativas que casam com o filtro e ainda
+// estão em andamento com o prazo vencido em now
+func (r *MongoRepository) GetOverdueQuizAttempts(filter bson.M, now time.Time, limit int64) ([]entities.QuizAttempt, error) {
+	query := bson.M{"status": entities.QuizInProgress, "deadline": bson.M{"$lt": now}}
+	for key, value := range filter {
+		query[key] = value
+	}
+	ctx := context.Background()
+	cursor, err := r.db.GetCollection("quiz_attempts").Find(ctx, query, options.Find().SetLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	attempts := []entities.QuizAttempt{}
+	if err := cursor.All(ctx, &attempts); err != nil {
+		return nil, err
+	}
+	return attempts, nil
+}
+
+// GetQuizAttempts lista as tentativas mais recentes que casam com o filtro,
+// sem as questões
+func (r *MongoRepository) GetQuizAttempts(filter bson.M, since time.Time, limit int64) ([]entities.QuizAttempt, error) {
+	if !since.IsZero() {
+		filter["started_at"] = bson.M{"$gte": since}
+	}
+	ctx := context.Background()
+	opts := options.Find().
+		SetSort(bson.M{"started_at": -1}).
+		SetLimit(limit).
+		SetProjection(bson.M{"questions": 0})
+	cursor, err := r.db.GetCollection("quiz_attempts").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	attempts := []entities.QuizAttempt{}
+	if err := cursor.All(ctx, &attempts); err != nil {
+		return nil, err
+	}
+	return attempts, nil
+}
@@ -0,0 +1,393 @@
+package flashcards
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrQuizNotFound        = errors.New("quiz attempt not found")
+	ErrQuizClosed          = errors.New("quiz attempt has already ended")
+	ErrQuizTimeUp          = errors.New("quiz time limit has run out")
+	ErrQuizQuestionMissing = errors.New("card is not part of this quiz")
+	ErrQuizAlreadyAnswered = errors.New("question has already been answered")
+)
+
+const (
+	defaultQuizQuestions = 10
+	maxQuizQuestions     = 100
+	// maxQuizTimeLimit limita o tempo que pode ser dado a um quiz, em segundos
+	maxQuizTimeLimit = 3 * 60 * 60
+	maxQuizAttempts  = 100
+	quizExpiryBatch  = 100
+)
+
+// QuizRequest descreve o quiz a ser sorteado
+type QuizRequest struct {
+	Count              int  `json:"count"`                // questões sorteadas
+	MultipleChoiceOnly bool `json:"multiple_choice_only"` // só cards com alternativas
+	TimeLimitSeconds   int  `json:"time_limit_seconds"`   // 0 = sem limite
+}
+
+// QuizAnswerResult é devolvido a cada resposta; a correção só aparece no
+// relatório final
+type QuizAnswerResult struct {
+	CardID    string `json:"card_id"`
+	Answered  int    `json:"answered"`
+	Remaining int    `json:"remaining"`
+}
+
+// QuizReport é o resultado de uma tentativa encerrada
+type QuizReport struct {
+	Attempt   *entities.QuizAttempt `json:"attempt"`
+	Questions []QuizQuestionResult  `json:"questions"`
+}
+
+// QuizQuestionResult mostra a resposta dada, a correta e o tempo gasto numa
+// questão
+type QuizQuestionResult struct {
+	CardID             string   `json:"card_id"`
+	Question           string   `json:"question"`
+	Alternatives       []string `json:"alternatives,omitempty"`
+	CorrectAnswer      string   `json:"correct_answer"`
+	CorrectAlternative *int     `json:"correct_alternative,omitempty"`
+	Answered           bool     `json:"answered"`
+	GivenAlternative   *int     `json:"given_alternative,omitempty"`
+	GivenAnswer        string   `json:"given_answer,omitempty"`
+	IsCorrect          bool     `json:"is_correct"`
+	TimeSpent          int      `json:"time_spent"` // em segundos
+}
+
+// StartQuiz sorteia as questões do deck e de seus subdecks e abre uma
+// tentativa. O prazo, quando há limite de tempo, conta a partir daqui.
+func (s *Service) StartQuiz(userID, deckID string, req QuizRequest) (*entities.QuizAttempt, error) {
+	count := req.Count
+	if count <= 0 {
+		count = defaultQuizQuestions
+	}
+	if count > maxQuizQuestions {
+		return nil, fmt.Errorf("a quiz can have at most %d questions", maxQuizQuestions)
+	}
+	if req.TimeLimitSeconds < 0 || req.TimeLimitSeconds > maxQuizTimeLimit {
+		return nil, fmt.Errorf("time_limit_seconds must be between 0 and %d", maxQuizTimeLimit)
+	}
+
+	deck, err := s.getReadableDeck(userID, deckID)
+	if err != nil {
+		return nil, err
+	}
+	descendants, err := s.repo.GetDescendantDecks(deck.UserID, deck.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subdecks: %w", err)
+	}
+	deckIDs := []string{deck.ID.Hex()}
+	for _, descendant := range descendants {
+		if deck.Role == "" && !descendant.IsPublic {
+			continue
+		}
+		deckIDs = append(deckIDs, descendant.ID.Hex())
+	}
+
+	cards, err := s.repo.SampleFlashcards(deckIDs, count, req.MultipleChoiceOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample cards: %w", err)
+	}
+	if len(cards) == 0 {
+		if req.MultipleChoiceOnly {
+			return nil, fmt.Errorf("deck has no multiple-choice cards")
+		}
+		return nil, fmt.Errorf("deck has no cards")
+	}
+
+	now := time.Now()
+	attempt := &entities.QuizAttempt{
+		UserID:             userID,
+		DeckID:             deck.ID.Hex(),
+		Status:             entities.QuizInProgress,
+		MultipleChoiceOnly: req.MultipleChoiceOnly,
+		TimeLimitSeconds:   req.TimeLimitSeconds,
+		Questions:          make([]entities.QuizQuestion, len(cards)),
+		StartedAt:          now,
+	}
+	if req.TimeLimitSeconds > 0 {
+		deadline := now.Add(time.Duration(req.TimeLimitSeconds) * time.Second)
+		attempt.Deadline = &deadline
+	}
+	for i, card := range cards {
+		attempt.Questions[i] = entities.QuizQuestion{
+			CardID:             card.ID.Hex(),
+			Question:           card.Question,
+			Alternatives:       card.Alternatives,
+			ImageURL:           card.ImageURL,
+			AudioURL:           card.AudioURL,
+			Answer:             card.Answer,
+			CorrectAlternative: card.CorrectAlternative,
+		}
+	}
+	if err := s.repo.CreateQuizAttempt(attempt); err != nil {
+		return nil, fmt.Errorf("failed to create quiz attempt: %w", err)
+	}
+	return attempt, nil
+}
+
+// getQuizAttempt retorna a tentativa do usuário. Uma tentativa com o prazo
+// vencido é encerrada como expirada nesse momento.
+func (s *Service) getQuizAttempt(userID, attemptID string) (*entities.QuizAttempt, error) {
+	id, err := primitive.ObjectIDFromHex(attemptID)
+	if err != nil {
+		return nil, ErrQuizNotFound
+	}
+	attempt, err := s.repo.GetQuizAttempt(id)
+	if err != nil {
+		return nil, err
+	}
+	if attempt.UserID != userID {
+		return nil, ErrQuizNotFound
+	}
+	if attempt.Status == entities.QuizInProgress && attempt.Deadline != nil && time.Now().After(*attempt.Deadline) {
+		if err := s.finishQuizAttempt(attempt, entities.QuizExpired, *attempt.Deadline); err != nil && !errors.Is(err, ErrQuizClosed) {
+			return nil, err
+		}
+		return s.repo.GetQuizAttempt(id)
+	}
+	return attempt, nil
+}
+
+// AnswerQuiz grava a resposta de uma questão: alternative nas de múltipla
+// escolha, text nas abertas. Cada questão aceita uma única resposta.
+func (s *Service) AnswerQuiz(userID, attemptID, cardID string, alternative *int, text string) (*QuizAnswerResult, error) {
+	attempt, err := s.getQuizAttempt(userID, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	switch attempt.Status {
+	case entities.QuizInProgress:
+	case entities.QuizExpired:
+		return nil, ErrQuizTimeUp
+	default:
+		return nil, ErrQuizClosed
+	}
+
+	index := -1
+	for i, question := range attempt.Questions {
+		if question.CardID == cardID {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, ErrQuizQuestionMissing
+	}
+	question := attempt.Questions[index]
+	if question.Response != nil {
+		return nil, ErrQuizAlreadyAnswered
+	}
+
+	now := time.Now()
+	response := entities.QuizResponse{AnsweredAt: now, TimeSpent: quizTimeSpent(attempt, now)}
+	if len(question.Alternatives) > 0 {
+		if alternative == nil || *alternative < 0 || *alternative >= len(question.Alternatives) {
+			return nil, fmt.Errorf("alternative must be between 0 and %d", len(question.Alternatives)-1)
+		}
+		response.Alternative = alternative
+	} else {
+		response.Text = text
+	}
+	response.IsCorrect = gradeQuizResponse(question, response)
+
+	recorded, err := s.repo.RecordQuizResponse(attempt.ID, index, response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record answer: %w", err)
+	}
+	if !recorded {
+		return nil, ErrQuizAlreadyAnswered
+	}
+
+	answered := 1
+	for _, other := range attempt.Questions {
+		if other.Response != nil {
+			answered++
+		}
+	}
+	return &QuizAnswerResult{CardID: cardID, Answered: answered, Remaining: len(attempt.Questions) - answered}, nil
+}
+
+// FinishQuiz entrega a tentativa e devolve o relatório. Questões sem resposta
+// contam como erradas.
+func (s *Service) FinishQuiz(userID, attemptID string) (*QuizReport, error) {
+	attempt, err := s.getQuizAttempt(userID, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	if attempt.Status != entities.QuizInProgress {
+		return nil, ErrQuizClosed
+	}
+	if err := s.finishQuizAttempt(attempt, entities.QuizCompleted, time.Now()); err != nil {
+		return nil, err
+	}
+	return buildQuizReport(attempt), nil
+}
+
+// GetQuiz retorna a tentativa em andamento, sem as respostas certas, ou o
+// relatório de uma tentativa encerrada
+func (s *Service) GetQuiz(userID, attemptID string) (interface{}, error) {
+	attempt, err := s.getQuizAttempt(userID, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	if attempt.Status == entities.QuizInProgress {
+		return attempt, nil
+	}
+	return buildQuizReport(attempt), nil
+}
+
+// GetQuizAttempts lista as tentativas do usuário, de um deck ou de todos
+func (s *Service) GetQuizAttempts(userID, deckID string) ([]entities.QuizAttempt, error) {
+	filter := bson.M{"user_id": userID}
+	if deckID != "" {
+		filter["deck_id"] = deckID
+	}
+	if err := s.expireOverdueQuizAttempts(filter, time.Now()); err != nil {
+		return nil, err
+	}
+	attempts, err := s.repo.GetQuizAttempts(filter, time.Time{}, maxQuizAttempts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quiz attempts: %w", err)
+	}
+	return attempts, nil
+}
+
+// GetDeckQuizResults lista as tentativas de todos os alunos num deck, para
+// quem pode editá-lo
+func (s *Service) GetDeckQuizResults(userID, deckID string, since time.Time) ([]entities.QuizAttempt, error) {
+	deck, err := s.getEditableDeck(userID, deckID)
+	if err != nil {
+		return nil, err
+	}
+	filter := bson.M{"deck_id": deck.ID.Hex()}
+	if err := s.expireOverdueQuizAttempts(filter, time.Now()); err != nil {
+		return nil, err
+	}
+	attempts, err := s.repo.GetQuizAttempts(filter, since, maxQuizAttempts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quiz attempts: %w", err)
+	}
+	return attempts, nil
+}
+
+// expireOverdueQuizAttempts encerra como expiradas as tentativas do filtro
+// que ainda estão em andamento com o prazo vencido, para as listagens não as
+// mostrarem como em andamento
+func (s *Service) expireOverdueQuizAttempts(filter bson.M, now time.Time) error {
+	for {
+		attempts, err := s.repo.GetOverdueQuizAttempts(filter, now, quizExpiryBatch)
+		if err != nil {
+			return fmt.Errorf("failed to get overdue quiz attempts: %w", err)
+		}
+		for i := range attempts {
+			err := s.finishQuizAttempt(&attempts[i], entities.QuizExpired, *attempts[i].Deadline)
+			if err != nil && !errors.Is(err, ErrQuizClosed) {
+				return err
+			}
+		}
+		if len(attempts) < quizExpiryBatch {
+			return nil
+		}
+	}
+}
+
+func (s *Service) finishQuizAttempt(attempt *entities.QuizAttempt, status string, endTime time.Time) error {
+	summarizeQuizAttempt(attempt, status, endTime)
+	finished, err := s.repo.FinishQuizAttempt(attempt)
+	if err != nil {
+		return fmt.Errorf("failed to finish quiz attempt: %w", err)
+	}
+	if !finished {
+		return ErrQuizClosed
+	}
+	return nil
+}
+
+// gradeQuizResponse corrige uma resposta. Nas questões abertas a comparação
+// ignora maiúsculas, acentos e pontuação.
+func gradeQuizResponse(question entities.QuizQuestion, response entities.QuizResponse) bool {
+	if len(question.Alternatives) > 0 {
+		return response.Alternative != nil && question.CorrectAlternative != nil &&
+			*response.Alternative == *question.CorrectAlternative
+	}
+	expected := normalizeCardText(question.Answer)
+	return expected != "" && normalizeCardText(response.Text) == expected
+}
+
+// quizTimeSpent é o tempo desde a resposta anterior ou, na primeira, desde o
+// início da tentativa
+func quizTimeSpent(attempt *entities.QuizAttempt, now time.Time) int {
+	since := attempt.StartedAt
+	for _, question := range attempt.Questions {
+		if question.Response != nil && question.Response.AnsweredAt.After(since) {
+			since = question.Response.AnsweredAt
+		}
+	}
+	seconds := int(now.Sub(since).Seconds())
+	if seconds < 0 {
+		return 0
+	}
+	return seconds
+}
+
+// summarizeQuizAttempt calcula duração, acertos e nota sobre todas as
+// questões sorteadas
+func summarizeQuizAttempt(attempt *entities.QuizAttempt, status string, endTime time.Time) {
+	if endTime.Before(attempt.StartedAt) {
+		endTime = attempt.StartedAt
+	}
+	attempt.Status = status
+	attempt.FinishedAt = &endTime
+	attempt.DurationSeconds = int(endTime.Sub(attempt.StartedAt).Seconds())
+
+	attempt.CorrectCount = 0
+	for _, question := range attempt.Questions {
+		if question.Response != nil && question.Response.IsCorrect {
+			attempt.CorrectCount++
+		}
+	}
+	attempt.Score = 0
+	if len(attempt.Questions) > 0 {
+		attempt.Score = float64(attempt.CorrectCount) * 100 / float64(len(attempt.Questions))
+	}
+}
+
+func buildQuizReport(attempt *entities.QuizAttempt) *QuizReport {
+	report := &QuizReport{Questions: make([]QuizQuestionResult, len(attempt.Questions))}
+	for i, question := range attempt.Questions {
+		result := QuizQuestionResult{
+			CardID:             question.CardID,
+			Question:           question.Question,
+			Alternatives:       question.Alternatives,
+			CorrectAnswer:      question.Answer,
+			CorrectAlternative: question.CorrectAlternative,
+		}
+		if question.CorrectAlternative != nil && *question.CorrectAlternative >= 0 && *question.CorrectAlternative < len(question.Alternatives) {
+			result.CorrectAnswer = question.Alternatives[*question.CorrectAlternative]
+		}
+		if response := question.Response; response != nil {
+			result.Answered = true
+			result.GivenAlternative = response.Alternative
+			result.GivenAnswer = response.Text
+			result.IsCorrect = response.IsCorrect
+			result.TimeSpent = response.TimeSpent
+		}
+		report.Questions[i] = result
+	}
+
+	summary := *attempt
+	summary.Questions = nil
+	report.Attempt = &summary
+	return report
+}
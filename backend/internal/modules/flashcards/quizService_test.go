@@ -0,0 +1,108 @@
+package flashcards
+
+import (
+	"testing"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+	"flashcard-backend/internal/infrastructure/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestGradeQuizResponse(t *testing.T) {
+	correct, wrong := 1, 0
+	choice := entities.QuizQuestion{Alternatives: []string{"Lisboa", "Porto"}, CorrectAlternative: &correct}
+	assert.True(t, gradeQuizResponse(choice, entities.QuizResponse{Alternative: &correct}))
+	assert.False(t, gradeQuizResponse(choice, entities.QuizResponse{Alternative: &wrong}))
+	assert.False(t, gradeQuizResponse(choice, entities.QuizResponse{}))
+
+	// Questões abertas ignoram maiúsculas, acentos e pontuação
+	open := entities.QuizQuestion{Answer: "São Paulo"}
+	assert.True(t, gradeQuizResponse(open, entities.QuizResponse{Text: "sao paulo!"}))
+	assert.False(t, gradeQuizResponse(open, entities.QuizResponse{Text: "Rio"}))
+	assert.False(t, gradeQuizResponse(entities.QuizQuestion{}, entities.QuizResponse{}))
+}
+
+func TestQuizTimeSpent(t *testing.T) {
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	attempt := &entities.QuizAttempt{StartedAt: start, Questions: make([]entities.QuizQuestion, 2)}
+	assert.Equal(t, 20, quizTimeSpent(attempt, start.Add(20*time.Second)))
+
+	attempt.Questions[1].Response = &entities.QuizResponse{AnsweredAt: start.Add(20 * time.Second)}
+	assert.Equal(t, 15, quizTimeSpent(attempt, start.Add(35*time.Second)))
+}
+
+func TestSummarizeQuizAttempt(t *testing.T) {
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	correct := 0
+	attempt := &entities.QuizAttempt{
+		StartedAt: start,
+		Questions: []entities.QuizQuestion{
+			{Answer: "a", Response: &entities.QuizResponse{Text: "a", IsCorrect: true, TimeSpent: 30}},
+			{Answer: "b", Response: &entities.QuizResponse{Text: "c"}},
+			{Alternatives: []string{"x", "y"}, CorrectAlternative: &correct}, // sem resposta conta como erro
+			{Answer: "d", Response: &entities.QuizResponse{Text: "d", IsCorrect: true}},
+		},
+	}
+
+	summarizeQuizAttempt(attempt, entities.QuizExpired, start.Add(90*time.Second))
+	assert.Equal(t, entities.QuizExpired, attempt.Status)
+	assert.Equal(t, 2, attempt.CorrectCount)
+	assert.Equal(t, 50.0, attempt.Score)
+	assert.Equal(t, 90, attempt.DurationSeconds)
+
+	report := buildQuizReport(attempt)
+	assert.Nil(t, report.Attempt.Questions)
+	assert.Len(t, attempt.Questions, 4)
+	assert.Equal(t, 30, report.Questions[0].TimeSpent)
+	assert.False(t, report.Questions[2].Answered)
+	assert.Equal(t, "x", report.Questions[2].CorrectAnswer)
+}
+
+func TestGetQuizAttemptsExpiresOverdue(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("overdue attempt", func(mt *mtest.T) {
+		db := &database.MongoDB{Client: mt.Client, Database: mt.DB}
+		service := NewService(NewMongoRepository(db), nil, nil, nil, nil)
+
+		start := time.Now().Add(-time.Hour)
+		deadline := start.Add(10 * time.Minute)
+		overdue := entities.QuizAttempt{
+			ID:        primitive.NewObjectID(),
+			UserID:    "user",
+			DeckID:    "deck",
+			Status:    entities.QuizInProgress,
+			StartedAt: start,
+			Deadline:  &deadline,
+			Questions: []entities.QuizQuestion{{Answer: "a", Response: &entities.QuizResponse{Text: "a", IsCorrect: true}}},
+		}
+		expired := overdue
+		expired.Status = entities.QuizExpired
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "db.quiz_attempts", mtest.FirstBatch, toBSONDocument(mt, overdue)),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+			mtest.CreateCursorResponse(0, "db.quiz_attempts", mtest.FirstBatch, toBSONDocument(mt, expired)),
+		)
+
+		attempts, err := service.GetQuizAttempts("user", "deck")
+		require.NoError(mt, err)
+		require.Len(mt, attempts, 1)
+		assert.Equal(mt, entities.QuizExpired, attempts[0].Status)
+
+		mt.GetStartedEvent()
+		update := mt.GetStartedEvent()
+		require.NotNil(mt, update)
+		assert.Equal(mt, "update", update.CommandName)
+		set := update.Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("u", "$set").Document()
+		assert.Equal(mt, entities.QuizExpired, set.Lookup("status").StringValue())
+		// A duração vai até o prazo, não até a listagem
+		assert.Equal(mt, int32(600), set.Lookup("duration_seconds").Int32())
+	})
+}
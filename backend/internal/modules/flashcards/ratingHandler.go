@@ -0,0 +1,110 @@
+package flashcards
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateDeck cria ou atualiza a avaliação do usuário no deck
+// PUT /api/decks/:id/rating
+func (h *Handler) RateDeck(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Rating int    `json:"rating" binding:"required"`
+		Review string `json:"review"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rating, err := h.service.RateDeck(userID, c.Param("id"), req.Rating, req.Review)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rating)
+}
+
+// DeleteMyRating remove a avaliação do usuário no deck
+// DELETE /api/decks/:id/rating
+func (h *Handler) DeleteMyRating(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteMyRating(userID, c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Rating deleted successfully"})
+}
+
+// GetDeckRatings lista as avaliações do deck com o resumo das notas
+// GET /api/decks/:id/ratings?limit=...&skip=...
+func (h *Handler) GetDeckRatings(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "0"), 10, 64)
+	skip, _ := strconv.ParseInt(c.DefaultQuery("skip", "0"), 10, 64)
+	ratings, err := h.service.GetDeckRatings(userID, c.Param("id"), skip, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ratings)
+}
+
+// ReplyToRating grava a resposta do autor a uma avaliação
+// POST /api/decks/:id/ratings/:ratingId/reply
+func (h *Handler) ReplyToRating(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Text string `json:"text" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rating, err := h.service.ReplyToRating(userID, c.Param("id"), c.Param("ratingId"), req.Text)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rating)
+}
+
+// DeleteRatingReply remove a resposta do autor
+// DELETE /api/decks/:id/ratings/:ratingId/reply
+func (h *Handler) DeleteRatingReply(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteRatingReply(userID, c.Param("id"), c.Param("ratingId")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Reply deleted successfully"})
+}
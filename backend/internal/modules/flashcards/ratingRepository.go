@@ -0,0 +1,144 @@
+package flashcards
+
+import (
+	"context"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SaveDeckRating cria ou substitui a avaliação do usuário no deck, mantendo
+// a resposta do autor e a data da primeira avaliação
+func (r *MongoRepository) SaveDeckRating(rating *entities.DeckRating) error {
+	collection := r.db.GetCollection("deck_ratings")
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	err := collection.FindOneAndUpdate(
+		context.Background(),
+		bson.M{"deck_id": rating.DeckID, "user_id": rating.UserID},
+		bson.M{
+			"$set": bson.M{
+				"user_name":  rating.UserName,
+				"rating":     rating.Rating,
+				"review":     rating.Review,
+				"updated_at": rating.UpdatedAt,
+			},
+			"$setOnInsert": bson.M{"created_at": rating.CreatedAt},
+		},
+		opts,
+	).Decode(rating)
+	return err
+}
+
+func (r *MongoRepository) GetDeckRating(deckID, userID string) (*entities.DeckRating, error) {
+	return r.findDeckRating(bson.M{"deck_id": deckID, "user_id": userID})
+}
+
+func (r *MongoRepository) GetDeckRatingByID(ratingID primitive.ObjectID) (*entities.DeckRating, error) {
+	return r.findDeckRating(bson.M{"_id": ratingID})
+}
+
+func (r *MongoRepository) findDeckRating(filter bson.M) (*entities.DeckRating, error) {
+	var rating entities.DeckRating
+	err := r.db.GetCollection("deck_ratings").FindOne(context.Background(), filter).Decode(&rating)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rating, nil
+}
+
+// GetDeckRatings lista as avaliações do deck, das mais recentes para as mais antigas
+func (r *MongoRepository) GetDeckRatings(deckID string, skip, limit int64) ([]entities.DeckRating, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "updated_at", Value: -1}}).SetSkip(skip).SetLimit(limit)
+	cursor, err := r.db.GetCollection("deck_ratings").Find(context.Background(), bson.M{"deck_id": deckID}, opts)
+	if err != nil {
+		return []entities.DeckRating{}, err
+	}
+	defer cursor.Close(context.Background())
+
+	var ratings []entities.DeckRating
+	if err = cursor.All(context.Background(), &ratings); err != nil {
+		return []entities.DeckRating{}, err
+	}
+	if ratings == nil {
+		ratings = []entities.DeckRating{}
+	}
+	return ratings, nil
+}
+
+// SetRatingReply grava a resposta do autor; nil remove a resposta
+func (r *MongoRepository) SetRatingReply(ratingID primitive.ObjectID, reply *entities.RatingReply) error {
+	update := bson.M{"$unset": bson.M{"reply": ""}}
+	if reply != nil {
+		update = bson.M{"$set": bson.M{"reply": reply}}
+	}
+	_, err := r.db.GetCollection("deck_ratings").UpdateOne(context.Background(), bson.M{"_id": ratingID}, update)
+	return err
+}
+
+func (r *MongoRepository) DeleteDeckRating(ratingID primitive.ObjectID) error {
+	_, err := r.db.GetCollection("deck_ratings").DeleteOne(context.Background(), bson.M{"_id": ratingID})
+	return err
+}
+
+func (r *MongoRepository) DeleteDeckRatings(deckID string) error {
+	_, err := r.db.GetCollection("deck_ratings").DeleteMany(context.Background(), bson.M{"deck_id": deckID})
+	return err
+}
+
+// RatingSummary resume as notas de um deck
+type RatingSummary struct {
+	Average      float64     `bson:"average" json:"average"`
+	Count        int         `bson:"count" json:"count"`
+	Distribution map[int]int `bson:"-" json:"distribution"` // quantidade por nota
+}
+
+// GetDeckRatingSummary calcula média, total e distribuição das notas
+func (r *MongoRepository) GetDeckRatingSummary(deckID string) (*RatingSummary, error) {
+	ctx := context.Background()
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"deck_id": deckID}}},
+		{{Key: "$group", Value: bson.M{"_id": "$rating", "count": bson.M{"$sum": 1}}}},
+	}
+	cursor, err := r.db.GetCollection("deck_ratings").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		Rating int `bson:"_id"`
+		Count  int `bson:"count"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+
+	summary := &RatingSummary{Distribution: map[int]int{1: 0, 2: 0, 3: 0, 4: 0, 5: 0}}
+	total := 0
+	for _, group := range groups {
+		summary.Distribution[group.Rating] = group.Count
+		summary.Count += group.Count
+		total += group.Rating * group.Count
+	}
+	if summary.Count > 0 {
+		summary.Average = float64(total) / float64(summary.Count)
+	}
+	return summary, nil
+}
+
+// SetDeckRatingStats grava no deck a média e o total usados pelo catálogo
+func (r *MongoRepository) SetDeckRatingStats(deckID primitive.ObjectID, average float64, count int) error {
+	update := bson.M{"$unset": bson.M{"ratingAverage": "", "ratingCount": ""}}
+	if count > 0 {
+		update = bson.M{"$set": bson.M{"ratingAverage": average, "ratingCount": count}}
+	}
+	_, err := r.db.GetCollection("decks").UpdateOne(context.Background(), bson.M{"_id": deckID}, update)
+	return err
+}
@@ -0,0 +1,195 @@
+package flashcards
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrRatingNotFound = errors.New("rating not found")
+
+const (
+	maxReviewLength = 2000
+	maxReplyLength  = 1000
+	// defaultRatingsPage e maxRatingsPage limitam a listagem de avaliações
+	defaultRatingsPage = 20
+	maxRatingsPage     = 100
+)
+
+// DeckRatings é uma página de avaliações com o resumo das notas do deck e a
+// avaliação do próprio usuário
+type DeckRatings struct {
+	RatingSummary
+	Mine    *entities.DeckRating  `json:"mine,omitempty"`
+	Ratings []entities.DeckRating `json:"ratings"`
+}
+
+// RateDeck cria ou atualiza a avaliação do usuário. Só decks públicos são
+// avaliados, e quem mantém o deck (dono ou editor) não avalia o próprio
+// trabalho.
+func (s *Service) RateDeck(userID, deckID string, rating int, review string) (*entities.DeckRating, error) {
+	deck, err := s.getReadableDeck(userID, deckID)
+	if err != nil {
+		return nil, err
+	}
+	if canEditDeck(deck.Role) {
+		return nil, fmt.Errorf("authors and editors cannot rate their own decks")
+	}
+	if !deck.IsPublic {
+		return nil, fmt.Errorf("only public decks can be rated")
+	}
+	if rating < 1 || rating > 5 {
+		return nil, fmt.Errorf("rating must be between 1 and 5")
+	}
+	review = strings.TrimSpace(review)
+	if len([]rune(review)) > maxReviewLength {
+		return nil, fmt.Errorf("review must have at most %d characters", maxReviewLength)
+	}
+
+	now := time.Now()
+	deckRating := &entities.DeckRating{
+		DeckID:    deck.ID.Hex(),
+		UserID:    userID,
+		Rating:    rating,
+		Review:    review,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if user, err := s.getUser(userID); err == nil {
+		deckRating.UserName = user.Name
+	}
+	if err := s.repo.SaveDeckRating(deckRating); err != nil {
+		return nil, fmt.Errorf("failed to save rating: %w", err)
+	}
+
+	s.refreshDeckRating(deck.ID)
+	return deckRating, nil
+}
+
+// DeleteMyRating remove a avaliação do usuário no deck
+func (s *Service) DeleteMyRating(userID, deckID string) error {
+	deck, err := s.getDeck(deckID)
+	if err != nil {
+		return err
+	}
+	rating, err := s.repo.GetDeckRating(deck.ID.Hex(), userID)
+	if err != nil {
+		return fmt.Errorf("failed to get rating: %w", err)
+	}
+	if rating == nil {
+		return ErrRatingNotFound
+	}
+	if err := s.repo.DeleteDeckRating(rating.ID); err != nil {
+		return fmt.Errorf("failed to delete rating: %w", err)
+	}
+
+	s.refreshDeckRating(deck.ID)
+	return nil
+}
+
+// GetDeckRatings lista as avaliações do deck, das mais recentes para as mais
+// antigas
+func (s *Service) GetDeckRatings(userID, deckID string, skip, limit int64) (*DeckRatings, error) {
+	deck, err := s.getReadableDeck(userID, deckID)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultRatingsPage
+	}
+	if limit > maxRatingsPage {
+		limit = maxRatingsPage
+	}
+	if skip < 0 {
+		skip = 0
+	}
+
+	summary, err := s.repo.GetDeckRatingSummary(deck.ID.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize ratings: %w", err)
+	}
+	ratings, err := s.repo.GetDeckRatings(deck.ID.Hex(), skip, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ratings: %w", err)
+	}
+	mine, err := s.repo.GetDeckRating(deck.ID.Hex(), userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rating: %w", err)
+	}
+
+	return &DeckRatings{RatingSummary: *summary, Mine: mine, Ratings: ratings}, nil
+}
+
+// ReplyToRating grava ou substitui a resposta do autor a uma avaliação
+func (s *Service) ReplyToRating(userID, deckID, ratingID, text string) (*entities.DeckRating, error) {
+	rating, err := s.getOwnedDeckRating(userID, deckID, ratingID)
+	if err != nil {
+		return nil, err
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, fmt.Errorf("reply text is required")
+	}
+	if len([]rune(text)) > maxReplyLength {
+		return nil, fmt.Errorf("reply must have at most %d characters", maxReplyLength)
+	}
+
+	now := time.Now()
+	reply := &entities.RatingReply{Text: text, CreatedAt: now, UpdatedAt: now}
+	if rating.Reply != nil {
+		reply.CreatedAt = rating.Reply.CreatedAt
+	}
+	if err := s.repo.SetRatingReply(rating.ID, reply); err != nil {
+		return nil, fmt.Errorf("failed to save reply: %w", err)
+	}
+	rating.Reply = reply
+	return rating, nil
+}
+
+// DeleteRatingReply remove a resposta do autor
+func (s *Service) DeleteRatingReply(userID, deckID, ratingID string) error {
+	rating, err := s.getOwnedDeckRating(userID, deckID, ratingID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.SetRatingReply(rating.ID, nil); err != nil {
+		return fmt.Errorf("failed to delete reply: %w", err)
+	}
+	return nil
+}
+
+// getOwnedDeckRating retorna uma avaliação de um deck do usuário
+func (s *Service) getOwnedDeckRating(userID, deckID, ratingID string) (*entities.DeckRating, error) {
+	deck, err := s.getOwnedDeck(userID, deckID)
+	if err != nil {
+		return nil, err
+	}
+	ratingObjectID, err := primitive.ObjectIDFromHex(ratingID)
+	if err != nil {
+		return nil, ErrRatingNotFound
+	}
+	rating, err := s.repo.GetDeckRatingByID(ratingObjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rating: %w", err)
+	}
+	if rating == nil || rating.DeckID != deck.ID.Hex() {
+		return nil, ErrRatingNotFound
+	}
+	return rating, nil
+}
+
+// refreshDeckRating recalcula a média e o total guardados no deck
+func (s *Service) refreshDeckRating(deckID primitive.ObjectID) {
+	summary, err := s.repo.GetDeckRatingSummary(deckID.Hex())
+	if err == nil {
+		err = s.repo.SetDeckRatingStats(deckID, summary.Average, summary.Count)
+	}
+	if err != nil {
+		fmt.Printf("Failed to refresh rating of deck %s: %v\n", deckID.Hex(), err)
+	}
+}
@@ -0,0 +1,111 @@
+package flashcards
+
+import (
+	"testing"
+
+	"flashcard-backend/internal/domain/entities"
+	"flashcard-backend/internal/infrastructure/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestRateDeckRejectsPrivateDecksAndEditors(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	cases := []struct {
+		name     string
+		isPublic bool
+		role     string
+		message  string
+	}{
+		{"viewer of a private deck", false, entities.DeckRoleViewer, "only public decks can be rated"},
+		{"editor of a public deck", true, entities.DeckRoleEditor, "authors and editors cannot rate their own decks"},
+	}
+	for _, tc := range cases {
+		mt.Run(tc.name, func(mt *mtest.T) {
+			db := &database.MongoDB{Client: mt.Client, Database: mt.DB}
+			service := NewService(NewMongoRepository(db), nil, nil, nil, nil)
+
+			deck := entities.Deck{ID: primitive.NewObjectID(), UserID: "owner", Name: "Inglês", IsPublic: tc.isPublic}
+			member := entities.DeckMember{DeckID: deck.ID.Hex(), UserID: "user", Role: tc.role, Status: entities.MemberActive}
+			mt.AddMockResponses(
+				mtest.CreateCursorResponse(0, "db.decks", mtest.FirstBatch, toBSONDocument(mt, deck)),
+				mtest.CreateCursorResponse(0, "db.deck_members", mtest.FirstBatch, toBSONDocument(mt, member)),
+			)
+
+			_, err := service.RateDeck("user", deck.ID.Hex(), 5, "")
+			assert.EqualError(mt, err, tc.message)
+
+			// Nada é gravado
+			for started := mt.GetStartedEvent(); started != nil; started = mt.GetStartedEvent() {
+				assert.Equal(mt, "find", started.CommandName)
+			}
+		})
+	}
+}
+
+func TestRateDeckUpsertsAndRefreshesSummary(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("public deck", func(mt *mtest.T) {
+		db := &database.MongoDB{Client: mt.Client, Database: mt.DB}
+		service := NewService(NewMongoRepository(db), nil, nil, nil, nil)
+
+		deck := entities.Deck{ID: primitive.NewObjectID(), UserID: "owner", Name: "Inglês", IsPublic: true}
+		saved := entities.DeckRating{ID: primitive.NewObjectID(), DeckID: deck.ID.Hex(), UserID: "user", Rating: 5, Review: "Ótimo"}
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "db.decks", mtest.FirstBatch, toBSONDocument(mt, deck)),
+			mtest.CreateCursorResponse(0, "db.deck_members", mtest.FirstBatch),
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: toBSONDocument(mt, saved)}),
+			mtest.CreateCursorResponse(0, "db.deck_ratings", mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: 5}, {Key: "count", Value: 2}},
+				bson.D{{Key: "_id", Value: 2}, {Key: "count", Value: 1}},
+			),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+		)
+
+		rating, err := service.RateDeck("user", deck.ID.Hex(), 5, "  Ótimo  ")
+		require.NoError(mt, err)
+		assert.Equal(mt, saved.ID, rating.ID)
+
+		mt.GetStartedEvent() // deck
+		mt.GetStartedEvent() // membros
+		upsert := mt.GetStartedEvent()
+		require.NotNil(mt, upsert)
+		assert.Equal(mt, "findAndModify", upsert.CommandName)
+		assert.True(mt, upsert.Command.Lookup("upsert").Boolean())
+		assert.Equal(mt, "Ótimo", upsert.Command.Lookup("update", "$set", "review").StringValue())
+
+		mt.GetStartedEvent() // resumo
+		stats := mt.GetStartedEvent()
+		require.NotNil(mt, stats)
+		set := stats.Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("u", "$set").Document()
+		assert.Equal(mt, 4.0, set.Lookup("ratingAverage").Double())
+		assert.Equal(mt, int32(3), set.Lookup("ratingCount").Int32())
+	})
+}
+
+func TestGetDeckRatingSummary(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("distribution", func(mt *mtest.T) {
+		repo := NewMongoRepository(&database.MongoDB{Client: mt.Client, Database: mt.DB})
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.deck_ratings", mtest.FirstBatch,
+			bson.D{{Key: "_id", Value: 4}, {Key: "count", Value: 3}},
+			bson.D{{Key: "_id", Value: 1}, {Key: "count", Value: 1}},
+		))
+
+		summary, err := repo.GetDeckRatingSummary("deck")
+		require.NoError(mt, err)
+		assert.Equal(mt, 4, summary.Count)
+		assert.Equal(mt, 3.25, summary.Average)
+		assert.Equal(mt, map[int]int{1: 1, 2: 0, 3: 0, 4: 3, 5: 0}, summary.Distribution)
+	})
+}
@@ -37,22 +37,27 @@ func NewRepository(db *database.MongoDB) *Repository {
 }
 
 type CardDocument struct {
-	ID                 primitive.ObjectID `bson:"_id,omitempty"`
-	DeckID             string             `bson:"deckId"`
-	UserID             primitive.ObjectID `bson:"userId"`
-	Question           string             `bson:"question"`
-	Answer             string             `bson:"answer"`
-	Alternatives       []string           `bson:"alternatives,omitempty"`
-	CorrectAlternative *int               `bson:"correctAlternative,omitempty"`
-	ImageURL           *string            `bson:"imageUrl,omitempty"`
-	AudioURL           *string            `bson:"audioUrl,omitempty"`
-	Tags               []string           `bson:"tags,omitempty"`
-	Difficulty         int                `bson:"difficulty"`
-	ReviewCount        int                `bson:"reviewCount"`
-	LastReviewed       *time.Time         `bson:"lastReviewed,omitempty"`
-	NextReview         *time.Time         `bson:"nextReview,omitempty"`
-	CreatedAt          time.Time          `bson:"createdAt"`
-	UpdatedAt          time.Time          `bson:"updatedAt"`
+	ID                 primitive.ObjectID   `bson:"_id,omitempty"`
+	DeckID             string               `bson:"deckId"`
+	UserID             primitive.ObjectID   `bson:"userId"`
+	Question           string               `bson:"question"`
+	Answer             string               `bson:"answer"`
+	Alternatives       []string             `bson:"alternatives,omitempty"`
+	CorrectAlternative *int                 `bson:"correctAlternative,omitempty"`
+	ImageURL           *string              `bson:"imageUrl,omitempty"`
+	AudioURL           *string              `bson:"audioUrl,omitempty"`
+	Tags               []string             `bson:"tags,omitempty"`
+	Difficulty         int                  `bson:"difficulty"`
+	ReviewCount        int                  `bson:"reviewCount"`
+	LastReviewed       *time.Time           `bson:"lastReviewed,omitempty"`
+	NextReview         *time.Time           `bson:"nextReview,omitempty"`
+	Lapses             int                  `bson:"lapses,omitempty"`
+	Ease               float64              `bson:"ease,omitempty"`
+	Suspended          bool                 `bson:"suspended,omitempty"`
+	FilteredSession    string               `bson:"filteredSession,omitempty"`
+	Origin             *entities.CardOrigin `bson:"origin,omitempty"`
+	CreatedAt          time.Time            `bson:"createdAt"`
+	UpdatedAt          time.Time            `bson:"updatedAt"`
 }
 
 func (r *MongoRepository) Create(ctx context.Context, card *entities.Flashcard) error {
@@ -68,6 +73,7 @@ func (r *MongoRepository) Create(ctx context.Context, card *entities.Flashcard)
 		Tags:               card.Tags,
 		Difficulty:         card.Difficulty,
 		ReviewCount:        0,
+		Origin:             card.Origin,
 		CreatedAt:          time.Now(),
 		UpdatedAt:          time.Now(),
 	}
@@ -210,7 +216,9 @@ func (r *MongoRepository) GetDueForReview(ctx context.Context, userID string) ([
 	// Get cards due for review
 	now := time.Now()
 	filter := bson.M{
-		"deckId": bson.M{"$in": deckIDs},
+		"deckId":          bson.M{"$in": deckIDs},
+		"suspended":       bson.M{"$ne": true},
+		"filteredSession": bson.M{"$exists": false},
 		"$or": []bson.M{
 			{"nextReview": bson.M{"$lte": now}},
 			{"nextReview": bson.M{"$exists": false}},
@@ -235,19 +243,13 @@ func (r *MongoRepository) GetDueForReview(ctx context.Context, userID string) ([
 	return cards, nil
 }
 
-func (r *MongoRepository) UpdateReview(ctx context.Context, cardID string, isCorrect bool) error {
+// UpdateReview aplica ao card uma resposta dada em now
+func (r *MongoRepository) UpdateReview(ctx context.Context, cardID string, isCorrect bool, now time.Time) error {
 	objID, err := primitive.ObjectIDFromHex(cardID)
 	if err != nil {
 		return fmt.Errorf("invalid card ID: %v", err)
 	}
 
-	// Calculate next review using spaced repetition algorithm
-	now := time.Now()
-	var nextReview time.Time
-
-	// Simple spaced repetition: 1, 3, 7, 14, 30, 90 days
-	intervals := []int{1, 3, 7, 14, 30, 90}
-
 	// Get current card to check review count
 	var doc CardDocument
 	filter := bson.M{"_id": objID}
@@ -256,31 +258,21 @@ func (r *MongoRepository) UpdateReview(ctx context.Context, cardID string, isCor
 		return fmt.Errorf("failed to find card: %v", err)
 	}
 
-	reviewCount := doc.ReviewCount
-	if isCorrect {
-		reviewCount++
-	} else {
-		// Reset to beginning if incorrect
-		reviewCount = 0
-	}
-
-	// Calculate next review interval
-	intervalIndex := reviewCount
-	if intervalIndex >= len(intervals) {
-		intervalIndex = len(intervals) - 1
-	}
-
-	days := intervals[intervalIndex]
-	nextReview = now.AddDate(0, 0, days)
+	reviewCount, nextReview := nextReviewState(doc.ReviewCount, isCorrect, now)
 
 	update := bson.M{
 		"$set": bson.M{
 			"reviewCount":  reviewCount,
 			"lastReviewed": now,
 			"nextReview":   nextReview,
-			"updatedAt":    now,
+			"ease":         nextEase(doc.Ease, isCorrect),
+			"updatedAt":    time.Now(),
 		},
 	}
+	// Errar um card que já tinha acertos conta como lapso
+	if isLapse(doc.ReviewCount, isCorrect) {
+		update["$inc"] = bson.M{"lapses": 1}
+	}
 
 	_, err = r.collection.UpdateOne(ctx, filter, update)
 	if err != nil {
@@ -351,6 +343,11 @@ func (r *MongoRepository) documentToEntity(doc *CardDocument) *entities.Flashcar
 		ReviewCount:        doc.ReviewCount,
 		LastReviewed:       doc.LastReviewed,
 		NextReview:         doc.NextReview,
+		Lapses:             doc.Lapses,
+		Ease:               doc.Ease,
+		Suspended:          doc.Suspended,
+		FilteredSession:    doc.FilteredSession,
+		Origin:             doc.Origin,
 		CreatedAt:          doc.CreatedAt,
 		UpdatedAt:          doc.UpdatedAt,
 	}
@@ -658,6 +655,15 @@ func (r *MongoRepository) CreateDeckWithStringUserID(deck *entities.Deck, userID
 		"createdAt":   deck.CreatedAt,
 		"updatedAt":   deck.UpdatedAt,
 	}
+	if deck.ParentID != "" {
+		doc["parentId"] = deck.ParentID
+	}
+	if deck.Language != "" {
+		doc["language"] = deck.Language
+	}
+	if deck.ForkedFrom != "" {
+		doc["forkedFrom"] = deck.ForkedFrom
+	}
 
 	collection := r.db.GetCollection("decks")
 	result, err := collection.InsertOne(context.Background(), doc)
@@ -667,6 +673,7 @@ func (r *MongoRepository) CreateDeckWithStringUserID(deck *entities.Deck, userID
 
 	// Atualizar o ID do deck
 	deck.ID = result.InsertedID.(primitive.ObjectID)
+	deck.UserID = userID
 	return nil
 }
 
@@ -719,12 +726,59 @@ func (r *MongoRepository) UpdateDeck(deck *entities.Deck) error {
 	return err
 }
 
+// SetOriginCardDeleted marca ou desmarca um card de origem como apagado do
+// fork
+func (r *MongoRepository) SetOriginCardDeleted(deckID, originCardID string, deleted bool) error {
+	id, err := primitive.ObjectIDFromHex(deckID)
+	if err != nil {
+		return err
+	}
+	update := bson.M{"$pull": bson.M{"deletedOriginCards": originCardID}}
+	if deleted {
+		update = bson.M{"$addToSet": bson.M{"deletedOriginCards": originCardID}}
+	}
+	_, err = r.db.GetCollection("decks").UpdateOne(context.Background(), bson.M{"_id": id}, update)
+	return err
+}
+
+// UnpublishDecks torna privados os decks informados
+func (r *MongoRepository) UnpublishDecks(deckIDs []string) error {
+	ids := make([]primitive.ObjectID, 0, len(deckIDs))
+	for _, deckID := range deckIDs {
+		if id, err := primitive.ObjectIDFromHex(deckID); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	collection := r.db.GetCollection("decks")
+	_, err := collection.UpdateMany(
+		context.Background(),
+		bson.M{"_id": bson.M{"$in": ids}, "isPublic": true},
+		bson.M{"$set": bson.M{"isPublic": false, "updatedAt": time.Now()}},
+	)
+	return err
+}
+
+func (r *MongoRepository) IncrementDeckForkCount(deckID primitive.ObjectID) error {
+	collection := r.db.GetCollection("decks")
+	_, err := collection.UpdateOne(
+		context.Background(),
+		bson.M{"_id": deckID},
+		bson.M{"$inc": bson.M{"forkCount": 1}},
+	)
+	return err
+}
+
 func (r *MongoRepository) DeleteDeck(deckID primitive.ObjectID) error {
 	collection := r.db.GetCollection("decks")
 	_, err := collection.DeleteOne(context.Background(), bson.M{"_id": deckID})
 	return err
 }
 
+func (r *MongoRepository) DeleteFlashcardsByDeckID(deckID string) error {
+	_, err := r.collection.DeleteMany(context.Background(), bson.M{"deckId": deckID})
+	return err
+}
+
 func (r *MongoRepository) CountDecksByUserID(userID primitive.ObjectID) (int64, error) {
 	collection := r.db.GetCollection("decks")
 	return collection.CountDocuments(context.Background(), bson.M{"userId": userID})
@@ -761,27 +815,19 @@ func (r *MongoRepository) CountPrivateDecksByUserEmail(userEmail string) (int64,
 }
 
 // Study session operations for MongoRepository
+// CreateStudySession grava a sessão. O ID é gerado antes quando não vem
+// preenchido, para quem criou a sessão poder continuá-la pelo ID retornado.
 func (r *MongoRepository) CreateStudySession(session *entities.StudySession) error {
 	session.StartTime = time.Now()
+	if session.ID.IsZero() {
+		session.ID = primitive.NewObjectID()
+	}
 
 	collection := r.db.GetCollection("study_sessions")
 	_, err := collection.InsertOne(context.Background(), session)
 	return err
 }
 
-func (r *MongoRepository) UpdateStudySession(session *entities.StudySession) error {
-	session.EndTime = time.Now()
-	session.Duration = int(session.EndTime.Sub(session.StartTime).Minutes())
-
-	collection := r.db.GetCollection("study_sessions")
-	_, err := collection.UpdateOne(
-		context.Background(),
-		bson.M{"_id": session.ID},
-		bson.M{"$set": session},
-	)
-	return err
-}
-
 func (r *MongoRepository) GetStudySessionsByUserID(userID primitive.ObjectID, limit int64) ([]entities.StudySession, error) {
 	collection := r.db.GetCollection("study_sessions")
 
@@ -0,0 +1,61 @@
+package flashcards
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCardRevisions lista o histórico de alterações de um card
+// GET /api/cards/:id/revisions?limit=...
+func (h *Handler) GetCardRevisions(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "0"), 10, 64)
+	revisions, err := h.service.GetCardRevisions(userID, c.Param("id"), limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+// RestoreCardRevision volta o card para o conteúdo de uma revisão
+// POST /api/cards/:id/revisions/:revisionId/restore
+func (h *Handler) RestoreCardRevision(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	card, err := h.service.RestoreCardRevision(userID, c.Param("id"), c.Param("revisionId"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, card)
+}
+
+// GetDeckChanges lista as alterações recentes nos cards de um deck
+// GET /api/decks/:id/changes?limit=...
+func (h *Handler) GetDeckChanges(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "0"), 10, 64)
+	revisions, err := h.service.GetDeckChanges(userID, c.Param("id"), limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
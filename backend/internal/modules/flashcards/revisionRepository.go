@@ -0,0 +1,74 @@
+package flashcards
+
+import (
+	"context"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (r *MongoRepository) CreateCardRevision(revision *entities.CardRevision) error {
+	collection := r.db.GetCollection("card_revisions")
+	result, err := collection.InsertOne(context.Background(), revision)
+	if err != nil {
+		return err
+	}
+	revision.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *MongoRepository) CountCardRevisions(cardID string) (int64, error) {
+	collection := r.db.GetCollection("card_revisions")
+	return collection.CountDocuments(context.Background(), bson.M{"card_id": cardID})
+}
+
+func (r *MongoRepository) GetCardRevisionByID(revisionID primitive.ObjectID) (*entities.CardRevision, error) {
+	collection := r.db.GetCollection("card_revisions")
+
+	var revision entities.CardRevision
+	err := collection.FindOne(context.Background(), bson.M{"_id": revisionID}).Decode(&revision)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func (r *MongoRepository) GetCardRevisions(cardID string, limit int64) ([]entities.CardRevision, error) {
+	return r.findCardRevisions(bson.M{"card_id": cardID}, limit)
+}
+
+// GetDeckRevisions retorna as alterações recentes do deck, sem os registros
+// de conteúdo original
+func (r *MongoRepository) GetDeckRevisions(deckID string, limit int64) ([]entities.CardRevision, error) {
+	return r.findCardRevisions(bson.M{
+		"deck_id": deckID,
+		"action":  bson.M{"$ne": entities.RevisionOriginal},
+	}, limit)
+}
+
+func (r *MongoRepository) findCardRevisions(filter bson.M, limit int64) ([]entities.CardRevision, error) {
+	collection := r.db.GetCollection("card_revisions")
+
+	opts := options.Find().SetSort(bson.M{"created_at": -1}).SetLimit(limit)
+	cursor, err := collection.Find(context.Background(), filter, opts)
+	if err != nil {
+		return []entities.CardRevision{}, err
+	}
+	defer cursor.Close(context.Background())
+
+	var revisions []entities.CardRevision
+	if err = cursor.All(context.Background(), &revisions); err != nil {
+		return []entities.CardRevision{}, err
+	}
+	if revisions == nil {
+		revisions = []entities.CardRevision{}
+	}
+	return revisions, nil
+}
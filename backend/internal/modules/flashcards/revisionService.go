@@ -0,0 +1,151 @@
+package flashcards
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const defaultRevisionLimit = 50
+
+// revisionAuthor identifica quem fez a alteração
+type revisionAuthor struct {
+	id   string
+	name string
+}
+
+// revisionAuthorFor resolve o nome do autor uma única vez por operação
+func (s *Service) revisionAuthorFor(userID string) revisionAuthor {
+	author := revisionAuthor{id: userID}
+	if s.authService != nil {
+		if user, err := s.authService.GetUserByID(userID); err == nil && user != nil {
+			author.name = user.Name
+		}
+	}
+	return author
+}
+
+// fieldChanges lista campo a campo o que mudou entre dois conteúdos
+func fieldChanges(before, after entities.CardContent) []entities.FieldChange {
+	var changes []entities.FieldChange
+	for _, field := range cardContentFields {
+		beforeValue, afterValue := field.get(&before), field.get(&after)
+		if reflect.DeepEqual(beforeValue, afterValue) {
+			continue
+		}
+		changes = append(changes, entities.FieldChange{Field: field.name, Before: beforeValue, After: afterValue})
+	}
+	return changes
+}
+
+// recordRevision registra o conteúdo atual do card. before é o conteúdo
+// anterior (nil na criação) e nada é gravado se não houve mudança. Na
+// primeira alteração de um card sem histórico o conteúdo anterior também é
+// guardado, para que o original possa ser restaurado. Falhas são apenas
+// logadas para não desfazer a alteração do card.
+func (s *Service) recordRevision(card *entities.Flashcard, before *entities.CardContent, author revisionAuthor, action, restoredFrom string) *entities.CardRevision {
+	content := cardContent(card)
+	revision := &entities.CardRevision{
+		CardID:       card.ID.Hex(),
+		DeckID:       card.DeckID,
+		AuthorID:     author.id,
+		AuthorName:   author.name,
+		Action:       action,
+		Content:      content,
+		RestoredFrom: restoredFrom,
+		CreatedAt:    time.Now(),
+	}
+
+	if before != nil {
+		revision.Changes = fieldChanges(*before, content)
+		if len(revision.Changes) == 0 {
+			return nil
+		}
+
+		count, err := s.repo.CountCardRevisions(revision.CardID)
+		if err == nil && count == 0 {
+			originalAt := card.CreatedAt
+			if card.UpdatedAt.After(originalAt) && card.UpdatedAt.Before(revision.CreatedAt) {
+				originalAt = card.UpdatedAt
+			}
+			original := &entities.CardRevision{
+				CardID:    revision.CardID,
+				DeckID:    revision.DeckID,
+				Action:    entities.RevisionOriginal,
+				Content:   *before,
+				CreatedAt: originalAt,
+			}
+			if err := s.repo.CreateCardRevision(original); err != nil {
+				fmt.Printf("Failed to record original revision for card %s: %v\n", revision.CardID, err)
+			}
+		}
+	}
+
+	if err := s.repo.CreateCardRevision(revision); err != nil {
+		fmt.Printf("Failed to record revision for card %s: %v\n", revision.CardID, err)
+		return nil
+	}
+	return revision
+}
+
+// GetCardRevisions lista as revisões de um card, da mais recente para a mais antiga
+func (s *Service) GetCardRevisions(userID, cardID string, limit int64) ([]entities.CardRevision, error) {
+	card, err := s.repo.GetByID(context.Background(), cardID)
+	if err != nil {
+		return nil, ErrCardNotFound
+	}
+	if _, err := s.getReadableDeck(userID, card.DeckID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultRevisionLimit
+	}
+	return s.repo.GetCardRevisions(cardID, limit)
+}
+
+// GetDeckChanges lista as alterações mais recentes nos cards de um deck
+func (s *Service) GetDeckChanges(userID, deckID string, limit int64) ([]entities.CardRevision, error) {
+	if _, err := s.getReadableDeck(userID, deckID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultRevisionLimit
+	}
+	return s.repo.GetDeckRevisions(deckID, limit)
+}
+
+// RestoreCardRevision volta o conteúdo do card para o de uma revisão. O
+// estado de revisão (agendamento) do card não é alterado e a restauração
+// gera uma nova revisão.
+func (s *Service) RestoreCardRevision(userID, cardID, revisionID string) (*entities.Flashcard, error) {
+	card, err := s.repo.GetByID(context.Background(), cardID)
+	if err != nil {
+		return nil, ErrCardNotFound
+	}
+	if _, err := s.getEditableDeck(userID, card.DeckID); err != nil {
+		return nil, err
+	}
+
+	revisionObjectID, err := primitive.ObjectIDFromHex(revisionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid revision ID: %w", err)
+	}
+	revision, err := s.repo.GetCardRevisionByID(revisionObjectID)
+	if err != nil || revision == nil || revision.CardID != cardID {
+		return nil, fmt.Errorf("revision not found")
+	}
+
+	before := cardContent(card)
+	applyCardContent(card, revision.Content)
+	if err := s.repo.Update(context.Background(), card); err != nil {
+		return nil, fmt.Errorf("failed to restore card: %w", err)
+	}
+	s.recordRevision(card, &before, s.revisionAuthorFor(userID), entities.RevisionRestore, revisionID)
+
+	return card, nil
+}
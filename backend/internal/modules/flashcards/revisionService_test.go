@@ -0,0 +1,162 @@
+package flashcards
+
+import (
+	"testing"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+	"flashcard-backend/internal/infrastructure/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestFieldChanges(t *testing.T) {
+	before := entities.CardContent{Question: "Capital da Austrália?", Answer: "Sydney", Tags: []string{"geo"}}
+	after := entities.CardContent{Question: "Capital da Austrália?", Answer: "Canberra", Tags: []string{"geo", "oceania"}}
+
+	changes := fieldChanges(before, after)
+	require.Len(t, changes, 2)
+	assert.Equal(t, entities.FieldChange{Field: "answer", Before: "Sydney", After: "Canberra"}, changes[0])
+	assert.Equal(t, "tags", changes[1].Field)
+
+	assert.Empty(t, fieldChanges(before, before))
+}
+
+func TestRecordRevisionSkipsUnchangedCards(t *testing.T) {
+	card := entities.Flashcard{ID: primitive.NewObjectID(), DeckID: "deck", Question: "Q", Answer: "A"}
+	before := cardContent(&card)
+
+	// Sem mudança nada é consultado nem gravado (o serviço não tem repositório)
+	s := &Service{}
+	assert.Nil(t, s.recordRevision(&card, &before, revisionAuthor{id: "user"}, entities.RevisionUpdate, ""))
+}
+
+func insertedDocument(mt *mtest.T) bson.Raw {
+	started := mt.GetStartedEvent()
+	require.NotNil(mt, started)
+	require.Equal(mt, "insert", started.CommandName)
+	return started.Command.Lookup("documents").Array().Index(0).Value().Document()
+}
+
+func TestRecordRevisionSavesOriginalOnFirstChange(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("card without history", func(mt *mtest.T) {
+		db := &database.MongoDB{Client: mt.Client, Database: mt.DB}
+		service := NewService(NewMongoRepository(db), nil, nil, nil, nil)
+
+		createdAt := time.Now().Add(-48 * time.Hour)
+		card := entities.Flashcard{ID: primitive.NewObjectID(), DeckID: "deck", Question: "Capital?", Answer: "Sydney", CreatedAt: createdAt}
+		before := cardContent(&card)
+		card.Answer = "Canberra"
+
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "db.card_revisions", mtest.FirstBatch),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(),
+		)
+
+		revision := service.recordRevision(&card, &before, revisionAuthor{id: "user"}, entities.RevisionUpdate, "")
+		require.NotNil(mt, revision)
+		require.Len(mt, revision.Changes, 1)
+		assert.Equal(mt, "answer", revision.Changes[0].Field)
+
+		mt.GetStartedEvent() // contagem das revisões
+		original := insertedDocument(mt)
+		assert.Equal(mt, entities.RevisionOriginal, original.Lookup("action").StringValue())
+		assert.Equal(mt, "Sydney", original.Lookup("content", "answer").StringValue())
+		assert.Equal(mt, createdAt.UnixMilli(), original.Lookup("created_at").Time().UnixMilli())
+
+		updated := insertedDocument(mt)
+		assert.Equal(mt, entities.RevisionUpdate, updated.Lookup("action").StringValue())
+		assert.Equal(mt, "Canberra", updated.Lookup("content", "answer").StringValue())
+	})
+
+	mt.Run("card with history", func(mt *mtest.T) {
+		db := &database.MongoDB{Client: mt.Client, Database: mt.DB}
+		service := NewService(NewMongoRepository(db), nil, nil, nil, nil)
+
+		card := entities.Flashcard{ID: primitive.NewObjectID(), DeckID: "deck", Question: "Capital?", Answer: "Sydney"}
+		before := cardContent(&card)
+		card.Answer = "Canberra"
+
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "db.card_revisions", mtest.FirstBatch, bson.D{{Key: "n", Value: 2}}),
+			mtest.CreateSuccessResponse(),
+		)
+
+		require.NotNil(mt, service.recordRevision(&card, &before, revisionAuthor{id: "user"}, entities.RevisionUpdate, ""))
+
+		mt.GetStartedEvent() // contagem das revisões
+		assert.Equal(mt, entities.RevisionUpdate, insertedDocument(mt).Lookup("action").StringValue())
+		assert.Nil(mt, mt.GetStartedEvent(), "o original só é guardado na primeira alteração")
+	})
+}
+
+func TestRestoreCardRevision(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	deck := entities.Deck{ID: primitive.NewObjectID(), UserID: "user", Name: "Geografia"}
+	card := CardDocument{ID: primitive.NewObjectID(), DeckID: deck.ID.Hex(), Question: "Capital?", Answer: "Sydney"}
+
+	mt.Run("restores the revision content", func(mt *mtest.T) {
+		db := &database.MongoDB{Client: mt.Client, Database: mt.DB}
+		service := NewService(NewMongoRepository(db), nil, nil, nil, nil)
+
+		revision := entities.CardRevision{
+			ID:      primitive.NewObjectID(),
+			CardID:  card.ID.Hex(),
+			DeckID:  deck.ID.Hex(),
+			Action:  entities.RevisionUpdate,
+			Content: entities.CardContent{Question: "Capital?", Answer: "Canberra", Tags: []string{"oceania"}},
+		}
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "db.cards", mtest.FirstBatch, toBSONDocument(mt, card)),
+			mtest.CreateCursorResponse(0, "db.decks", mtest.FirstBatch, toBSONDocument(mt, deck)),
+			mtest.CreateCursorResponse(0, "db.card_revisions", mtest.FirstBatch, toBSONDocument(mt, revision)),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+			mtest.CreateCursorResponse(0, "db.card_revisions", mtest.FirstBatch, bson.D{{Key: "n", Value: 3}}),
+			mtest.CreateSuccessResponse(),
+		)
+
+		restored, err := service.RestoreCardRevision("user", card.ID.Hex(), revision.ID.Hex())
+		require.NoError(mt, err)
+		assert.Equal(mt, "Canberra", restored.Answer)
+		assert.Equal(mt, []string{"oceania"}, restored.Tags)
+
+		mt.GetStartedEvent() // card
+		mt.GetStartedEvent() // deck
+		mt.GetStartedEvent() // revisão
+		update := mt.GetStartedEvent()
+		require.NotNil(mt, update)
+		require.Equal(mt, "update", update.CommandName)
+		set := update.Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("u", "$set").Document()
+		assert.Equal(mt, "Canberra", set.Lookup("answer").StringValue())
+
+		mt.GetStartedEvent() // contagem das revisões
+		recorded := insertedDocument(mt)
+		assert.Equal(mt, entities.RevisionRestore, recorded.Lookup("action").StringValue())
+		assert.Equal(mt, revision.ID.Hex(), recorded.Lookup("restored_from").StringValue())
+	})
+
+	mt.Run("rejects a revision of another card", func(mt *mtest.T) {
+		db := &database.MongoDB{Client: mt.Client, Database: mt.DB}
+		service := NewService(NewMongoRepository(db), nil, nil, nil, nil)
+
+		revision := entities.CardRevision{ID: primitive.NewObjectID(), CardID: primitive.NewObjectID().Hex(), Content: entities.CardContent{Question: "Outro"}}
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "db.cards", mtest.FirstBatch, toBSONDocument(mt, card)),
+			mtest.CreateCursorResponse(0, "db.decks", mtest.FirstBatch, toBSONDocument(mt, deck)),
+			mtest.CreateCursorResponse(0, "db.card_revisions", mtest.FirstBatch, toBSONDocument(mt, revision)),
+		)
+
+		_, err := service.RestoreCardRevision("user", card.ID.Hex(), revision.ID.Hex())
+		assert.EqualError(mt, err, "revision not found")
+	})
+}
@@ -0,0 +1,196 @@
+package flashcards
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+)
+
+// reviewIntervals são os dias até a próxima revisão conforme o número de
+// acertos seguidos
+var reviewIntervals = []int{1, 3, 7, 14, 30, 90}
+
+// nextReviewState aplica uma resposta ao contador de acertos e calcula a
+// próxima revisão. Um erro volta o card para o início.
+func nextReviewState(reviewCount int, isCorrect bool, now time.Time) (int, time.Time) {
+	if isCorrect {
+		reviewCount++
+	} else {
+		reviewCount = 0
+	}
+
+	intervalIndex := reviewCount
+	if intervalIndex >= len(reviewIntervals) {
+		intervalIndex = len(reviewIntervals) - 1
+	}
+	return reviewCount, now.AddDate(0, 0, reviewIntervals[intervalIndex])
+}
+
+// Fator de facilidade (ease) no estilo do Anki: começa em defaultEase e cai
+// a cada erro até minEase. Cards ainda não revisados não têm o campo.
+const (
+	defaultEase = 2.5
+	minEase     = 1.3
+	easePenalty = 0.2
+)
+
+// nextEase aplica uma resposta ao fator de facilidade
+func nextEase(ease float64, isCorrect bool) float64 {
+	if ease == 0 {
+		ease = defaultEase
+	}
+	if isCorrect {
+		return ease
+	}
+	ease = math.Round((ease-easePenalty)*100) / 100
+	if ease < minEase {
+		ease = minEase
+	}
+	return ease
+}
+
+// leechThreshold é o número de lapsos a partir do qual um card é
+// considerado uma sanguessuga (leech): difícil demais para o formato atual
+const leechThreshold = 8
+
+// isLapse indica se a resposta é um lapso: errar um card que já tinha acertos
+func isLapse(reviewCount int, isCorrect bool) bool {
+	return !isCorrect && reviewCount > 0
+}
+
+func isLeech(card *entities.Flashcard) bool {
+	return card.Lapses >= leechThreshold
+}
+
+// ReviewCard registra a resposta no agendamento de quem estudou. O dono usa
+// os campos do próprio card; membros e leitores de decks públicos têm o
+// próprio agendamento, sem afetar os demais.
+func (s *Service) ReviewCard(userID, cardID string, isCorrect bool) (*entities.Flashcard, error) {
+	return s.reviewCardAt(userID, cardID, isCorrect, time.Now())
+}
+
+// reviewCardAt aplica uma resposta dada em now, usado também para repetir
+// revisões feitas offline. Uma resposta anterior à última revisão registrada
+// retorna ErrReviewSuperseded sem mexer no agendamento.
+func (s *Service) reviewCardAt(userID, cardID string, isCorrect bool, now time.Time) (*entities.Flashcard, error) {
+	ctx := context.Background()
+	card, err := s.repo.GetByID(ctx, cardID)
+	if err != nil {
+		return nil, ErrCardNotFound
+	}
+	deck, err := s.getReadableDeck(userID, card.DeckID)
+	if err != nil {
+		return nil, err
+	}
+
+	if deck.UserID == userID {
+		if card.LastReviewed != nil && card.LastReviewed.After(now) {
+			return card, ErrReviewSuperseded
+		}
+		if err := s.repo.UpdateReview(ctx, cardID, isCorrect, now); err != nil {
+			return nil, err
+		}
+		return s.repo.GetByID(ctx, cardID)
+	}
+
+	schedule, err := s.repo.GetCardSchedule(userID, cardID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedule: %w", err)
+	}
+	if schedule == nil {
+		schedule = &entities.CardSchedule{UserID: userID, CardID: cardID}
+	}
+	if schedule.LastReviewed != nil && schedule.LastReviewed.After(now) {
+		applyCardSchedule(card, schedule)
+		return card, ErrReviewSuperseded
+	}
+
+	if isLapse(schedule.ReviewCount, isCorrect) {
+		schedule.Lapses++
+	}
+	reviewCount, nextReview := nextReviewState(schedule.ReviewCount, isCorrect, now)
+	schedule.Ease = nextEase(schedule.Ease, isCorrect)
+	schedule.DeckID = card.DeckID
+	schedule.ReviewCount = reviewCount
+	schedule.LastReviewed = &now
+	schedule.NextReview = &nextReview
+	if err := s.repo.UpsertCardSchedule(schedule); err != nil {
+		return nil, fmt.Errorf("failed to update schedule: %w", err)
+	}
+
+	applyCardSchedule(card, schedule)
+	return card, nil
+}
+
+// applyCardSchedule troca o estado de revisão do card pelo do membro; sem
+// agendamento o card é novo para ele
+func applyCardSchedule(card *entities.Flashcard, schedule *entities.CardSchedule) {
+	if schedule == nil {
+		card.ReviewCount = 0
+		card.LastReviewed = nil
+		card.NextReview = nil
+		card.Lapses = 0
+		card.Ease = 0
+		return
+	}
+	card.ReviewCount = schedule.ReviewCount
+	card.LastReviewed = schedule.LastReviewed
+	card.NextReview = schedule.NextReview
+	card.Lapses = schedule.Lapses
+	card.Ease = schedule.Ease
+}
+
+// applyMemberSchedules aplica aos cards dos decks informados o agendamento
+// do usuário
+func (s *Service) applyMemberSchedules(userID string, deckIDs []string, cards []entities.Flashcard) error {
+	schedules, err := s.repo.GetCardSchedules(userID, deckIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get schedules: %w", err)
+	}
+	for i := range cards {
+		if schedule, ok := schedules[cards[i].ID.Hex()]; ok {
+			applyCardSchedule(&cards[i], &schedule)
+		} else {
+			applyCardSchedule(&cards[i], nil)
+		}
+	}
+	return nil
+}
+
+// getMemberDueCards faz o mesmo que GetDueFlashcards usando o agendamento do
+// usuário: primeiro os cards novos, depois os mais atrasados
+func (s *Service) getMemberDueCards(userID string, deckIDs []string, now time.Time, limit int64) ([]entities.Flashcard, error) {
+	cards, err := s.repo.FindFlashcards(deckIDs, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cards: %w", err)
+	}
+	if err := s.applyMemberSchedules(userID, deckIDs, cards); err != nil {
+		return nil, err
+	}
+
+	due := []entities.Flashcard{}
+	for _, card := range cards {
+		if card.Suspended {
+			continue
+		}
+		if card.NextReview == nil || !card.NextReview.After(now) {
+			due = append(due, card)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		a, b := due[i].NextReview, due[j].NextReview
+		if a == nil || b == nil {
+			return a == nil && b != nil
+		}
+		return a.Before(*b)
+	})
+
+	if limit > 0 && int64(len(due)) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
@@ -0,0 +1,50 @@
+package flashcards
+
+import (
+	"testing"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextReviewState(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	count, next := nextReviewState(0, true, now)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, now.AddDate(0, 0, 3), next)
+
+	// Depois do último intervalo o espaçamento para de crescer
+	count, next = nextReviewState(10, true, now)
+	assert.Equal(t, 11, count)
+	assert.Equal(t, now.AddDate(0, 0, 90), next)
+
+	count, next = nextReviewState(4, false, now)
+	assert.Equal(t, 0, count)
+	assert.Equal(t, now.AddDate(0, 0, 1), next)
+}
+
+func TestApplyCardSchedule(t *testing.T) {
+	reviewed := time.Now()
+	card := &entities.Flashcard{ReviewCount: 5, LastReviewed: &reviewed, NextReview: &reviewed}
+
+	// Sem agendamento próprio o card é novo para o membro
+	applyCardSchedule(card, nil)
+	assert.Equal(t, 0, card.ReviewCount)
+	assert.Nil(t, card.NextReview)
+
+	next := reviewed.AddDate(0, 0, 7)
+	applyCardSchedule(card, &entities.CardSchedule{ReviewCount: 2, LastReviewed: &reviewed, NextReview: &next})
+	assert.Equal(t, 2, card.ReviewCount)
+	assert.Equal(t, &next, card.NextReview)
+}
+
+func TestNextEase(t *testing.T) {
+	// Sem ease o card parte do padrão
+	assert.Equal(t, defaultEase, nextEase(0, true))
+	assert.Equal(t, 2.3, nextEase(0, false))
+	assert.Equal(t, 2.1, nextEase(2.3, false))
+	assert.Equal(t, minEase, nextEase(1.4, false))
+}
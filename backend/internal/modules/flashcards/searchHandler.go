@@ -0,0 +1,97 @@
+package flashcards
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchCards busca nos cards do usuário. q usa a linguagem de busca de
+// cards (searchQuery.go).
+// GET /api/search?q=...&deck_id=...&tag=...&due=due|new|scheduled&suspended=true|false&leech=true|false
+// &created_after=...&created_before=...&updated_after=...&updated_before=...&page=...&limit=...
+func (h *Handler) SearchCards(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	query := SearchQuery{
+		Text:   c.Query("q"),
+		DeckID: c.Query("deck_id"),
+		Tag:    c.Query("tag"),
+		Due:    c.Query("due"),
+	}
+	query.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	query.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "0"))
+
+	var err error
+	if query.Suspended, err = queryBool(c, "suspended"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if query.Leech, err = queryBool(c, "leech"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	dates := []struct {
+		param    string
+		target   **time.Time
+		endOfDay bool
+	}{
+		{"created_after", &query.CreatedAfter, false},
+		{"created_before", &query.CreatedBefore, true},
+		{"updated_after", &query.UpdatedAfter, false},
+		{"updated_before", &query.UpdatedBefore, true},
+	}
+	for _, date := range dates {
+		if *date.target, err = queryDate(c, date.param, date.endOfDay); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	result, err := h.service.SearchCards(userID, query)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// queryBool lê um parâmetro true/false opcional
+func queryBool(c *gin.Context, param string) (*bool, error) {
+	value := c.Query(param)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: use true or false", param)
+	}
+	return &parsed, nil
+}
+
+// queryDate lê uma data opcional em RFC 3339 ou AAAA-MM-DD. Uma data sem
+// hora usada como limite final vale até o fim do dia.
+func queryDate(c *gin.Context, param string, endOfDay bool) (*time.Time, error) {
+	value := c.Query(param)
+	if value == "" {
+		return nil, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return &parsed, nil
+	}
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: use YYYY-MM-DD or RFC 3339", param)
+	}
+	if endOfDay {
+		parsed = parsed.Add(24*time.Hour - time.Nanosecond)
+	}
+	return &parsed, nil
+}
@@ -0,0 +1,139 @@
+package flashcards
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+const (
+	// snippetLength é o tamanho máximo do trecho, em caracteres
+	snippetLength = 160
+	// snippetContext é quanto texto aparece antes do primeiro termo
+	snippetContext = 40
+)
+
+// accentFolds remove os acentos mais comuns, como o índice textual faz ao
+// comparar palavras
+var accentFolds = map[rune]rune{
+	'á': 'a', 'à': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a',
+	'é': 'e', 'è': 'e', 'ê': 'e', 'ë': 'e',
+	'í': 'i', 'ì': 'i', 'î': 'i', 'ï': 'i',
+	'ó': 'o', 'ò': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o',
+	'ú': 'u', 'ù': 'u', 'û': 'u', 'ü': 'u',
+	'ç': 'c', 'ñ': 'n',
+}
+
+func foldWord(word []rune) string {
+	var b strings.Builder
+	for _, r := range word {
+		r = unicode.ToLower(r)
+		if folded, ok := accentFolds[r]; ok {
+			r = folded
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// wordSpan é a posição de uma palavra no texto, em runas
+type wordSpan struct {
+	start, end int
+}
+
+func splitWords(text []rune) []wordSpan {
+	var words []wordSpan
+	start := -1
+	for i, r := range text {
+		if isWordRune(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			words = append(words, wordSpan{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, wordSpan{start, len(text)})
+	}
+	return words
+}
+
+// searchTerms extrai as palavras da busca no formato do $text: frases entre
+// aspas contam palavra por palavra e termos com "-" são exclusões, que não
+// são destacadas
+func searchTerms(query string) map[string]bool {
+	terms := make(map[string]bool)
+	runes := []rune(query)
+	for _, word := range splitWords(runes) {
+		if word.start > 0 && runes[word.start-1] == '-' && (word.start == 1 || unicode.IsSpace(runes[word.start-2])) {
+			continue
+		}
+		terms[foldWord(runes[word.start:word.end])] = true
+	}
+	return terms
+}
+
+// highlightSnippet marca com <mark> as palavras do texto que estão em terms.
+// Textos longos são cortados em volta da primeira ocorrência. Retorna false
+// se nenhum termo aparece no texto. O restante do texto é escapado para HTML.
+func highlightSnippet(text string, terms map[string]bool) (string, bool) {
+	runes := []rune(text)
+	var matches []wordSpan
+	for _, word := range splitWords(runes) {
+		if terms[foldWord(runes[word.start:word.end])] {
+			matches = append(matches, word)
+		}
+	}
+	if len(matches) == 0 {
+		return "", false
+	}
+
+	start, end := 0, len(runes)
+	if len(runes) > snippetLength {
+		start = matches[0].start - snippetContext
+		if start < 0 {
+			start = 0
+		}
+		end = start + snippetLength
+		if end > len(runes) {
+			end = len(runes)
+			start = end - snippetLength
+		}
+		// Evita cortar palavras nas pontas do trecho
+		for start > 0 && isWordRune(runes[start-1]) && isWordRune(runes[start]) && start < matches[0].start {
+			start++
+		}
+		for end < len(runes) && end > start && isWordRune(runes[end-1]) && isWordRune(runes[end]) {
+			end--
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	pos := start
+	for _, match := range matches {
+		if match.start < start || match.end > end {
+			continue
+		}
+		b.WriteString(html.EscapeString(string(runes[pos:match.start])))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(string(runes[match.start:match.end])))
+		b.WriteString("</mark>")
+		pos = match.end
+	}
+	b.WriteString(html.EscapeString(string(runes[pos:end])))
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return strings.TrimSpace(b.String()), true
+}
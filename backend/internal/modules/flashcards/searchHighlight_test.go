@@ -0,0 +1,55 @@
+package flashcards
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchTerms(t *testing.T) {
+	terms := searchTerms(`Coração "válvula mitral" -aorta`)
+	assert.True(t, terms["coracao"])
+	assert.True(t, terms["valvula"])
+	assert.True(t, terms["mitral"])
+	assert.False(t, terms["aorta"])
+}
+
+func TestHighlightSnippet(t *testing.T) {
+	terms := searchTerms("coracao")
+
+	snippet, ok := highlightSnippet("O <b>Coração</b> bombeia sangue", terms)
+	assert.True(t, ok)
+	assert.Equal(t, "O &lt;b&gt;<mark>Coração</mark>&lt;/b&gt; bombeia sangue", snippet)
+
+	_, ok = highlightSnippet("Pulmões", terms)
+	assert.False(t, ok)
+
+	// Textos longos são cortados em volta da primeira ocorrência
+	long := strings.Repeat("palavra ", 40) + "coração " + strings.Repeat("fim ", 40)
+	snippet, ok = highlightSnippet(long, terms)
+	assert.True(t, ok)
+	assert.True(t, strings.HasPrefix(snippet, "…"))
+	assert.True(t, strings.HasSuffix(snippet, "…"))
+	assert.Contains(t, snippet, "<mark>coração</mark>")
+	assert.LessOrEqual(t, len([]rune(snippet)), snippetLength+len("<mark></mark>")+2)
+}
+
+func TestBuildSearchFilter(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	yes := true
+
+	filter, err := buildSearchFilter([]string{"d1"}, SearchQuery{Text: "ecg", Due: DueStateScheduled, Leech: &yes}, now)
+	assert.NoError(t, err)
+	assert.Contains(t, filter, "$text")
+	assert.Contains(t, filter, "nextReview")
+	assert.Contains(t, filter, "lapses")
+
+	_, err = buildSearchFilter([]string{"d1"}, SearchQuery{Due: "late"}, now)
+	assert.Error(t, err)
+
+	before := now.Add(-time.Hour)
+	_, err = buildSearchFilter([]string{"d1"}, SearchQuery{CreatedAfter: &now, CreatedBefore: &before}, now)
+	assert.Error(t, err)
+}
@@ -0,0 +1,521 @@
+package flashcards
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Linguagem de busca de cards, no estilo do Anki. Os termos são separados
+// por espaços e todos precisam casar; "-" na frente nega o termo.
+//
+//	palavra "frase exata"   busca textual (índice de texto)
+//	deck:"Biologia::Células" deck pelo caminho, com subdecks; aceita *
+//	tag:prova               tag sem diferenciar maiúsculas; aceita *
+//	is:due|new|scheduled|suspended|leech
+//	prop:ease<2.0           ease, lapses, reviews, difficulty ou due (em dias)
+//	rated:7[:1]             revisados nos últimos N dias (resposta 1-4)
+//	added:30, edited:30     criados ou editados nos últimos N dias
+//
+// is: (menos suspended) e prop: (menos difficulty) usam o agendamento
+// gravado no card, que é o do dono. Nos decks compartilhados cada membro tem
+// o próprio agendamento, então esses termos só casam com cards dos decks do
+// usuário.
+
+// Estados aceitos em is:
+const (
+	QueryStateDue       = "due"
+	QueryStateNew       = "new"
+	QueryStateScheduled = "scheduled"
+	QueryStateSuspended = "suspended"
+	QueryStateLeech     = "leech"
+)
+
+// maxQueryDays limita o período de rated:, added: e edited:
+const maxQueryDays = 3650
+
+// ratedAnswers são as respostas de rated:N:A, na ordem dos botões de estudo
+var ratedAnswers = []string{"again", "hard", "good", "easy"}
+
+// queryProps são os campos aceitos em prop:, com o nome no documento do card
+var queryProps = map[string]string{
+	"ease":       "ease",
+	"lapses":     "lapses",
+	"reviews":    "reviewCount",
+	"difficulty": "difficulty",
+	"due":        "nextReview",
+}
+
+// queryOperators em ordem de tamanho, para "<=" vir antes de "<"
+var queryOperators = []string{"<=", ">=", "!=", "<", ">", "="}
+
+// QueryError é um erro de sintaxe na busca, com a posição (em caracteres,
+// a partir de 1) do termo inválido
+type QueryError struct {
+	Pos     int
+	Message string
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("invalid query at position %d: %s", e.Pos, e.Message)
+}
+
+// queryTerm é um termo já validado da busca
+type queryTerm struct {
+	pos     int
+	negated bool
+	field   string // vazio para texto livre
+	value   string
+	quoted  bool
+	op      string  // prop:
+	number  float64 // prop:, rated:, added:, edited:
+	answer  int     // rated:N:A
+}
+
+// CardQuery é uma busca já interpretada, pronta para virar filtro
+type CardQuery struct {
+	terms []queryTerm
+}
+
+// Empty indica se a busca não tem nenhum termo
+func (q *CardQuery) Empty() bool {
+	return q == nil || len(q.terms) == 0
+}
+
+// ParseCardQuery interpreta o texto da busca. Erros de sintaxe voltam como
+// *QueryError.
+func ParseCardQuery(input string) (*CardQuery, error) {
+	tokens, err := tokenizeQuery(input)
+	if err != nil {
+		return nil, err
+	}
+	query := &CardQuery{}
+	hasText, hasPositiveText := false, false
+	for _, token := range tokens {
+		term, err := parseQueryTerm(token)
+		if err != nil {
+			return nil, err
+		}
+		if term.field == "" {
+			hasText = true
+			hasPositiveText = hasPositiveText || !term.negated
+		}
+		query.terms = append(query.terms, term)
+	}
+	if hasText && !hasPositiveText {
+		return nil, &QueryError{Pos: 1, Message: "text search needs at least one word that is not negated"}
+	}
+	return query, nil
+}
+
+// queryToken é um pedaço da busca antes de interpretar o campo
+type queryToken struct {
+	pos     int
+	negated bool
+	field   string
+	value   string
+	quoted  bool
+}
+
+func tokenizeQuery(input string) ([]queryToken, error) {
+	runes := []rune(input)
+	var tokens []queryToken
+	i := 0
+	for i < len(runes) {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+		token := queryToken{pos: i + 1}
+		if runes[i] == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+			token.negated = true
+			i++
+		}
+
+		var b strings.Builder
+		for i < len(runes) && !unicode.IsSpace(runes[i]) {
+			switch r := runes[i]; {
+			case r == '"':
+				start := i
+				i++
+				closed := false
+				for i < len(runes) {
+					if runes[i] == '\\' && i+1 < len(runes) {
+						b.WriteRune(runes[i+1])
+						i += 2
+						continue
+					}
+					if runes[i] == '"' {
+						closed = true
+						i++
+						break
+					}
+					b.WriteRune(runes[i])
+					i++
+				}
+				if !closed {
+					return nil, &QueryError{Pos: start + 1, Message: "unterminated quote"}
+				}
+				token.quoted = true
+			case r == ':' && token.field == "" && !token.quoted && isQueryFieldName(b.String()):
+				token.field = strings.ToLower(b.String())
+				b.Reset()
+				i++
+			default:
+				b.WriteRune(r)
+				i++
+			}
+		}
+		token.value = b.String()
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
+
+func isQueryFieldName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func parseQueryTerm(token queryToken) (queryTerm, error) {
+	term := queryTerm{pos: token.pos, negated: token.negated, field: token.field, value: token.value, quoted: token.quoted}
+	fail := func(format string, args ...interface{}) (queryTerm, error) {
+		return queryTerm{}, &QueryError{Pos: token.pos, Message: fmt.Sprintf(format, args...)}
+	}
+
+	if term.field != "" && strings.TrimSpace(term.value) == "" {
+		return fail("%s: needs a value", term.field)
+	}
+
+	switch term.field {
+	case "":
+		if strings.TrimSpace(term.value) == "" {
+			return fail("empty search term")
+		}
+	case "deck", "tag":
+	case "is":
+		term.value = strings.ToLower(term.value)
+		switch term.value {
+		case QueryStateDue, QueryStateNew, QueryStateScheduled, QueryStateSuspended, QueryStateLeech:
+		default:
+			return fail("unknown state is:%s (use due, new, scheduled, suspended or leech)", term.value)
+		}
+	case "prop":
+		name, op, value := splitQueryOperator(strings.ToLower(term.value))
+		if _, ok := queryProps[name]; !ok {
+			return fail("unknown property %q (use ease, lapses, reviews, difficulty or due)", name)
+		}
+		if op == "" {
+			return fail("prop:%s needs an operator such as <, <=, >, >=, = or !=", name)
+		}
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fail("prop:%s needs a number, got %q", name, value)
+		}
+		if name == "due" && number != float64(int(number)) {
+			return fail("prop:due needs a whole number of days")
+		}
+		term.value, term.op, term.number = name, op, number
+	case "rated":
+		days, answer, hasAnswer := strings.Cut(term.value, ":")
+		n, err := parseQueryDays(days)
+		if err != nil {
+			return fail("rated: %v", err)
+		}
+		term.number = float64(n)
+		if hasAnswer {
+			a, err := strconv.Atoi(answer)
+			if err != nil || a < 1 || a > len(ratedAnswers) {
+				return fail("rated: answer must be 1 (again), 2 (hard), 3 (good) or 4 (easy)")
+			}
+			term.answer = a
+		}
+	case "added", "edited":
+		n, err := parseQueryDays(term.value)
+		if err != nil {
+			return fail("%s: %v", term.field, err)
+		}
+		term.number = float64(n)
+	default:
+		return fail("unknown field %q (use deck, tag, is, prop, rated, added or edited)", term.field)
+	}
+	return term, nil
+}
+
+func splitQueryOperator(value string) (name, op, rest string) {
+	for i := range value {
+		for _, candidate := range queryOperators {
+			if strings.HasPrefix(value[i:], candidate) {
+				return value[:i], candidate, value[i+len(candidate):]
+			}
+		}
+	}
+	return value, "", ""
+}
+
+func parseQueryDays(value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("days must be a positive whole number")
+	}
+	if n > maxQueryDays {
+		return 0, fmt.Errorf("days must be at most %d", maxQueryDays)
+	}
+	return n, nil
+}
+
+// queryEnv dá ao compilador o que depende do usuário: os decks que podem
+// ser citados em deck:, as revisões usadas por rated: e os decks em que o
+// agendamento do card é o do usuário (nil quando são todos)
+type queryEnv struct {
+	now             time.Time
+	decks           []entities.Deck
+	ratedCards      func(since time.Time, difficulty string) ([]primitive.ObjectID, error)
+	scheduleDeckIDs []string
+}
+
+// compiledQuery é a busca como filtro do MongoDB: o texto vai para o $text,
+// que só pode aparecer uma vez no nível de cima, e as demais condições
+// entram num $and
+type compiledQuery struct {
+	Text       string
+	Conditions bson.A
+}
+
+// apply acrescenta a busca ao filtro
+func (q *compiledQuery) apply(filter bson.M) {
+	if q.Text != "" {
+		filter["$text"] = bson.M{"$search": q.Text}
+	}
+	if len(q.Conditions) > 0 {
+		and, _ := filter["$and"].(bson.A)
+		filter["$and"] = append(and, q.Conditions...)
+	}
+}
+
+func (q *CardQuery) compile(env queryEnv) (*compiledQuery, error) {
+	compiled := &compiledQuery{Conditions: bson.A{}}
+	var text []string
+	restricted := false
+	for _, term := range q.terms {
+		if term.field == "" {
+			text = append(text, textSearchTerm(term))
+			continue
+		}
+		condition, err := compileQueryTerm(term, env)
+		if err != nil {
+			return nil, err
+		}
+		if term.negated {
+			condition = bson.M{"$nor": bson.A{condition}}
+		}
+		compiled.Conditions = append(compiled.Conditions, condition)
+		// A restrição fica fora do $nor para -is:due também não casar com
+		// os cards dos decks compartilhados
+		if term.usesSchedule() && env.scheduleDeckIDs != nil && !restricted {
+			compiled.Conditions = append(compiled.Conditions, bson.M{"deckId": bson.M{"$in": env.scheduleDeckIDs}})
+			restricted = true
+		}
+	}
+	compiled.Text = strings.Join(text, " ")
+	return compiled, nil
+}
+
+// usesSchedule indica se o termo depende do agendamento de revisão gravado
+// no card
+func (t queryTerm) usesSchedule() bool {
+	switch t.field {
+	case "is":
+		return t.value != QueryStateSuspended
+	case "prop":
+		return t.value != "difficulty"
+	}
+	return false
+}
+
+// textSearchTerm escreve o termo na sintaxe do $search
+func textSearchTerm(term queryTerm) string {
+	value := term.value
+	if term.quoted {
+		value = `"` + strings.ReplaceAll(value, `"`, "") + `"`
+	}
+	if term.negated {
+		value = "-" + value
+	}
+	return value
+}
+
+func compileQueryTerm(term queryTerm, env queryEnv) (bson.M, error) {
+	today := startOfDay(env.now)
+	switch term.field {
+	case "deck":
+		return bson.M{"deckId": bson.M{"$in": matchQueryDecks(env.decks, term.value)}}, nil
+	case "tag":
+		return bson.M{"tags": bson.M{"$regex": wildcardPattern(term.value), "$options": "i"}}, nil
+	case "is":
+		return queryStateCondition(term.value, env.now), nil
+	case "prop":
+		return queryPropCondition(term.value, term.op, term.number, today), nil
+	case "rated":
+		difficulty := ""
+		if term.answer > 0 {
+			difficulty = ratedAnswers[term.answer-1]
+		}
+		ids, err := env.ratedCards(queryDaysSince(today, term.number), difficulty)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get reviews: %w", err)
+		}
+		return bson.M{"_id": bson.M{"$in": ids}}, nil
+	case "added":
+		return bson.M{"createdAt": bson.M{"$gte": queryDaysSince(today, term.number)}}, nil
+	case "edited":
+		return bson.M{"updatedAt": bson.M{"$gte": queryDaysSince(today, term.number)}}, nil
+	}
+	return nil, &QueryError{Pos: term.pos, Message: fmt.Sprintf("unknown field %q", term.field)}
+}
+
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// queryDaysSince é o início do período de N dias que termina hoje: 1 é só
+// hoje, 7 é hoje e os seis dias anteriores
+func queryDaysSince(today time.Time, days float64) time.Time {
+	return today.AddDate(0, 0, 1-int(days))
+}
+
+// wildcardPattern converte um valor com * numa regex ancorada
+func wildcardPattern(value string) string {
+	parts := strings.Split(value, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return "^" + strings.Join(parts, ".*") + "$"
+}
+
+// matchQueryDecks retorna os decks cujo caminho casa com o padrão, mais os
+// subdecks deles
+func matchQueryDecks(decks []entities.Deck, pattern string) []string {
+	matcher := regexp.MustCompile("(?i)" + wildcardPattern(strings.TrimSpace(pattern)))
+	var roots []string
+	for _, deck := range decks {
+		if matcher.MatchString(deck.Name) {
+			roots = append(roots, deck.Name)
+		}
+	}
+	ids := []string{}
+	for _, deck := range decks {
+		for _, root := range roots {
+			if deck.Name == root || isDescendantPath(deck.Name, root) {
+				ids = append(ids, deck.ID.Hex())
+				break
+			}
+		}
+	}
+	return ids
+}
+
+func queryStateCondition(state string, now time.Time) bson.M {
+	switch state {
+	case QueryStateDue:
+		return bson.M{"$or": bson.A{
+			bson.M{"nextReview": bson.M{"$lte": now}},
+			bson.M{"nextReview": nil},
+		}}
+	case QueryStateNew:
+		return bson.M{"nextReview": nil}
+	case QueryStateScheduled:
+		return bson.M{"nextReview": bson.M{"$gt": now}}
+	case QueryStateSuspended:
+		return bson.M{"suspended": true}
+	default: // QueryStateLeech
+		return bson.M{"lapses": bson.M{"$gte": leechThreshold}}
+	}
+}
+
+// queryPropCondition compara um campo numérico. Campos que podem faltar no
+// documento (ease e lapses) valem o padrão, então o card sem o campo entra
+// quando o padrão satisfaz a comparação.
+func queryPropCondition(name, op string, number float64, today time.Time) bson.M {
+	field := queryProps[name]
+	if name == "due" {
+		return queryDueCondition(op, int(number), today)
+	}
+
+	operators := map[string]string{"<": "$lt", "<=": "$lte", ">": "$gt", ">=": "$gte", "=": "$eq", "!=": "$ne"}
+	condition := bson.M{field: bson.M{operators[op]: number}}
+
+	var missing float64
+	switch name {
+	case "ease":
+		missing = defaultEase
+	case "lapses":
+	default:
+		return condition
+	}
+	if compareQueryNumber(missing, op, number) {
+		return bson.M{"$or": bson.A{condition, bson.M{field: nil}}}
+	}
+	if op == "!=" {
+		// $ne sozinho também casaria com o campo ausente
+		return bson.M{field: bson.M{"$ne": number, "$exists": true}}
+	}
+	return condition
+}
+
+func compareQueryNumber(value float64, op string, number float64) bool {
+	switch op {
+	case "<":
+		return value < number
+	case "<=":
+		return value <= number
+	case ">":
+		return value > number
+	case ">=":
+		return value >= number
+	case "=":
+		return value == number
+	default:
+		return value != number
+	}
+}
+
+// queryDueCondition compara a próxima revisão em dias a partir de hoje:
+// prop:due=0 vence hoje, prop:due<0 está atrasado. Cards novos não entram.
+func queryDueCondition(op string, days int, today time.Time) bson.M {
+	start := today.AddDate(0, 0, days)
+	end := start.AddDate(0, 0, 1)
+	switch op {
+	case "<":
+		return bson.M{"nextReview": bson.M{"$lt": start}}
+	case "<=":
+		return bson.M{"nextReview": bson.M{"$lt": end}}
+	case ">":
+		return bson.M{"nextReview": bson.M{"$gte": end}}
+	case ">=":
+		return bson.M{"nextReview": bson.M{"$gte": start}}
+	case "=":
+		return bson.M{"nextReview": bson.M{"$gte": start, "$lt": end}}
+	default:
+		return bson.M{"$or": bson.A{
+			bson.M{"nextReview": bson.M{"$lt": start}},
+			bson.M{"nextReview": bson.M{"$gte": end}},
+		}}
+	}
+}
@@ -0,0 +1,133 @@
+package flashcards
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestParseCardQuery(t *testing.T) {
+	query, err := ParseCardQuery(`deck:"Biologia::Células" tag:prova -tag:facil coração "válvula mitral" -aorta`)
+	assert.NoError(t, err)
+	assert.Len(t, query.terms, 6)
+	assert.Equal(t, "deck", query.terms[0].field)
+	assert.Equal(t, "Biologia::Células", query.terms[0].value)
+	assert.True(t, query.terms[2].negated)
+	assert.True(t, query.terms[4].quoted)
+
+	query, err = ParseCardQuery("prop:ease<=2.0 rated:7:1 added:30")
+	assert.NoError(t, err)
+	assert.Equal(t, "<=", query.terms[0].op)
+	assert.Equal(t, 2.0, query.terms[0].number)
+	assert.Equal(t, 1, query.terms[1].answer)
+
+	query, err = ParseCardQuery("   ")
+	assert.NoError(t, err)
+	assert.True(t, query.Empty())
+}
+
+func TestParseCardQueryErrors(t *testing.T) {
+	cases := map[string]int{
+		`deck:"Biologia`:    6,
+		"is:late":           1,
+		"tag:ok prop:ivl>3": 8,
+		"prop:ease":         1,
+		"prop:ease<abc":     1,
+		"rated:0":           1,
+		"rated:7:5":         1,
+		"color:red":         1,
+		"-aorta":            1,
+		"tag:":              1,
+	}
+	for input, pos := range cases {
+		_, err := ParseCardQuery(input)
+		var queryErr *QueryError
+		if assert.True(t, errors.As(err, &queryErr), input) {
+			assert.Equal(t, pos, queryErr.Pos, input)
+		}
+	}
+}
+
+func TestCompileCardQuery(t *testing.T) {
+	now := time.Date(2024, 3, 10, 15, 0, 0, 0, time.UTC)
+	biology, cells, other := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	reviewed := primitive.NewObjectID()
+	var ratedSince time.Time
+	var ratedAnswer string
+	env := queryEnv{
+		now: now,
+		decks: []entities.Deck{
+			{ID: biology, Name: "Biologia"},
+			{ID: cells, Name: "Biologia::Células"},
+			{ID: other, Name: "História"},
+		},
+		ratedCards: func(since time.Time, difficulty string) ([]primitive.ObjectID, error) {
+			ratedSince, ratedAnswer = since, difficulty
+			return []primitive.ObjectID{reviewed}, nil
+		},
+	}
+
+	query, err := ParseCardQuery(`deck:biologia coração -"válvula mitral" -is:suspended rated:7:1`)
+	assert.NoError(t, err)
+	compiled, err := query.compile(env)
+	assert.NoError(t, err)
+	assert.Equal(t, `coração -"válvula mitral"`, compiled.Text)
+	assert.Equal(t, bson.M{"deckId": bson.M{"$in": []string{biology.Hex(), cells.Hex()}}}, compiled.Conditions[0])
+	assert.Equal(t, bson.M{"$nor": bson.A{bson.M{"suspended": true}}}, compiled.Conditions[1])
+	assert.Equal(t, bson.M{"_id": bson.M{"$in": []primitive.ObjectID{reviewed}}}, compiled.Conditions[2])
+	assert.Equal(t, time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), ratedSince)
+	assert.Equal(t, "again", ratedAnswer)
+
+	filter := bson.M{"deckId": "d1"}
+	compiled.apply(filter)
+	assert.Equal(t, bson.M{"$search": compiled.Text}, filter["$text"])
+	assert.Len(t, filter["$and"], 3)
+
+	query, _ = ParseCardQuery("tag:cardio* added:1")
+	compiled, err = query.compile(env)
+	assert.NoError(t, err)
+	assert.Empty(t, compiled.Text)
+	assert.Equal(t, bson.M{"tags": bson.M{"$regex": "^cardio.*$", "$options": "i"}}, compiled.Conditions[0])
+	assert.Equal(t, bson.M{"createdAt": bson.M{"$gte": time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)}}, compiled.Conditions[1])
+}
+
+func TestCompileCardQueryScheduleTermsSkipSharedDecks(t *testing.T) {
+	own, shared := primitive.NewObjectID(), primitive.NewObjectID()
+	decks := []entities.Deck{{ID: own, UserID: "user"}, {ID: shared, UserID: "owner"}}
+	env := queryEnv{now: time.Now(), decks: decks, scheduleDeckIDs: ownScheduleDeckIDs("user", decks)}
+	onlyOwn := bson.M{"deckId": bson.M{"$in": []string{own.Hex()}}}
+
+	// A restrição entra uma vez e fora da negação
+	query, _ := ParseCardQuery("-is:due prop:ease<2")
+	compiled, err := query.compile(env)
+	assert.NoError(t, err)
+	assert.Len(t, compiled.Conditions, 3)
+	assert.Equal(t, onlyOwn, compiled.Conditions[1])
+
+	// Suspensão e dificuldade ficam no card e valem para todos
+	query, _ = ParseCardQuery("is:suspended prop:difficulty>2")
+	compiled, err = query.compile(env)
+	assert.NoError(t, err)
+	assert.NotContains(t, compiled.Conditions, onlyOwn)
+
+	assert.Nil(t, ownScheduleDeckIDs("user", decks[:1]))
+}
+
+func TestQueryPropCondition(t *testing.T) {
+	today := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	// Cards sem ease valem o padrão (2.5)
+	assert.Equal(t, bson.M{"ease": bson.M{"$lt": 2.0}}, queryPropCondition("ease", "<", 2.0, today))
+	assert.Equal(t, bson.M{"$or": bson.A{bson.M{"ease": bson.M{"$gt": 2.0}}, bson.M{"ease": nil}}}, queryPropCondition("ease", ">", 2.0, today))
+	assert.Equal(t, bson.M{"lapses": bson.M{"$ne": 0.0, "$exists": true}}, queryPropCondition("lapses", "!=", 0, today))
+	assert.Equal(t, bson.M{"reviewCount": bson.M{"$gte": 3.0}}, queryPropCondition("reviews", ">=", 3, today))
+
+	assert.Equal(t, bson.M{"nextReview": bson.M{"$gte": today.AddDate(0, 0, 1), "$lt": today.AddDate(0, 0, 2)}}, queryPropCondition("due", "=", 1, today))
+	assert.Equal(t, bson.M{"nextReview": bson.M{"$lt": today}}, queryPropCondition("due", "<", 0, today))
+}
@@ -0,0 +1,79 @@
+package flashcards
+
+import (
+	"context"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// scoredCard é um card com a relevância calculada pelo índice textual
+type scoredCard struct {
+	entities.Flashcard `bson:",inline"`
+	Score              float64 `bson:"score,omitempty"`
+}
+
+// SearchFlashcards busca cards com o filtro informado. Com busca textual o
+// resultado vem por relevância; sem ela, dos mais recentes para os mais
+// antigos. Retorna também o total de cards que casam com o filtro.
+func (r *MongoRepository) SearchFlashcards(filter bson.M, textSearch bool, skip, limit int64) ([]scoredCard, int64, error) {
+	ctx := context.Background()
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().SetSkip(skip).SetLimit(limit)
+	if textSearch {
+		score := bson.M{"$meta": "textScore"}
+		opts.SetProjection(bson.M{"score": score})
+		opts.SetSort(bson.D{{Key: "score", Value: score}, {Key: "_id", Value: 1}})
+	} else {
+		opts.SetSort(bson.D{{Key: "updatedAt", Value: -1}, {Key: "_id", Value: 1}})
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	cards := []scoredCard{}
+	if err := cursor.All(ctx, &cards); err != nil {
+		return nil, 0, err
+	}
+	return cards, total, nil
+}
+
+// GetRatedCardIDs retorna os cards que o usuário revisou desde since. Com
+// difficulty, só as revisões com essa resposta (again, hard, good ou easy).
+func (r *MongoRepository) GetRatedCardIDs(userID string, since time.Time, difficulty string) ([]primitive.ObjectID, error) {
+	filter := bson.M{
+		"user_id":     userID,
+		"action_type": "card_review",
+		"created_at":  bson.M{"$gte": since},
+	}
+	if difficulty != "" {
+		filter["difficulty"] = difficulty
+	}
+	values, err := r.db.GetCollection("study_stats").Distinct(context.Background(), "card_id", filter)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []primitive.ObjectID{}
+	for _, value := range values {
+		hex, ok := value.(string)
+		if !ok {
+			continue
+		}
+		if id, err := primitive.ObjectIDFromHex(hex); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
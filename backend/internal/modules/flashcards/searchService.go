@@ -0,0 +1,287 @@
+package flashcards
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Estados de revisão aceitos no filtro due
+const (
+	DueStateDue       = "due"       // entraria na revisão agora, inclusive os novos
+	DueStateNew       = "new"       // nunca revisados
+	DueStateScheduled = "scheduled" // com revisão marcada para o futuro
+)
+
+const (
+	defaultSearchPageSize = 20
+	maxSearchPageSize     = 100
+)
+
+// SearchQuery descreve uma busca nos cards do próprio usuário
+type SearchQuery struct {
+	Text          string // linguagem de busca de cards
+	DeckID        string // inclui os subdecks
+	Tag           string
+	Due           string
+	Suspended     *bool
+	Leech         *bool
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	UpdatedBefore *time.Time
+	Page          int
+	Limit         int
+}
+
+// SearchHighlight é um trecho de um campo do card com os termos marcados
+type SearchHighlight struct {
+	Field   string `json:"field"`
+	Snippet string `json:"snippet"`
+}
+
+// SearchHit é um card encontrado com o deck e os trechos destacados
+type SearchHit struct {
+	Card       entities.Flashcard `json:"card"`
+	DeckName   string             `json:"deck_name"`
+	Score      float64            `json:"score,omitempty"`
+	Leech      bool               `json:"leech,omitempty"`
+	Highlights []SearchHighlight  `json:"highlights,omitempty"`
+}
+
+// SearchResult é uma página da busca
+type SearchResult struct {
+	Hits    []SearchHit `json:"hits"`
+	Total   int64       `json:"total"`
+	Page    int         `json:"page"`
+	Limit   int         `json:"limit"`
+	HasMore bool        `json:"has_more"`
+}
+
+// SearchCards busca nos cards dos decks do usuário
+func (s *Service) SearchCards(userID string, query SearchQuery) (*SearchResult, error) {
+	decks, err := s.repo.GetDecksByUserEmail(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get decks: %w", err)
+	}
+	deckNames := make(map[string]string, len(decks))
+	for _, deck := range decks {
+		deckNames[deck.ID.Hex()] = deck.Name
+	}
+
+	deckIDs, err := searchDeckIDs(decks, query.DeckID)
+	if err != nil {
+		return nil, err
+	}
+	compiled, err := s.compileCardQuery(userID, decks, query.Text)
+	if err != nil {
+		return nil, err
+	}
+	query.Text = compiled.Text
+	filter, err := buildSearchFilter(deckIDs, query, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	compiled.apply(filter)
+
+	if query.Limit <= 0 {
+		query.Limit = defaultSearchPageSize
+	}
+	if query.Limit > maxSearchPageSize {
+		query.Limit = maxSearchPageSize
+	}
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+
+	text := strings.TrimSpace(query.Text)
+	skip := int64(query.Page-1) * int64(query.Limit)
+	cards, total, err := s.repo.SearchFlashcards(filter, text != "", skip, int64(query.Limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search cards: %w", err)
+	}
+
+	terms := searchTerms(text)
+	result := &SearchResult{
+		Hits:    make([]SearchHit, 0, len(cards)),
+		Total:   total,
+		Page:    query.Page,
+		Limit:   query.Limit,
+		HasMore: skip+int64(len(cards)) < total,
+	}
+	for _, card := range cards {
+		result.Hits = append(result.Hits, SearchHit{
+			Card:       card.Flashcard,
+			DeckName:   deckNames[card.DeckID],
+			Score:      card.Score,
+			Leech:      isLeech(&card.Flashcard),
+			Highlights: cardHighlights(&card.Flashcard, terms),
+		})
+	}
+	return result, nil
+}
+
+// compileCardQuery interpreta a busca na linguagem de cards. deck: só
+// enxerga os decks informados, rated: usa as revisões do próprio usuário e
+// os termos de agendamento ficam nos decks dele.
+func (s *Service) compileCardQuery(userID string, decks []entities.Deck, input string) (*compiledQuery, error) {
+	query, err := ParseCardQuery(input)
+	if err != nil {
+		return nil, err
+	}
+	// Os dias de rated:, added: e edited: seguem o fuso do usuário
+	return query.compile(queryEnv{
+		now:   time.Now().In(s.userLocation(userID)),
+		decks: decks,
+		ratedCards: func(since time.Time, difficulty string) ([]primitive.ObjectID, error) {
+			return s.repo.GetRatedCardIDs(userID, since, difficulty)
+		},
+		scheduleDeckIDs: ownScheduleDeckIDs(userID, decks),
+	})
+}
+
+// ownScheduleDeckIDs retorna os decks do próprio usuário, em que o
+// agendamento gravado no card é o dele, ou nil se todos os decks são dele
+func ownScheduleDeckIDs(userID string, decks []entities.Deck) []string {
+	ids := []string{}
+	for _, deck := range decks {
+		if deck.UserID == userID {
+			ids = append(ids, deck.ID.Hex())
+		}
+	}
+	if len(ids) == len(decks) {
+		return nil
+	}
+	return ids
+}
+
+// searchDeckIDs restringe a busca ao deck informado e seus subdecks
+func searchDeckIDs(decks []entities.Deck, deckID string) ([]string, error) {
+	ids := make([]string, 0, len(decks))
+	if deckID == "" {
+		for _, deck := range decks {
+			ids = append(ids, deck.ID.Hex())
+		}
+		return ids, nil
+	}
+
+	var root *entities.Deck
+	for i := range decks {
+		if decks[i].ID.Hex() == deckID {
+			root = &decks[i]
+			break
+		}
+	}
+	if root == nil {
+		return nil, ErrDeckNotFound
+	}
+	for _, deck := range decks {
+		if deck.ID == root.ID || isDescendantPath(deck.Name, root.Name) {
+			ids = append(ids, deck.ID.Hex())
+		}
+	}
+	return ids, nil
+}
+
+// buildSearchFilter monta o filtro do MongoDB para a busca
+func buildSearchFilter(deckIDs []string, query SearchQuery, now time.Time) (bson.M, error) {
+	filter := bson.M{"deckId": bson.M{"$in": deckIDs}}
+	var and bson.A
+
+	if text := strings.TrimSpace(query.Text); text != "" {
+		filter["$text"] = bson.M{"$search": text}
+	}
+	if tag := strings.TrimSpace(query.Tag); tag != "" {
+		filter["tags"] = tag
+	}
+
+	switch query.Due {
+	case "":
+	case DueStateDue:
+		and = append(and, bson.M{"$or": bson.A{
+			bson.M{"nextReview": bson.M{"$lte": now}},
+			bson.M{"nextReview": nil},
+		}})
+	case DueStateNew:
+		filter["nextReview"] = nil
+	case DueStateScheduled:
+		filter["nextReview"] = bson.M{"$gt": now}
+	default:
+		return nil, fmt.Errorf("invalid due state: %s (use due, new or scheduled)", query.Due)
+	}
+
+	if query.Suspended != nil {
+		if *query.Suspended {
+			filter["suspended"] = true
+		} else {
+			filter["suspended"] = bson.M{"$ne": true}
+		}
+	}
+	if query.Leech != nil {
+		if *query.Leech {
+			filter["lapses"] = bson.M{"$gte": leechThreshold}
+		} else {
+			and = append(and, bson.M{"$or": bson.A{
+				bson.M{"lapses": bson.M{"$lt": leechThreshold}},
+				bson.M{"lapses": nil},
+			}})
+		}
+	}
+
+	if err := addDateRange(filter, "createdAt", query.CreatedAfter, query.CreatedBefore); err != nil {
+		return nil, err
+	}
+	if err := addDateRange(filter, "updatedAt", query.UpdatedAfter, query.UpdatedBefore); err != nil {
+		return nil, err
+	}
+
+	if len(and) > 0 {
+		filter["$and"] = and
+	}
+	return filter, nil
+}
+
+func addDateRange(filter bson.M, field string, after, before *time.Time) error {
+	if after == nil && before == nil {
+		return nil
+	}
+	if after != nil && before != nil && after.After(*before) {
+		return fmt.Errorf("invalid %s range: start is after end", field)
+	}
+	dateRange := bson.M{}
+	if after != nil {
+		dateRange["$gte"] = *after
+	}
+	if before != nil {
+		dateRange["$lte"] = *before
+	}
+	filter[field] = dateRange
+	return nil
+}
+
+// cardHighlights destaca os termos da busca em cada campo do card
+func cardHighlights(card *entities.Flashcard, terms map[string]bool) []SearchHighlight {
+	if len(terms) == 0 {
+		return nil
+	}
+	var highlights []SearchHighlight
+	add := func(field, text string) {
+		if snippet, ok := highlightSnippet(text, terms); ok {
+			highlights = append(highlights, SearchHighlight{Field: field, Snippet: snippet})
+		}
+	}
+	add("question", card.Question)
+	add("answer", card.Answer)
+	for _, alternative := range card.Alternatives {
+		add("alternatives", alternative)
+	}
+	for _, tag := range card.Tags {
+		add("tags", tag)
+	}
+	return highlights
+}
@@ -3,6 +3,9 @@ package flashcards
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
 	"flashcard-backend/internal/config"
 	"flashcard-backend/internal/domain/entities"
@@ -22,6 +25,12 @@ type Service struct {
 	}
 	authService interface {
 		GetUserByID(userID string) (*entities.User, error)
+		SendDeckInvitation(to, inviterName, deckName string) error
+	}
+	media interface {
+		IsConfigured() bool
+		DeleteFile(url string) error
+		MediaHosts() []string
 	}
 }
 
@@ -32,17 +41,23 @@ func NewService(repo *MongoRepository, cfg *config.Config, adminService interfac
 	ValidatePublicCardLimit(ctx context.Context, userPlan string, currentPublicCardCount int) error
 }, authService interface {
 	GetUserByID(userID string) (*entities.User, error)
+	SendDeckInvitation(to, inviterName, deckName string) error
+}, media interface {
+	IsConfigured() bool
+	DeleteFile(url string) error
+	MediaHosts() []string
 }) *Service {
 	return &Service{
 		repo:         repo,
 		cfg:          cfg,
 		adminService: adminService,
 		authService:  authService,
+		media:        media,
 	}
 }
 
 // Deck operations
-func (s *Service) CreateDeck(userID string, name, description string, tags []string, isPublic bool) (*entities.Deck, error) {
+func (s *Service) CreateDeck(userID string, name, description string, tags []string, isPublic bool, language string) (*entities.Deck, error) {
 	fmt.Printf("🔍 CREATE DECK - Iniciando criação de deck\n")
 	fmt.Printf("🔍 CREATE DECK - userID: %s\n", userID)
 	fmt.Printf("🔍 CREATE DECK - name: %s\n", name)
@@ -50,6 +65,31 @@ func (s *Service) CreateDeck(userID string, name, description string, tags []str
 
 	// removido deckCount não utilizado
 
+	// Nomes com "::" criam subdecks; os decks pais que faltarem são criados
+	// antes, depois de o plano comportar todos eles
+	name, err := normalizeDeckPath(name)
+	if err != nil {
+		return nil, err
+	}
+	language, err = normalizeDeckLanguage(language)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validatePublishing(userID, isPublic); err != nil {
+		return nil, err
+	}
+	existing, err := s.repo.GetDeckByUserAndName(userID, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check deck name: %w", err)
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("a deck named %q already exists", name)
+	}
+	parents, err := s.planDeckParents(userID, name)
+	if err != nil {
+		return nil, err
+	}
+
 	decks, _ := s.repo.GetDecksByUserEmail(userID)
 	fmt.Printf("🔍 CREATE DECK - Total de decks encontrados: %d\n", len(decks))
 
@@ -65,11 +105,17 @@ func (s *Service) CreateDeck(userID string, name, description string, tags []str
 					if user.Email == adminEmail {
 						fmt.Printf("🔍 CREATE DECK - Usuário é admin, ignorando limites\n")
 						// Ignora limites
+						parentID, err := s.createDeckParents(userID, parents, isPublic)
+						if err != nil {
+							return nil, err
+						}
 						deck := &entities.Deck{
 							Name:        name,
+							ParentID:    parentID,
 							Description: description,
 							Tags:        tags,
 							IsPublic:    isPublic,
+							Language:    language,
 						}
 						if err := s.repo.CreateDeckWithStringUserID(deck, userID); err != nil {
 							return nil, fmt.Errorf("failed to create deck: %w", err)
@@ -103,13 +149,13 @@ func (s *Service) CreateDeck(userID string, name, description string, tags []str
 	if s.adminService != nil {
 		if isPublic {
 			fmt.Printf("🔍 CREATE DECK - Validando limite de decks públicos (%d)\n", publicCount)
-			if err := s.adminService.ValidateDeckLimit(context.Background(), userPlan, publicCount, true); err != nil {
+			if err := s.adminService.ValidateDeckLimit(context.Background(), userPlan, publicCount+len(parents.missing), true); err != nil {
 				fmt.Printf("🔍 CREATE DECK - ERRO na validação de público: %v\n", err)
 				return nil, err
 			}
 		} else {
 			fmt.Printf("🔍 CREATE DECK - Validando limite de decks privados (%d)\n", privateCount)
-			if err := s.adminService.ValidateDeckLimit(context.Background(), userPlan, privateCount, false); err != nil {
+			if err := s.adminService.ValidateDeckLimit(context.Background(), userPlan, privateCount+len(parents.missing), false); err != nil {
 				fmt.Printf("🔍 CREATE DECK - ERRO na validação de privado: %v\n", err)
 				return nil, err
 			}
@@ -118,11 +164,18 @@ func (s *Service) CreateDeck(userID string, name, description string, tags []str
 
 	fmt.Printf("🔍 CREATE DECK - Validação passou, criando deck\n")
 
+	parentID, err := s.createDeckParents(userID, parents, isPublic)
+	if err != nil {
+		return nil, err
+	}
+
 	deck := &entities.Deck{
 		Name:        name,
+		ParentID:    parentID,
 		Description: description,
 		Tags:        tags,
 		IsPublic:    isPublic,
+		Language:    language,
 	}
 
 	if err := s.repo.CreateDeckWithStringUserID(deck, userID); err != nil {
@@ -170,33 +223,42 @@ func (s *Service) GetDecksByUserID(userID string) ([]entities.Deck, error) {
 	return decks, nil
 }
 
-func (s *Service) GetDeckByID(deckID string) (*entities.Deck, error) {
-	deckObjectID, err := primitive.ObjectIDFromHex(deckID)
-	if err != nil {
-		return nil, fmt.Errorf("invalid deck ID: %w", err)
-	}
-
-	return s.repo.GetDeckByID(deckObjectID)
+// GetDeckByID retorna o deck com o papel do usuário nele
+func (s *Service) GetDeckByID(userID, deckID string) (*entities.Deck, error) {
+	return s.getReadableDeck(userID, deckID)
 }
 
-func (s *Service) UpdateDeck(deckID string, name, description string, tags []string, color, border, background string, isPublic bool) (*entities.Deck, error) {
-	deckObjectID, err := primitive.ObjectIDFromHex(deckID)
+// UpdateDeck altera as configurações do deck; só o dono pode
+func (s *Service) UpdateDeck(userID, deckID string, name, description string, tags []string, color, border, background string, isPublic bool, language string) (*entities.Deck, error) {
+	deck, err := s.getOwnedDeck(userID, deckID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid deck ID: %w", err)
+		return nil, err
 	}
-
-	deck, err := s.repo.GetDeckByID(deckObjectID)
+	language, err = normalizeDeckLanguage(language)
 	if err != nil {
-		return nil, fmt.Errorf("deck not found: %w", err)
+		return nil, err
+	}
+	if isPublic && deck.ModerationLockedAt != nil {
+		return nil, ErrDeckLocked
+	}
+	if !deck.IsPublic {
+		if err := s.validatePublishing(userID, isPublic); err != nil {
+			return nil, err
+		}
+	}
+
+	// Renomear atualiza o caminho de todos os subdecks
+	if err := s.renameDeckTree(deck, name); err != nil {
+		return nil, err
 	}
 
-	deck.Name = name
 	deck.Description = description
 	deck.Tags = tags
 	deck.Color = color
 	deck.Border = border
 	deck.Background = background
 	deck.IsPublic = isPublic
+	deck.Language = language
 
 	if err := s.repo.UpdateDeck(deck); err != nil {
 		return nil, fmt.Errorf("failed to update deck: %w", err)
@@ -205,13 +267,26 @@ func (s *Service) UpdateDeck(deckID string, name, description string, tags []str
 	return deck, nil
 }
 
-func (s *Service) DeleteDeck(deckID string) error {
-	deckObjectID, err := primitive.ObjectIDFromHex(deckID)
+// deckLanguagePattern aceita códigos como "pt", "en" ou "pt-br"
+var deckLanguagePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})?$`)
+
+// normalizeDeckLanguage valida o idioma do deck, que é opcional
+func normalizeDeckLanguage(language string) (string, error) {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if language != "" && !deckLanguagePattern.MatchString(language) {
+		return "", fmt.Errorf("invalid language code: %s", language)
+	}
+	return language, nil
+}
+
+// DeleteDeck move o deck, seus subdecks e cards para a lixeira; só o dono pode
+func (s *Service) DeleteDeck(userID, deckID string) error {
+	deck, err := s.getOwnedDeck(userID, deckID)
 	if err != nil {
-		return fmt.Errorf("invalid deck ID: %w", err)
+		return err
 	}
 
-	return s.repo.DeleteDeck(deckObjectID)
+	return s.moveDeckToTrash(deck)
 }
 
 // Flashcard operations
@@ -221,6 +296,11 @@ func (s *Service) CreateFlashcard(userID, deckID, question, answer string, alter
 		return nil, fmt.Errorf("invalid user ID: %w", err)
 	}
 
+	// Donos e editores podem adicionar cards
+	if _, err := s.getEditableDeck(userID, deckID); err != nil {
+		return nil, err
+	}
+
 	// BYPASS: Se email do usuário está em AdminEmails, ignora limites
 	if s.authService != nil && s.adminService != nil {
 		user, err := s.authService.GetUserByID(userID)
@@ -244,6 +324,7 @@ func (s *Service) CreateFlashcard(userID, deckID, question, answer string, alter
 						if err := s.repo.CreateFlashcard(card); err != nil {
 							return nil, fmt.Errorf("failed to create flashcard: %w", err)
 						}
+						s.recordRevision(card, nil, s.revisionAuthorFor(userID), entities.RevisionCreate, "")
 						return card, nil
 					}
 				}
@@ -284,20 +365,41 @@ func (s *Service) CreateFlashcard(userID, deckID, question, answer string, alter
 	if err := s.repo.CreateFlashcard(card); err != nil {
 		return nil, fmt.Errorf("failed to create flashcard: %w", err)
 	}
+	s.recordRevision(card, nil, s.revisionAuthorFor(userID), entities.RevisionCreate, "")
 
 	return card, nil
 }
 
-func (s *Service) GetFlashcardsByDeckID(deckID string) ([]entities.Flashcard, error) {
-	return s.repo.GetFlashcardsByDeckIDString(deckID)
+// GetFlashcardsByDeckID lista os cards do deck com o estado de revisão de
+// quem consulta
+func (s *Service) GetFlashcardsByDeckID(userID, deckID string) ([]entities.Flashcard, error) {
+	deck, err := s.getReadableDeck(userID, deckID)
+	if err != nil {
+		return nil, err
+	}
+
+	cards, err := s.repo.GetFlashcardsByDeckIDString(deck.ID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if deck.UserID != userID {
+		if err := s.applyMemberSchedules(userID, []string{deck.ID.Hex()}, cards); err != nil {
+			return nil, err
+		}
+	}
+	return cards, nil
 }
 
-func (s *Service) UpdateFlashcard(cardID, question, answer string, alternatives []string, correctAlternative *int, imageURL, audioURL string, tags []string, difficulty int) (*entities.Flashcard, error) {
+func (s *Service) UpdateFlashcard(userID, cardID, question, answer string, alternatives []string, correctAlternative *int, imageURL, audioURL string, tags []string, difficulty int) (*entities.Flashcard, error) {
 	ctx := context.Background()
 	card, err := s.repo.GetByID(ctx, cardID)
 	if err != nil {
-		return nil, fmt.Errorf("flashcard not found: %w", err)
+		return nil, ErrCardNotFound
+	}
+	if _, err := s.getEditableDeck(userID, card.DeckID); err != nil {
+		return nil, err
 	}
+	before := cardContent(card)
 
 	card.Question = question
 	card.Answer = answer
@@ -311,52 +413,26 @@ func (s *Service) UpdateFlashcard(cardID, question, answer string, alternatives
 	if err := s.repo.Update(ctx, card); err != nil {
 		return nil, fmt.Errorf("failed to update flashcard: %w", err)
 	}
+	s.recordRevision(card, &before, s.revisionAuthorFor(userID), entities.RevisionUpdate, "")
 
 	return card, nil
 }
 
-func (s *Service) DeleteFlashcard(cardID string) error {
-	ctx := context.Background()
-	return s.repo.Delete(ctx, cardID)
-}
-
-// Study session operations
-func (s *Service) StartStudySession(userID, deckID string) (*entities.StudySession, error) {
-	userObjectID, err := primitive.ObjectIDFromHex(userID)
+// DeleteFlashcard move o card para a lixeira do dono do deck
+func (s *Service) DeleteFlashcard(userID, cardID string) error {
+	card, err := s.repo.GetByID(context.Background(), cardID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid user ID: %w", err)
-	}
-
-	deckObjectID, err := primitive.ObjectIDFromHex(deckID)
-	if err != nil {
-		return nil, fmt.Errorf("invalid deck ID: %w", err)
-	}
-
-	session := &entities.StudySession{
-		UserID: userObjectID,
-		DeckID: deckObjectID,
+		return ErrCardNotFound
 	}
-
-	if err := s.repo.CreateStudySession(session); err != nil {
-		return nil, fmt.Errorf("failed to create study session: %w", err)
+	if _, err := s.getEditableDeck(userID, card.DeckID); err != nil {
+		return err
 	}
 
-	return session, nil
-}
-
-func (s *Service) EndStudySession(sessionID string, cardsReviewed int, score float64) error {
-	sessionObjectID, err := primitive.ObjectIDFromHex(sessionID)
-	if err != nil {
-		return fmt.Errorf("invalid session ID: %w", err)
+	if err := s.moveCardToTrash(card, time.Now()); err != nil {
+		return err
 	}
-
-	session := &entities.StudySession{
-		ID:            sessionObjectID,
-		CardsReviewed: cardsReviewed,
-		Score:         score,
-	}
-
-	return s.repo.UpdateStudySession(session)
+	s.refreshDeckCardCounts(card.DeckID)
+	return nil
 }
 
 func (s *Service) GetStudyHistory(userID string, limit int64) ([]entities.StudySession, error) {
@@ -0,0 +1,104 @@
+package flashcards
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateShareLink cria um link não listado para o deck
+// POST /api/decks/:id/share-links
+func (h *Handler) CreateShareLink(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		ExpiresInHours int `json:"expires_in_hours"`
+		MaxUses        int `json:"max_uses"`
+	}
+	// O corpo é opcional: sem ele o link não expira e não tem limite de usos
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	link, err := h.service.CreateShareLink(userID, c.Param("id"), req.ExpiresInHours, req.MaxUses)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, link)
+}
+
+// GetShareLinks lista os links do deck
+// GET /api/decks/:id/share-links
+func (h *Handler) GetShareLinks(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	links, err := h.service.GetShareLinks(userID, c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, links)
+}
+
+// RevokeShareLink revoga um link do deck
+// DELETE /api/decks/:id/share-links/:linkId
+func (h *Handler) RevokeShareLink(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.RevokeShareLink(userID, c.Param("id"), c.Param("linkId")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Share link revoked successfully"})
+}
+
+// PreviewSharedDeck mostra o deck do link sem exigir login
+// GET /share/:token
+func (h *Handler) PreviewSharedDeck(c *gin.Context) {
+	preview, err := h.service.PreviewSharedDeck(c.Param("token"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
+// ImportSharedDeck copia o deck do link para a conta do usuário
+// POST /api/share/:token/import
+func (h *Handler) ImportSharedDeck(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.ImportSharedDeck(userID, c.Param("token"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Como nos forks, a cópia não rende XP
+	if err := h.statsService.LogDeckCreated(c.Request.Context(), userID, result.Deck.ID.Hex(), 0); err != nil {
+		fmt.Printf("Failed to log deck creation: %v\n", err)
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
@@ -0,0 +1,100 @@
+package flashcards
+
+import (
+	"context"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (r *MongoRepository) CreateShareLink(link *entities.ShareLink) error {
+	link.ID = primitive.NewObjectID()
+	_, err := r.db.GetCollection("share_links").InsertOne(context.Background(), link)
+	return err
+}
+
+func (r *MongoRepository) GetShareLink(linkID primitive.ObjectID) (*entities.ShareLink, error) {
+	var link entities.ShareLink
+	err := r.db.GetCollection("share_links").FindOne(context.Background(), bson.M{"_id": linkID}).Decode(&link)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// GetShareLinks lista os links de um deck, dos mais recentes para os mais antigos
+func (r *MongoRepository) GetShareLinks(deckID string) ([]entities.ShareLink, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.db.GetCollection("share_links").Find(context.Background(), bson.M{"deck_id": deckID}, opts)
+	if err != nil {
+		return []entities.ShareLink{}, err
+	}
+	defer cursor.Close(context.Background())
+
+	var links []entities.ShareLink
+	if err = cursor.All(context.Background(), &links); err != nil {
+		return []entities.ShareLink{}, err
+	}
+	if links == nil {
+		links = []entities.ShareLink{}
+	}
+	return links, nil
+}
+
+// ConsumeShareLink registra um uso do link se ele ainda estiver válido. A
+// verificação e o incremento são atômicos, então o limite de usos não é
+// ultrapassado por importações simultâneas. Retorna false se o link não
+// puder mais ser usado.
+func (r *MongoRepository) ConsumeShareLink(linkID primitive.ObjectID, now time.Time) (bool, error) {
+	filter := bson.M{
+		"_id":        linkID,
+		"revoked_at": bson.M{"$exists": false},
+		"$and": bson.A{
+			bson.M{"$or": bson.A{
+				bson.M{"expires_at": bson.M{"$exists": false}},
+				bson.M{"expires_at": bson.M{"$gt": now}},
+			}},
+			bson.M{"$or": bson.A{
+				bson.M{"max_uses": 0},
+				bson.M{"$expr": bson.M{"$lt": bson.A{"$uses", "$max_uses"}}},
+			}},
+		},
+	}
+	result, err := r.db.GetCollection("share_links").UpdateOne(context.Background(), filter, bson.M{"$inc": bson.M{"uses": 1}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// ReleaseShareLink devolve um uso consumido por uma importação que falhou
+func (r *MongoRepository) ReleaseShareLink(linkID primitive.ObjectID) error {
+	_, err := r.db.GetCollection("share_links").UpdateOne(
+		context.Background(),
+		bson.M{"_id": linkID, "uses": bson.M{"$gt": 0}},
+		bson.M{"$inc": bson.M{"uses": -1}},
+	)
+	return err
+}
+
+func (r *MongoRepository) RevokeShareLink(linkID primitive.ObjectID, now time.Time) error {
+	_, err := r.db.GetCollection("share_links").UpdateOne(
+		context.Background(),
+		bson.M{"_id": linkID, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": now}},
+	)
+	return err
+}
+
+func (r *MongoRepository) DeleteShareLinks(deckID string) error {
+	_, err := r.db.GetCollection("share_links").DeleteMany(context.Background(), bson.M{"deck_id": deckID})
+	return err
+}
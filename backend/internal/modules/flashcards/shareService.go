@@ -0,0 +1,232 @@
+package flashcards
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrShareLinkNotFound = errors.New("share link not found")
+	ErrShareLinkExpired  = errors.New("share link has expired or reached its use limit")
+)
+
+// maxPreviewCards limita os cards mostrados na prévia pública
+const maxPreviewCards = 100
+
+// SharedDeckPreview é a visão somente leitura de um deck aberto por link
+type SharedDeckPreview struct {
+	Name          string                 `json:"name"`
+	Description   string                 `json:"description"`
+	Tags          []string               `json:"tags,omitempty"`
+	Language      string                 `json:"language,omitempty"`
+	OwnerName     string                 `json:"owner_name,omitempty"`
+	CardCount     int                    `json:"card_count"`
+	Cards         []entities.CardContent `json:"cards"`
+	ExpiresAt     *time.Time             `json:"expires_at,omitempty"`
+	RemainingUses *int                   `json:"remaining_uses,omitempty"`
+}
+
+// signShareToken gera o token do link: o ID seguido da assinatura HMAC do ID
+func signShareToken(secret string, linkID primitive.ObjectID) string {
+	return linkID.Hex() + "." + shareSignature(secret, linkID.Hex())
+}
+
+// parseShareToken confere a assinatura do token e retorna o ID do link
+func parseShareToken(secret, token string) (primitive.ObjectID, bool) {
+	id, signature, found := strings.Cut(token, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(shareSignature(secret, id))) {
+		return primitive.NilObjectID, false
+	}
+	linkID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, false
+	}
+	return linkID, true
+}
+
+func shareSignature(secret, id string) string {
+	mac := hmac.New(sha256.New, []byte("share-link:"+secret))
+	mac.Write([]byte(id))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:18])
+}
+
+// shareLinkUsable indica se o link ainda pode ser aberto
+func shareLinkUsable(link *entities.ShareLink, now time.Time) bool {
+	if link.RevokedAt != nil {
+		return false
+	}
+	if link.ExpiresAt != nil && !now.Before(*link.ExpiresAt) {
+		return false
+	}
+	return link.MaxUses == 0 || link.Uses < link.MaxUses
+}
+
+func (s *Service) shareSecret() string {
+	return s.cfg.Auth.JWTSecret
+}
+
+// CreateShareLink cria um link para o deck. expiresInHours e maxUses iguais a
+// zero deixam o link sem validade e sem limite de usos.
+func (s *Service) CreateShareLink(userID, deckID string, expiresInHours, maxUses int) (*entities.ShareLink, error) {
+	deck, err := s.getOwnedDeck(userID, deckID)
+	if err != nil {
+		return nil, err
+	}
+	if expiresInHours < 0 {
+		return nil, fmt.Errorf("expires_in_hours must be positive")
+	}
+	if maxUses < 0 {
+		return nil, fmt.Errorf("max_uses must be positive")
+	}
+
+	now := time.Now()
+	link := &entities.ShareLink{
+		DeckID:    deck.ID.Hex(),
+		UserID:    userID,
+		MaxUses:   maxUses,
+		CreatedAt: now,
+	}
+	if expiresInHours > 0 {
+		expiresAt := now.Add(time.Duration(expiresInHours) * time.Hour)
+		link.ExpiresAt = &expiresAt
+	}
+	if err := s.repo.CreateShareLink(link); err != nil {
+		return nil, fmt.Errorf("failed to create share link: %w", err)
+	}
+	link.Token = signShareToken(s.shareSecret(), link.ID)
+	return link, nil
+}
+
+// GetShareLinks lista os links do deck, incluindo os revogados e expirados
+func (s *Service) GetShareLinks(userID, deckID string) ([]entities.ShareLink, error) {
+	deck, err := s.getOwnedDeck(userID, deckID)
+	if err != nil {
+		return nil, err
+	}
+	links, err := s.repo.GetShareLinks(deck.ID.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get share links: %w", err)
+	}
+	for i := range links {
+		links[i].Token = signShareToken(s.shareSecret(), links[i].ID)
+	}
+	return links, nil
+}
+
+// RevokeShareLink invalida o link imediatamente
+func (s *Service) RevokeShareLink(userID, deckID, linkID string) error {
+	deck, err := s.getOwnedDeck(userID, deckID)
+	if err != nil {
+		return err
+	}
+	linkObjectID, err := primitive.ObjectIDFromHex(linkID)
+	if err != nil {
+		return ErrShareLinkNotFound
+	}
+	link, err := s.repo.GetShareLink(linkObjectID)
+	if err != nil {
+		return fmt.Errorf("failed to get share link: %w", err)
+	}
+	if link == nil || link.DeckID != deck.ID.Hex() {
+		return ErrShareLinkNotFound
+	}
+	if err := s.repo.RevokeShareLink(link.ID, time.Now()); err != nil {
+		return fmt.Errorf("failed to revoke share link: %w", err)
+	}
+	return nil
+}
+
+// resolveShareLink valida o token e retorna o link e o deck compartilhado
+func (s *Service) resolveShareLink(token string) (*entities.ShareLink, *entities.Deck, error) {
+	linkID, ok := parseShareToken(s.shareSecret(), token)
+	if !ok {
+		return nil, nil, ErrShareLinkNotFound
+	}
+	link, err := s.repo.GetShareLink(linkID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get share link: %w", err)
+	}
+	if link == nil || link.RevokedAt != nil {
+		return nil, nil, ErrShareLinkNotFound
+	}
+	if !shareLinkUsable(link, time.Now()) {
+		return nil, nil, ErrShareLinkExpired
+	}
+	// Um deck na lixeira ou que mudou de dono invalida o link
+	deck, err := s.getDeck(link.DeckID)
+	if err != nil || deck.UserID != link.UserID {
+		return nil, nil, ErrShareLinkNotFound
+	}
+	return link, deck, nil
+}
+
+// PreviewSharedDeck mostra o deck do link sem exigir login. A prévia não
+// consome usos do link.
+func (s *Service) PreviewSharedDeck(token string) (*SharedDeckPreview, error) {
+	link, deck, err := s.resolveShareLink(token)
+	if err != nil {
+		return nil, err
+	}
+	cards, err := s.repo.GetFlashcardsByDeckIDString(deck.ID.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cards: %w", err)
+	}
+
+	preview := &SharedDeckPreview{
+		Name:        deck.Name,
+		Description: deck.Description,
+		Tags:        deck.Tags,
+		Language:    deck.Language,
+		CardCount:   len(cards),
+		Cards:       []entities.CardContent{},
+		ExpiresAt:   link.ExpiresAt,
+	}
+	if owner, err := s.getUser(deck.UserID); err == nil {
+		preview.OwnerName = owner.Name
+	}
+	if link.MaxUses > 0 {
+		remaining := link.MaxUses - link.Uses
+		preview.RemainingUses = &remaining
+	}
+	for i := range cards {
+		if i == maxPreviewCards {
+			break
+		}
+		preview.Cards = append(preview.Cards, cardContent(&cards[i]))
+	}
+	return preview, nil
+}
+
+// ImportSharedDeck copia o deck do link para a conta do usuário. Cada
+// importação consome um uso do link.
+func (s *Service) ImportSharedDeck(userID, token string) (*ForkResult, error) {
+	link, deck, err := s.resolveShareLink(token)
+	if err != nil {
+		return nil, err
+	}
+	consumed, err := s.repo.ConsumeShareLink(link.ID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to use share link: %w", err)
+	}
+	if !consumed {
+		return nil, ErrShareLinkExpired
+	}
+
+	result, err := s.copyDeck(userID, deck)
+	if err != nil {
+		if releaseErr := s.repo.ReleaseShareLink(link.ID); releaseErr != nil {
+			fmt.Printf("Failed to release share link %s: %v\n", link.ID.Hex(), releaseErr)
+		}
+		return nil, err
+	}
+	return result, nil
+}
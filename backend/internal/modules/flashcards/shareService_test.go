@@ -0,0 +1,42 @@
+package flashcards
+
+import (
+	"testing"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestShareToken(t *testing.T) {
+	linkID := primitive.NewObjectID()
+	token := signShareToken("secret", linkID)
+
+	parsed, ok := parseShareToken("secret", token)
+	assert.True(t, ok)
+	assert.Equal(t, linkID, parsed)
+
+	// Outro segredo, assinatura adulterada ou ID trocado invalidam o token
+	_, ok = parseShareToken("other", token)
+	assert.False(t, ok)
+	_, ok = parseShareToken("secret", token+"x")
+	assert.False(t, ok)
+	_, ok = parseShareToken("secret", primitive.NewObjectID().Hex()+token[24:])
+	assert.False(t, ok)
+	_, ok = parseShareToken("secret", linkID.Hex())
+	assert.False(t, ok)
+}
+
+func TestShareLinkUsable(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	assert.True(t, shareLinkUsable(&entities.ShareLink{}, now))
+	assert.True(t, shareLinkUsable(&entities.ShareLink{ExpiresAt: &future, MaxUses: 2, Uses: 1}, now))
+	assert.False(t, shareLinkUsable(&entities.ShareLink{ExpiresAt: &past}, now))
+	assert.False(t, shareLinkUsable(&entities.ShareLink{MaxUses: 2, Uses: 2}, now))
+	assert.False(t, shareLinkUsable(&entities.ShareLink{RevokedAt: &past}, now))
+}
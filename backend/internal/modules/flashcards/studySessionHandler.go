@@ -0,0 +1,103 @@
+package flashcards
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NextSessionCard entrega o card atual da sessão
+// GET /api/study/:id/next
+func (h *Handler) NextSessionCard(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	next, err := h.service.NextSessionCard(userID, c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, next)
+}
+
+// AnswerSessionCard registra a resposta ao card atual da sessão
+// POST /api/study/:id/answer
+func (h *Handler) AnswerSessionCard(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		CardID     string `json:"card_id" binding:"required"`
+		Difficulty string `json:"difficulty" binding:"required"` // "again", "hard", "good", "easy"
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.AnswerSessionCard(userID, c.Param("id"), req.CardID, req.Difficulty)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	answer := result.Answer
+	err = h.statsService.LogCardReview(c.Request.Context(), userID, answer.DeckID, answer.CardID, answer.Difficulty, answer.IsCorrect, answer.TimeSpent, answer.XP)
+	if err != nil {
+		fmt.Printf("Failed to log card review: %v\n", err)
+		// Não falhar a operação principal por causa do log
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// StartFilteredSession abre uma sessão com os cards que casam com uma busca
+// POST /api/study/filtered
+func (h *Handler) StartFilteredSession(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req FilteredSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, err := h.service.StartFilteredSession(userID, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	err = h.statsService.LogStudySessionStart(c.Request.Context(), userID, session.ID.Hex(), nil)
+	if err != nil {
+		fmt.Printf("Failed to log study session start: %v\n", err)
+		// Não falhar a operação principal por causa do log
+	}
+
+	c.JSON(http.StatusCreated, session)
+}
+
+// DeleteStudySession apaga uma sessão filtrada e devolve os cards aos seus decks
+// DELETE /api/study/:id
+func (h *Handler) DeleteStudySession(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteStudySession(userID, c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Study session deleted successfully"})
+}
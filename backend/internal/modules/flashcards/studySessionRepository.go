@@ -0,0 +1,163 @@
+package flashcards
+
+import (
+	"context"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (r *MongoRepository) GetStudySession(id primitive.ObjectID) (*entities.StudySession, error) {
+	var session entities.StudySession
+	err := r.db.GetCollection("study_sessions").FindOne(context.Background(), bson.M{"_id": id}).Decode(&session)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrStudySessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// MarkSessionCardShown registra quando o card da posição foi entregue. Só a
+// primeira entrega conta, para pedir o mesmo card de novo não zerar o tempo.
+func (r *MongoRepository) MarkSessionCardShown(id primitive.ObjectID, position int, now time.Time) error {
+	_, err := r.db.GetCollection("study_sessions").UpdateOne(context.Background(),
+		bson.M{"_id": id, "status": entities.StudySessionActive, "position": position, "shown_at": nil},
+		bson.M{"$set": bson.M{"shown_at": now, "last_activity": now}},
+	)
+	return err
+}
+
+// SkipSessionCard pula o card da posição, usado quando ele foi apagado
+// depois do início da sessão
+func (r *MongoRepository) SkipSessionCard(id primitive.ObjectID, position int) error {
+	_, err := r.db.GetCollection("study_sessions").UpdateOne(context.Background(),
+		bson.M{"_id": id, "status": entities.StudySessionActive, "position": position},
+		bson.M{"$inc": bson.M{"position": 1}, "$unset": bson.M{"shown_at": ""}},
+	)
+	return err
+}
+
+// RecordSessionAnswer grava a resposta do card da posição e avança a fila.
+// Retorna false se a sessão já saiu dessa posição (resposta repetida ou
+// sessão encerrada).
+func (r *MongoRepository) RecordSessionAnswer(id primitive.ObjectID, position int, answer entities.SessionAnswer) (bool, error) {
+	inc := bson.M{"position": 1, "cards_reviewed": 1, "xp": answer.XP}
+	if answer.IsCorrect {
+		inc["correct_count"] = 1
+	}
+	result, err := r.db.GetCollection("study_sessions").UpdateOne(context.Background(),
+		bson.M{"_id": id, "status": entities.StudySessionActive, "position": position},
+		bson.M{
+			"$push":  bson.M{"answers": answer},
+			"$inc":   inc,
+			"$set":   bson.M{"last_activity": answer.AnsweredAt},
+			"$unset": bson.M{"shown_at": ""},
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// FinishStudySession grava o resultado de uma sessão ainda ativa. Retorna
+// false se ela já tinha sido encerrada.
+func (r *MongoRepository) FinishStudySession(session *entities.StudySession) (bool, error) {
+	result, err := r.db.GetCollection("study_sessions").UpdateOne(context.Background(),
+		bson.M{"_id": session.ID, "status": entities.StudySessionActive},
+		bson.M{
+			"$set": bson.M{
+				"status":           session.Status,
+				"end_time":         session.EndTime,
+				"duration":         session.Duration,
+				"duration_seconds": session.DurationSeconds,
+				"cards_reviewed":   session.CardsReviewed,
+				"correct_count":    session.CorrectCount,
+				"score":            session.Score,
+			},
+			"$unset": bson.M{"shown_at": ""},
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// GetIdleStudySessions lista as sessões ativas sem atividade desde before
+func (r *MongoRepository) GetIdleStudySessions(before time.Time, limit int64) ([]entities.StudySession, error) {
+	ctx := context.Background()
+	cursor, err := r.db.GetCollection("study_sessions").Find(ctx,
+		bson.M{"status": entities.StudySessionActive, "last_activity": bson.M{"$lt": before}},
+		options.Find().SetLimit(limit),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	sessions := []entities.StudySession{}
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// ClaimFilteredCards prende os cards a uma sessão filtrada, pulando os que já
+// estão em outra, e retorna os IDs que ficaram com esta sessão
+func (r *MongoRepository) ClaimFilteredCards(sessionID string, ids []primitive.ObjectID) (map[primitive.ObjectID]bool, error) {
+	ctx := context.Background()
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$in": ids}, "filteredSession": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"filteredSession": sessionID}},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := r.collection.Find(ctx,
+		bson.M{"_id": bson.M{"$in": ids}, "filteredSession": sessionID},
+		options.Find().SetProjection(bson.M{"_id": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	claimed := make(map[primitive.ObjectID]bool, len(docs))
+	for _, doc := range docs {
+		claimed[doc.ID] = true
+	}
+	return claimed, nil
+}
+
+// ReleaseFilteredCards devolve os cards da sessão filtrada às revisões do
+// seu deck
+func (r *MongoRepository) ReleaseFilteredCards(sessionID string) (int64, error) {
+	result, err := r.collection.UpdateMany(context.Background(),
+		bson.M{"filteredSession": sessionID},
+		bson.M{"$unset": bson.M{"filteredSession": ""}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *MongoRepository) DeleteStudySession(id primitive.ObjectID) error {
+	_, err := r.db.GetCollection("study_sessions").DeleteOne(context.Background(), bson.M{"_id": id})
+	return err
+}
@@ -0,0 +1,361 @@
+package flashcards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrStudySessionNotFound = errors.New("study session not found")
+	ErrStudySessionClosed   = errors.New("study session has already ended")
+	ErrNotCurrentCard       = errors.New("card is not the current card of the session")
+	ErrNotFilteredSession   = errors.New("only filtered study sessions can be deleted")
+)
+
+const (
+	defaultSessionCards = 100
+	maxSessionCards     = 500
+	// defaultSessionTimeout é a inatividade até a sessão ser abandonada,
+	// quando STUDY_SESSION_TIMEOUT_MINUTES não está configurado
+	defaultSessionTimeout = 30 * time.Minute
+	// maxAnswerSeconds limita o tempo contado numa resposta, para uma pausa
+	// com o card aberto não inflar o tempo de estudo
+	maxAnswerSeconds = 300
+	// sessionExpiryInterval é de quanto em quanto tempo as sessões paradas
+	// são encerradas
+	sessionExpiryInterval = 5 * time.Minute
+	sessionExpiryBatch    = 100
+)
+
+// SessionCard é o próximo card de uma sessão
+type SessionCard struct {
+	Session   *entities.StudySession `json:"session"`
+	Card      *entities.Flashcard    `json:"card,omitempty"`
+	Remaining int                    `json:"remaining"` // cards ainda não respondidos, incluindo este
+	Finished  bool                   `json:"finished"`  // a fila acabou; falta encerrar a sessão
+}
+
+// SessionAnswerResult é o resultado de uma resposta dada numa sessão
+type SessionAnswerResult struct {
+	Answer     entities.SessionAnswer `json:"answer"`
+	NextReview *time.Time             `json:"next_review,omitempty"`
+	Remaining  int                    `json:"remaining"`
+	Finished   bool                   `json:"finished"`
+}
+
+// reviewXP calcula o XP de uma resposta: respostas mais difíceis valem mais
+// e acertar dobra o valor
+func reviewXP(difficulty string, isCorrect bool) int {
+	xp := 0
+	switch difficulty {
+	case "easy":
+		xp = 1
+	case "good":
+		xp = 2
+	case "hard":
+		xp = 3
+	case "again":
+		xp = 0
+	}
+	if isCorrect {
+		xp *= 2 // bônus por acertar
+	}
+	return xp
+}
+
+func isSessionDifficulty(difficulty string) bool {
+	for _, answer := range ratedAnswers {
+		if difficulty == answer {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Service) sessionTimeout() time.Duration {
+	if s.cfg != nil && s.cfg.Study.SessionTimeoutMinutes > 0 {
+		return time.Duration(s.cfg.Study.SessionTimeoutMinutes) * time.Minute
+	}
+	return defaultSessionTimeout
+}
+
+// StartStudySession abre uma sessão com a fila de cards para revisão do
+// deck e seus subdecks, na ordem em que serão entregues
+func (s *Service) StartStudySession(userID, deckID string, limit int) (*entities.StudySession, error) {
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+	if limit <= 0 {
+		limit = defaultSessionCards
+	}
+	if limit > maxSessionCards {
+		limit = maxSessionCards
+	}
+
+	deck, err := s.getReadableDeck(userID, deckID)
+	if err != nil {
+		return nil, err
+	}
+	cards, err := s.GetDueCards(userID, deckID, int64(limit))
+	if err != nil {
+		return nil, err
+	}
+	queue := make([]string, len(cards))
+	for i, card := range cards {
+		queue[i] = card.ID.Hex()
+	}
+
+	session := &entities.StudySession{
+		UserID:       userObjectID,
+		DeckID:       deck.ID,
+		Status:       entities.StudySessionActive,
+		Queue:        queue,
+		TotalCards:   len(queue),
+		LastActivity: time.Now(),
+	}
+	if err := s.repo.CreateStudySession(session); err != nil {
+		return nil, fmt.Errorf("failed to create study session: %w", err)
+	}
+
+	return session, nil
+}
+
+// getActiveSession retorna a sessão ativa do usuário. Uma sessão parada há
+// mais que o tempo limite é encerrada como abandonada nesse momento.
+func (s *Service) getActiveSession(userID, sessionID string) (*entities.StudySession, error) {
+	id, err := primitive.ObjectIDFromHex(sessionID)
+	if err != nil {
+		return nil, ErrStudySessionNotFound
+	}
+	session, err := s.repo.GetStudySession(id)
+	if err != nil {
+		return nil, err
+	}
+	if session.UserID.Hex() != userID {
+		return nil, ErrStudySessionNotFound
+	}
+	if session.Status != entities.StudySessionActive {
+		return nil, ErrStudySessionClosed
+	}
+	if time.Since(session.LastActivity) > s.sessionTimeout() {
+		if err := s.finishStudySession(session, entities.StudySessionAbandoned, session.LastActivity); err != nil && !errors.Is(err, ErrStudySessionClosed) {
+			return nil, err
+		}
+		return nil, ErrStudySessionClosed
+	}
+	return session, nil
+}
+
+// NextSessionCard entrega o card atual da fila. Pedir de novo antes de
+// responder devolve o mesmo card; cards apagados durante a sessão são
+// pulados.
+func (s *Service) NextSessionCard(userID, sessionID string) (*SessionCard, error) {
+	session, err := s.getActiveSession(userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	for session.Position < len(session.Queue) {
+		card, err := s.repo.GetByID(ctx, session.Queue[session.Position])
+		if err != nil {
+			if err := s.repo.SkipSessionCard(session.ID, session.Position); err != nil {
+				return nil, fmt.Errorf("failed to update session: %w", err)
+			}
+			session.Position++
+			continue
+		}
+
+		now := time.Now()
+		if err := s.repo.MarkSessionCardShown(session.ID, session.Position, now); err != nil {
+			return nil, fmt.Errorf("failed to update session: %w", err)
+		}
+		if session.ShownAt == nil {
+			session.ShownAt = &now
+		}
+		session.LastActivity = now
+		return &SessionCard{Session: session, Card: card, Remaining: len(session.Queue) - session.Position}, nil
+	}
+	return &SessionCard{Session: session, Finished: true}, nil
+}
+
+// AnswerSessionCard registra a resposta ao card atual, reagenda o card e
+// avança a fila. "again" conta como erro; as demais respostas, como acerto.
+// Numa sessão filtrada em modo cram o card não é reagendado.
+func (s *Service) AnswerSessionCard(userID, sessionID, cardID, difficulty string) (*SessionAnswerResult, error) {
+	if !isSessionDifficulty(difficulty) {
+		return nil, fmt.Errorf("invalid difficulty: %s (use again, hard, good or easy)", difficulty)
+	}
+	session, err := s.getActiveSession(userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	position := session.Position
+	if position >= len(session.Queue) || session.Queue[position] != cardID {
+		return nil, ErrNotCurrentCard
+	}
+	card, err := s.repo.GetByID(context.Background(), cardID)
+	if err != nil {
+		return nil, ErrCardNotFound
+	}
+
+	now := time.Now()
+	isCorrect := difficulty != "again"
+	answer := entities.SessionAnswer{
+		CardID:     cardID,
+		DeckID:     card.DeckID,
+		Difficulty: difficulty,
+		IsCorrect:  isCorrect,
+		TimeSpent:  answerSeconds(session.ShownAt, now),
+		XP:         reviewXP(difficulty, isCorrect),
+		AnsweredAt: now,
+	}
+
+	// A resposta ocupa a posição antes de reagendar, para um envio repetido
+	// não reagendar o card duas vezes
+	recorded, err := s.repo.RecordSessionAnswer(session.ID, position, answer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record answer: %w", err)
+	}
+	if !recorded {
+		return nil, ErrNotCurrentCard
+	}
+
+	remaining := len(session.Queue) - position - 1
+	result := &SessionAnswerResult{Answer: answer, Remaining: remaining, Finished: remaining == 0}
+	if !reschedules(session) {
+		return result, nil
+	}
+
+	reviewed, err := s.ReviewCard(userID, cardID, isCorrect)
+	if err != nil {
+		return nil, err
+	}
+	result.NextReview = reviewed.NextReview
+	return result, nil
+}
+
+// answerSeconds é o tempo entre a entrega do card e a resposta
+func answerSeconds(shownAt *time.Time, answeredAt time.Time) int {
+	if shownAt == nil {
+		return 0
+	}
+	return clampAnswerSeconds(int(answeredAt.Sub(*shownAt).Seconds()))
+}
+
+// clampAnswerSeconds limita o tempo de uma resposta a [0, maxAnswerSeconds],
+// inclusive o informado pelo cliente fora de uma sessão
+func clampAnswerSeconds(seconds int) int {
+	if seconds < 0 {
+		return 0
+	}
+	if seconds > maxAnswerSeconds {
+		return maxAnswerSeconds
+	}
+	return seconds
+}
+
+// EndStudySession encerra a sessão com os números calculados a partir das
+// respostas registradas
+func (s *Service) EndStudySession(userID, sessionID string) (*entities.StudySession, error) {
+	session, err := s.getActiveSession(userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.finishStudySession(session, entities.StudySessionCompleted, time.Now()); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (s *Service) finishStudySession(session *entities.StudySession, status string, endTime time.Time) error {
+	summarizeStudySession(session, status, endTime)
+	finished, err := s.repo.FinishStudySession(session)
+	if err != nil {
+		return fmt.Errorf("failed to end study session: %w", err)
+	}
+	if !finished {
+		return ErrStudySessionClosed
+	}
+	s.releaseFilteredCards(session)
+	return nil
+}
+
+// summarizeStudySession calcula duração, total de cards, acertos e XP a
+// partir das respostas
+func summarizeStudySession(session *entities.StudySession, status string, endTime time.Time) {
+	if endTime.Before(session.StartTime) {
+		endTime = session.StartTime
+	}
+	session.Status = status
+	session.EndTime = endTime
+	session.DurationSeconds = int(endTime.Sub(session.StartTime).Seconds())
+	session.Duration = session.DurationSeconds / 60
+	session.ShownAt = nil
+
+	session.CardsReviewed = len(session.Answers)
+	session.CorrectCount = 0
+	session.XP = 0
+	for _, answer := range session.Answers {
+		if answer.IsCorrect {
+			session.CorrectCount++
+		}
+		session.XP += answer.XP
+	}
+	session.Score = 0
+	if session.CardsReviewed > 0 {
+		session.Score = float64(session.CorrectCount) * 100 / float64(session.CardsReviewed)
+	}
+}
+
+// ExpireIdleStudySessions encerra como abandonadas as sessões sem atividade
+// há mais que o tempo limite. A duração vai até a última atividade.
+func (s *Service) ExpireIdleStudySessions(now time.Time) (int, error) {
+	expired := 0
+	for {
+		sessions, err := s.repo.GetIdleStudySessions(now.Add(-s.sessionTimeout()), sessionExpiryBatch)
+		if err != nil {
+			return expired, fmt.Errorf("failed to get idle sessions: %w", err)
+		}
+		if len(sessions) == 0 {
+			return expired, nil
+		}
+		for i := range sessions {
+			err := s.finishStudySession(&sessions[i], entities.StudySessionAbandoned, sessions[i].LastActivity)
+			if err == nil {
+				expired++
+			} else if !errors.Is(err, ErrStudySessionClosed) {
+				return expired, err
+			}
+		}
+	}
+}
+
+// StartStudySessionExpiry encerra periodicamente as sessões abandonadas até
+// o contexto ser cancelado
+func (s *Service) StartStudySessionExpiry(ctx context.Context) {
+	ticker := time.NewTicker(sessionExpiryInterval)
+	defer ticker.Stop()
+
+	for {
+		expired, err := s.ExpireIdleStudySessions(time.Now())
+		if err != nil {
+			fmt.Printf("Failed to expire study sessions: %v\n", err)
+		} else if expired > 0 {
+			fmt.Printf("Expired %d abandoned study sessions\n", expired)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
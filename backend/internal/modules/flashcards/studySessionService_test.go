@@ -0,0 +1,120 @@
+package flashcards
+
+import (
+	"testing"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+	"flashcard-backend/internal/infrastructure/database"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestSummarizeStudySession(t *testing.T) {
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	session := &entities.StudySession{
+		StartTime: start,
+		Answers: []entities.SessionAnswer{
+			{IsCorrect: true, XP: 4},
+			{IsCorrect: false, XP: 0},
+			{IsCorrect: true, XP: 2},
+			{IsCorrect: true, XP: 6},
+		},
+	}
+
+	summarizeStudySession(session, entities.StudySessionCompleted, start.Add(150*time.Second))
+	assert.Equal(t, entities.StudySessionCompleted, session.Status)
+	assert.Equal(t, 4, session.CardsReviewed)
+	assert.Equal(t, 3, session.CorrectCount)
+	assert.Equal(t, 75.0, session.Score)
+	assert.Equal(t, 12, session.XP)
+	assert.Equal(t, 150, session.DurationSeconds)
+	assert.Equal(t, 2, session.Duration)
+
+	// Sessão sem respostas não divide por zero
+	empty := &entities.StudySession{StartTime: start}
+	summarizeStudySession(empty, entities.StudySessionAbandoned, start)
+	assert.Equal(t, 0.0, empty.Score)
+	assert.Equal(t, 0, empty.DurationSeconds)
+}
+
+func TestAnswerSeconds(t *testing.T) {
+	shown := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, 0, answerSeconds(nil, shown))
+	assert.Equal(t, 12, answerSeconds(&shown, shown.Add(12*time.Second)))
+	// Pausas longas com o card aberto contam só até o limite
+	assert.Equal(t, maxAnswerSeconds, answerSeconds(&shown, shown.Add(time.Hour)))
+
+	assert.Equal(t, 0, clampAnswerSeconds(-5))
+	assert.Equal(t, 40, clampAnswerSeconds(40))
+	assert.Equal(t, maxAnswerSeconds, clampAnswerSeconds(100000))
+}
+
+func TestReviewXP(t *testing.T) {
+	assert.Equal(t, 0, reviewXP("again", false))
+	assert.Equal(t, 6, reviewXP("hard", true))
+	assert.Equal(t, 2, reviewXP("easy", true))
+}
+
+func TestReschedules(t *testing.T) {
+	assert.True(t, reschedules(&entities.StudySession{}))
+	assert.True(t, reschedules(&entities.StudySession{Kind: entities.StudySessionFiltered, Reschedule: true}))
+	// Modo cram: a sessão filtrada não mexe no agendamento
+	assert.False(t, reschedules(&entities.StudySession{Kind: entities.StudySessionFiltered}))
+}
+
+func TestStudySessionIsAnsweredThroughCreatedID(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("start and answer", func(mt *mtest.T) {
+		db := &database.MongoDB{Client: mt.Client, Database: mt.DB}
+		service := NewService(NewMongoRepository(db), nil, nil, nil, nil)
+
+		userID := primitive.NewObjectID()
+		card := CardDocument{ID: primitive.NewObjectID(), DeckID: primitive.NewObjectID().Hex(), Question: "Q", Answer: "A"}
+		session := &entities.StudySession{
+			UserID:       userID,
+			Kind:         entities.StudySessionFiltered, // modo cram: responder não reagenda
+			Status:       entities.StudySessionActive,
+			Queue:        []string{card.ID.Hex()},
+			TotalCards:   1,
+			LastActivity: time.Now(),
+		}
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+		assert.NoError(mt, service.repo.CreateStudySession(session))
+		assert.False(mt, session.ID.IsZero())
+		mt.ClearEvents()
+
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "db.study_sessions", mtest.FirstBatch, toBSONDocument(mt, session)),
+			mtest.CreateCursorResponse(0, "db.cards", mtest.FirstBatch, toBSONDocument(mt, card)),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+		)
+
+		result, err := service.AnswerSessionCard(userID.Hex(), session.ID.Hex(), card.ID.Hex(), "good")
+		assert.NoError(mt, err)
+		assert.True(mt, result.Finished)
+
+		// A busca da sessão e a gravação da resposta usam o ID retornado no início
+		find := mt.GetStartedEvent()
+		assert.Equal(mt, session.ID, find.Command.Lookup("filter", "_id").ObjectID())
+		mt.GetStartedEvent() // card
+		update := mt.GetStartedEvent()
+		assert.Equal(mt, "update", update.CommandName)
+		assert.Equal(mt, session.ID, update.Command.Lookup("updates", "0", "q", "_id").ObjectID())
+	})
+}
+
+// toBSONDocument converte um valor no documento devolvido pelo mock
+func toBSONDocument(t *mtest.T, value interface{}) bson.D {
+	raw, err := bson.Marshal(value)
+	assert.NoError(t, err)
+	var doc bson.D
+	assert.NoError(t, bson.Unmarshal(raw, &doc))
+	return doc
+}
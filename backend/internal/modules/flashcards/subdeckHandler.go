@@ -0,0 +1,67 @@
+package flashcards
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MoveDeck move o deck e seus subdecks para baixo de outro deck
+// PUT /api/decks/:id/move
+func (h *Handler) MoveDeck(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	deckID := c.Param("id")
+	if deckID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Deck ID is required"})
+		return
+	}
+
+	var req struct {
+		ParentID string `json:"parent_id"` // vazio move para o primeiro nível
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	deck, err := h.service.MoveDeck(userID, deckID, req.ParentID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, deck)
+}
+
+// GetDueCards retorna os cards para revisão do deck e dos seus subdecks
+// GET /api/study/due?deck_id=...&limit=...
+func (h *Handler) GetDueCards(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	deckID := c.Query("deck_id")
+	if deckID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Deck ID is required"})
+		return
+	}
+
+	limit, err := strconv.ParseInt(c.DefaultQuery("limit", "0"), 10, 64)
+	if err != nil || limit < 0 {
+		limit = 0
+	}
+
+	cards, err := h.service.GetDueCards(userID, deckID, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cards)
+}
@@ -0,0 +1,105 @@
+package flashcards
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetDeckByUserAndName busca um deck pelo caminho completo. Retorna nil
+// quando o deck não existe.
+func (r *MongoRepository) GetDeckByUserAndName(userID, name string) (*entities.Deck, error) {
+	collection := r.db.GetCollection("decks")
+
+	var deck entities.Deck
+	err := collection.FindOne(context.Background(), bson.M{"userId": userID, "name": name}).Decode(&deck)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &deck, nil
+}
+
+// GetDescendantDecks retorna todos os subdecks (em qualquer nível) do caminho
+func (r *MongoRepository) GetDescendantDecks(userID, name string) ([]entities.Deck, error) {
+	collection := r.db.GetCollection("decks")
+	filter := bson.M{
+		"userId": userID,
+		"name":   bson.M{"$regex": "^" + regexp.QuoteMeta(name+DeckPathSeparator)},
+	}
+
+	cursor, err := collection.Find(context.Background(), filter)
+	if err != nil {
+		return []entities.Deck{}, err
+	}
+	defer cursor.Close(context.Background())
+
+	var decks []entities.Deck
+	if err = cursor.All(context.Background(), &decks); err != nil {
+		return []entities.Deck{}, err
+	}
+	if decks == nil {
+		decks = []entities.Deck{}
+	}
+	return decks, nil
+}
+
+// UpdateDeckPath grava o novo caminho e pai do deck
+func (r *MongoRepository) UpdateDeckPath(deckID primitive.ObjectID, name, parentID string) error {
+	update := bson.M{
+		"$set": bson.M{"name": name, "updatedAt": time.Now()},
+	}
+	if parentID != "" {
+		update["$set"].(bson.M)["parentId"] = parentID
+	} else {
+		update["$unset"] = bson.M{"parentId": ""}
+	}
+
+	collection := r.db.GetCollection("decks")
+	_, err := collection.UpdateOne(context.Background(), bson.M{"_id": deckID}, update)
+	return err
+}
+
+// GetDueFlashcards retorna os cards dos decks informados que estão vencidos
+// ou nunca foram revisados: primeiro os novos, depois os mais atrasados. Cards
+// suspensos ou presos numa sessão filtrada ficam de fora.
+func (r *MongoRepository) GetDueFlashcards(deckIDs []string, now time.Time, limit int64) ([]entities.Flashcard, error) {
+	filter := bson.M{
+		"deckId":          bson.M{"$in": deckIDs},
+		"suspended":       bson.M{"$ne": true},
+		"filteredSession": bson.M{"$exists": false},
+		"$or": []bson.M{
+			{"nextReview": bson.M{"$lte": now}},
+			{"nextReview": bson.M{"$exists": false}},
+			{"nextReview": nil},
+		},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "nextReview", Value: 1}, {Key: "createdAt", Value: 1}})
+	if limit > 0 {
+		opts.SetLimit(limit)
+	}
+
+	cursor, err := r.collection.Find(context.Background(), filter, opts)
+	if err != nil {
+		return []entities.Flashcard{}, err
+	}
+	defer cursor.Close(context.Background())
+
+	var cards []entities.Flashcard
+	if err = cursor.All(context.Background(), &cards); err != nil {
+		return []entities.Flashcard{}, err
+	}
+	if cards == nil {
+		cards = []entities.Flashcard{}
+	}
+	return cards, nil
+}
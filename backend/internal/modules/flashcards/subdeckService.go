@@ -0,0 +1,259 @@
+package flashcards
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+)
+
+// DeckPathSeparator separa os níveis no nome de um subdeck
+const DeckPathSeparator = "::"
+
+// normalizeDeckPath remove espaços em volta de cada nível e rejeita níveis vazios
+func normalizeDeckPath(name string) (string, error) {
+	parts := strings.Split(name, DeckPathSeparator)
+	for i, part := range parts {
+		parts[i] = strings.TrimSpace(part)
+		if parts[i] == "" {
+			return "", fmt.Errorf("invalid deck name: %q", name)
+		}
+	}
+	return strings.Join(parts, DeckPathSeparator), nil
+}
+
+// parentDeckPath retorna o caminho do deck pai ("" para decks de primeiro nível)
+func parentDeckPath(name string) string {
+	idx := strings.LastIndex(name, DeckPathSeparator)
+	if idx < 0 {
+		return ""
+	}
+	return name[:idx]
+}
+
+// deckLeafName retorna o último nível do caminho
+func deckLeafName(name string) string {
+	idx := strings.LastIndex(name, DeckPathSeparator)
+	if idx < 0 {
+		return name
+	}
+	return name[idx+len(DeckPathSeparator):]
+}
+
+// isDescendantPath indica se name está dentro da subárvore de ancestor
+func isDescendantPath(name, ancestor string) bool {
+	return strings.HasPrefix(name, ancestor+DeckPathSeparator)
+}
+
+// buildDeckTree monta a árvore de decks somando os cards dos descendentes.
+// Decks sem parentId mas com caminho no nome são ligados pelo nome; decks
+// cujo pai não está na lista viram raízes.
+func buildDeckTree(decks []entities.Deck) []*entities.DeckTreeNode {
+	nodes := make(map[string]*entities.DeckTreeNode, len(decks))
+	byName := make(map[string]*entities.DeckTreeNode, len(decks))
+	for _, deck := range decks {
+		node := &entities.DeckTreeNode{Deck: deck, Children: []*entities.DeckTreeNode{}}
+		nodes[deck.ID.Hex()] = node
+		if _, exists := byName[deck.Name]; !exists {
+			byName[deck.Name] = node
+		}
+	}
+
+	roots := []*entities.DeckTreeNode{}
+	for _, deck := range decks {
+		node := nodes[deck.ID.Hex()]
+		parent := nodes[deck.ParentID]
+		if parent == nil && deck.ParentID == "" {
+			parent = byName[parentDeckPath(deck.Name)]
+		}
+		if parent == nil || parent == node {
+			roots = append(roots, node)
+			continue
+		}
+		parent.Children = append(parent.Children, node)
+	}
+
+	var rollUp func(node *entities.DeckTreeNode) int
+	rollUp = func(node *entities.DeckTreeNode) int {
+		sort.Slice(node.Children, func(i, j int) bool {
+			return strings.ToLower(node.Children[i].Name) < strings.ToLower(node.Children[j].Name)
+		})
+		node.TotalCardCount = node.CardCount
+		for _, child := range node.Children {
+			node.TotalCardCount += rollUp(child)
+		}
+		return node.TotalCardCount
+	}
+	sort.Slice(roots, func(i, j int) bool {
+		return strings.ToLower(roots[i].Name) < strings.ToLower(roots[j].Name)
+	})
+	for _, root := range roots {
+		rollUp(root)
+	}
+
+	return roots
+}
+
+// deckParentsPlan diz o que falta para os pais de um caminho existirem
+type deckParentsPlan struct {
+	parentID string   // pai mais profundo que já existe ("" se nenhum)
+	missing  []string // caminhos dos pais a criar, do mais alto ao mais baixo
+}
+
+// planDeckParents procura os pais do caminho sem criar nada, para os limites
+// do plano serem verificados antes de qualquer escrita
+func (s *Service) planDeckParents(userID, name string) (*deckParentsPlan, error) {
+	plan := &deckParentsPlan{}
+	for path := parentDeckPath(name); path != ""; path = parentDeckPath(path) {
+		parent, err := s.repo.GetDeckByUserAndName(userID, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find parent deck: %w", err)
+		}
+		if parent != nil {
+			plan.parentID = parent.ID.Hex()
+			break
+		}
+		plan.missing = append([]string{path}, plan.missing...)
+	}
+	return plan, nil
+}
+
+// createDeckParents cria os pais que faltam e retorna o ID do pai direto
+func (s *Service) createDeckParents(userID string, plan *deckParentsPlan, isPublic bool) (string, error) {
+	parentID := plan.parentID
+	for _, path := range plan.missing {
+		parent := &entities.Deck{Name: path, ParentID: parentID, IsPublic: isPublic}
+		if err := s.repo.CreateDeckWithStringUserID(parent, userID); err != nil {
+			return "", fmt.Errorf("failed to create parent deck %s: %w", path, err)
+		}
+		parentID = parent.ID.Hex()
+	}
+	return parentID, nil
+}
+
+// resolveDeckParent garante que o pai do caminho exista, criando os decks
+// intermediários que faltarem se o plano comportar todos, e retorna o ID dele
+func (s *Service) resolveDeckParent(userID, name string, isPublic bool) (string, error) {
+	plan, err := s.planDeckParents(userID, name)
+	if err != nil {
+		return "", err
+	}
+	if len(plan.missing) > 0 {
+		if err := s.validateDeckCapacityFor(userID, isPublic, len(plan.missing)); err != nil {
+			return "", err
+		}
+	}
+	return s.createDeckParents(userID, plan, isPublic)
+}
+
+// renameDeckTree muda o caminho de um deck e atualiza o nome de todos os
+// seus subdecks. Mudar o caminho do pai equivale a mover o deck.
+func (s *Service) renameDeckTree(deck *entities.Deck, newName string) error {
+	newName, err := normalizeDeckPath(newName)
+	if err != nil {
+		return err
+	}
+	if newName == deck.Name {
+		return nil
+	}
+	if isDescendantPath(newName, deck.Name) {
+		return fmt.Errorf("cannot move a deck into its own subdeck")
+	}
+
+	existing, err := s.repo.GetDeckByUserAndName(deck.UserID, newName)
+	if err != nil {
+		return fmt.Errorf("failed to check deck name: %w", err)
+	}
+	if existing != nil && existing.ID != deck.ID {
+		return fmt.Errorf("a deck named %q already exists", newName)
+	}
+
+	parentID, err := s.resolveDeckParent(deck.UserID, newName, deck.IsPublic)
+	if err != nil {
+		return err
+	}
+
+	descendants, err := s.repo.GetDescendantDecks(deck.UserID, deck.Name)
+	if err != nil {
+		return fmt.Errorf("failed to get subdecks: %w", err)
+	}
+
+	if err := s.repo.UpdateDeckPath(deck.ID, newName, parentID); err != nil {
+		return fmt.Errorf("failed to rename deck: %w", err)
+	}
+	for _, descendant := range descendants {
+		descendantName := newName + strings.TrimPrefix(descendant.Name, deck.Name)
+		if err := s.repo.UpdateDeckPath(descendant.ID, descendantName, descendant.ParentID); err != nil {
+			return fmt.Errorf("failed to rename subdeck %s: %w", descendant.Name, err)
+		}
+	}
+
+	deck.Name = newName
+	deck.ParentID = parentID
+	return nil
+}
+
+// MoveDeck coloca o deck (com toda a subárvore) sob outro deck. parentID
+// vazio move o deck para o primeiro nível.
+func (s *Service) MoveDeck(userID, deckID, parentID string) (*entities.Deck, error) {
+	deck, err := s.getOwnedDeck(userID, deckID)
+	if err != nil {
+		return nil, err
+	}
+
+	newName := deckLeafName(deck.Name)
+	if parentID != "" {
+		parent, err := s.getOwnedDeck(userID, parentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.ID == deck.ID {
+			return nil, fmt.Errorf("cannot move a deck into itself")
+		}
+		newName = parent.Name + DeckPathSeparator + newName
+	}
+
+	if err := s.renameDeckTree(deck, newName); err != nil {
+		return nil, err
+	}
+	return deck, nil
+}
+
+// GetDeckTree retorna os decks do usuário em árvore
+func (s *Service) GetDeckTree(userID, visibility, search string) ([]*entities.DeckTreeNode, error) {
+	decks, err := s.GetDecksByUserIDWithFilter(userID, visibility, search)
+	if err != nil {
+		return nil, err
+	}
+	return buildDeckTree(decks), nil
+}
+
+// GetDueCards retorna os cards para revisão do deck e de todos os seus
+// subdecks. Em decks públicos de quem não é membro só entram subdecks
+// públicos. Quem não é dono revisa pelo próprio agendamento.
+func (s *Service) GetDueCards(userID, deckID string, limit int64) ([]entities.Flashcard, error) {
+	deck, err := s.getReadableDeck(userID, deckID)
+	if err != nil {
+		return nil, err
+	}
+
+	descendants, err := s.repo.GetDescendantDecks(deck.UserID, deck.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subdecks: %w", err)
+	}
+
+	deckIDs := []string{deck.ID.Hex()}
+	for _, descendant := range descendants {
+		if deck.Role == "" && !descendant.IsPublic {
+			continue
+		}
+		deckIDs = append(deckIDs, descendant.ID.Hex())
+	}
+
+	if deck.UserID != userID {
+		return s.getMemberDueCards(userID, deckIDs, time.Now(), limit)
+	}
+	return s.repo.GetDueFlashcards(deckIDs, time.Now(), limit)
+}
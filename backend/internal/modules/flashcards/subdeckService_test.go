@@ -0,0 +1,99 @@
+package flashcards
+
+import (
+	"testing"
+
+	"flashcard-backend/internal/domain/entities"
+	"flashcard-backend/internal/infrastructure/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestNormalizeDeckPath(t *testing.T) {
+	name, err := normalizeDeckPath(" Medicina :: Cardiologia::ECG ")
+	require.NoError(t, err)
+	assert.Equal(t, "Medicina::Cardiologia::ECG", name)
+	assert.Equal(t, "Medicina::Cardiologia", parentDeckPath(name))
+	assert.Equal(t, "ECG", deckLeafName(name))
+
+	_, err = normalizeDeckPath("Medicina::::ECG")
+	assert.Error(t, err)
+
+	assert.True(t, isDescendantPath("Medicina::Cardiologia", "Medicina"))
+	assert.False(t, isDescendantPath("Medicina 2", "Medicina"))
+}
+
+func TestBuildDeckTree(t *testing.T) {
+	root := entities.Deck{ID: primitive.NewObjectID(), Name: "Medicina", CardCount: 1}
+	child := entities.Deck{ID: primitive.NewObjectID(), Name: "Medicina::Cardiologia", ParentID: root.ID.Hex(), CardCount: 2}
+	// Deck antigo sem parentId é ligado pelo caminho no nome
+	grandchild := entities.Deck{ID: primitive.NewObjectID(), Name: "Medicina::Cardiologia::ECG", CardCount: 4}
+	other := entities.Deck{ID: primitive.NewObjectID(), Name: "Inglês", CardCount: 8}
+
+	tree := buildDeckTree([]entities.Deck{grandchild, other, child, root})
+
+	require.Len(t, tree, 2)
+	assert.Equal(t, "Inglês", tree[0].Name)
+	assert.Equal(t, 8, tree[0].TotalCardCount)
+
+	medicina := tree[1]
+	assert.Equal(t, 7, medicina.TotalCardCount)
+	require.Len(t, medicina.Children, 1)
+	assert.Equal(t, 6, medicina.Children[0].TotalCardCount)
+	require.Len(t, medicina.Children[0].Children, 1)
+	assert.Equal(t, grandchild.ID, medicina.Children[0].Children[0].ID)
+}
+
+func TestPlanDeckParentsDoesNotWrite(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("missing parents", func(mt *mtest.T) {
+		db := &database.MongoDB{Client: mt.Client, Database: mt.DB}
+		service := NewService(NewMongoRepository(db), nil, nil, nil, nil)
+
+		root := entities.Deck{ID: primitive.NewObjectID(), UserID: "user", Name: "Medicina"}
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "db.decks", mtest.FirstBatch), // Medicina::Cardiologia
+			mtest.CreateCursorResponse(0, "db.decks", mtest.FirstBatch, toBSONDocument(mt, root)),
+		)
+
+		plan, err := service.planDeckParents("user", "Medicina::Cardiologia::ECG")
+		require.NoError(mt, err)
+		assert.Equal(mt, root.ID.Hex(), plan.parentID)
+		assert.Equal(mt, []string{"Medicina::Cardiologia"}, plan.missing)
+
+		// Só buscas: nada é criado antes de os limites serem verificados
+		for started := mt.GetStartedEvent(); started != nil; started = mt.GetStartedEvent() {
+			assert.Equal(mt, "find", started.CommandName)
+		}
+	})
+}
+
+func TestReadableSubdecksKeepsParentsFirst(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("owner", func(mt *mtest.T) {
+		db := &database.MongoDB{Client: mt.Client, Database: mt.DB}
+		service := NewService(NewMongoRepository(db), nil, nil, nil, nil)
+
+		source := &entities.Deck{ID: primitive.NewObjectID(), UserID: "user", Name: "Medicina"}
+		grandchild := entities.Deck{ID: primitive.NewObjectID(), UserID: "user", Name: "Medicina::Cardiologia::ECG"}
+		// Deck antigo sem parentId
+		child := entities.Deck{ID: primitive.NewObjectID(), UserID: "user", Name: "Medicina::Cardiologia"}
+		// O pai "Medicina::Pediatria" não existe, então o subdeck fica de fora
+		orphan := entities.Deck{ID: primitive.NewObjectID(), UserID: "user", Name: "Medicina::Pediatria::Neonatologia"}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.decks", mtest.FirstBatch,
+			toBSONDocument(mt, grandchild), toBSONDocument(mt, orphan), toBSONDocument(mt, child)))
+
+		subdecks, err := service.readableSubdecks("user", source)
+		require.NoError(mt, err)
+		require.Len(mt, subdecks, 2)
+		assert.Equal(mt, child.ID, subdecks[0].ID)
+		assert.Equal(mt, grandchild.ID, subdecks[1].ID)
+	})
+}
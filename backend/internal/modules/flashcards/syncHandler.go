@@ -0,0 +1,51 @@
+package flashcards
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetUpstreamDiff lista os cards adicionados, alterados e removidos no deck
+// de origem de um fork
+// GET /api/decks/:id/upstream
+func (h *Handler) GetUpstreamDiff(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	diff, err := h.service.GetUpstreamDiff(userID, c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// SyncUpstream aplica ao fork todas ou algumas mudanças do deck de origem
+// POST /api/decks/:id/upstream/sync
+func (h *Handler) SyncUpstream(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		All           bool     `json:"all"`
+		SourceCardIDs []string `json:"source_card_ids"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.SyncUpstream(userID, c.Param("id"), req.All, req.SourceCardIDs)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,303 @@
+package flashcards
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tipos de mudança entre um fork e o deck de origem
+const (
+	UpstreamAdded   = "added"
+	UpstreamChanged = "changed"
+	UpstreamRemoved = "removed"
+)
+
+// UpstreamChange é uma mudança do deck de origem. Todas são identificadas
+// pelo ID do card de origem.
+type UpstreamChange struct {
+	Type          string                `json:"type"`
+	SourceCardID  string                `json:"source_card_id"`
+	CardID        string                `json:"card_id,omitempty"` // card local do fork
+	Upstream      *entities.CardContent `json:"upstream,omitempty"`
+	Local         *entities.CardContent `json:"local,omitempty"`
+	Fields        []string              `json:"fields,omitempty"`    // campos que o autor alterou
+	Conflicts     []string              `json:"conflicts,omitempty"` // campos também editados localmente
+	LocallyEdited bool                  `json:"locally_edited"`
+}
+
+// UpstreamDiff lista o que mudou no deck de origem desde a cópia
+type UpstreamDiff struct {
+	DeckID       string           `json:"deck_id"`
+	SourceDeckID string           `json:"source_deck_id"`
+	Added        int              `json:"added"`
+	Changed      int              `json:"changed"`
+	Removed      int              `json:"removed"`
+	Changes      []UpstreamChange `json:"changes"`
+}
+
+// UpstreamSyncResult resume a aplicação das mudanças
+type UpstreamSyncResult struct {
+	Added     int              `json:"added"`
+	Updated   int              `json:"updated"`
+	Removed   int              `json:"removed"`
+	Skipped   int              `json:"skipped"`
+	Conflicts []UpstreamChange `json:"conflicts,omitempty"` // campos em que a edição local foi mantida
+	Errors    []string         `json:"errors,omitempty"`
+}
+
+// upstreamState guarda o que foi carregado para calcular o diff e aplicá-lo
+type upstreamState struct {
+	fork     *entities.Deck
+	diff     *UpstreamDiff
+	local    map[string]entities.Flashcard // por ID do card de origem
+	upstream map[string]entities.Flashcard
+}
+
+// GetUpstreamDiff compara um fork do usuário com o deck de origem
+func (s *Service) GetUpstreamDiff(userID, deckID string) (*UpstreamDiff, error) {
+	state, err := s.loadUpstreamState(userID, deckID)
+	if err != nil {
+		return nil, err
+	}
+	return state.diff, nil
+}
+
+func (s *Service) loadUpstreamState(userID, deckID string) (*upstreamState, error) {
+	fork, err := s.getOwnedDeck(userID, deckID)
+	if err != nil {
+		return nil, err
+	}
+	if fork.ForkedFrom == "" {
+		return nil, fmt.Errorf("deck is not a fork")
+	}
+
+	source, err := s.getReadableDeck(userID, fork.ForkedFrom)
+	if err != nil {
+		return nil, fmt.Errorf("upstream deck is no longer available")
+	}
+
+	upstreamCards, err := s.repo.GetFlashcardsByDeckIDString(source.ID.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upstream cards: %w", err)
+	}
+	localCards, err := s.repo.GetFlashcardsByDeckIDString(fork.ID.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cards: %w", err)
+	}
+
+	state := &upstreamState{
+		fork:     fork,
+		local:    make(map[string]entities.Flashcard),
+		upstream: make(map[string]entities.Flashcard),
+		diff: &UpstreamDiff{
+			DeckID:       fork.ID.Hex(),
+			SourceDeckID: source.ID.Hex(),
+			Changes:      []UpstreamChange{},
+		},
+	}
+	for _, card := range localCards {
+		if card.Origin != nil && card.Origin.DeckID == source.ID.Hex() {
+			state.local[card.Origin.CardID] = card
+		}
+	}
+	// Cards que o dono apagou do fork não voltam como novos
+	deleted := make(map[string]bool, len(fork.DeletedOriginCards))
+	for _, sourceCardID := range fork.DeletedOriginCards {
+		deleted[sourceCardID] = true
+	}
+
+	for _, upstreamCard := range upstreamCards {
+		sourceCardID := upstreamCard.ID.Hex()
+		state.upstream[sourceCardID] = upstreamCard
+		upstreamContent := cardContent(&upstreamCard)
+
+		localCard, exists := state.local[sourceCardID]
+		if !exists && deleted[sourceCardID] {
+			continue
+		}
+		if !exists {
+			state.diff.Changes = append(state.diff.Changes, UpstreamChange{
+				Type:         UpstreamAdded,
+				SourceCardID: sourceCardID,
+				Upstream:     &upstreamContent,
+			})
+			state.diff.Added++
+			continue
+		}
+
+		localContent := cardContent(&localCard)
+		base := syncBase(&localCard, &upstreamCard)
+		fields := diffCardContent(base, upstreamContent)
+		if len(fields) == 0 {
+			continue
+		}
+		_, applied, conflicts := mergeCardContent(base, localContent, upstreamContent)
+		if len(applied) == 0 && len(conflicts) == 0 {
+			continue
+		}
+		state.diff.Changes = append(state.diff.Changes, UpstreamChange{
+			Type:          UpstreamChanged,
+			SourceCardID:  sourceCardID,
+			CardID:        localCard.ID.Hex(),
+			Upstream:      &upstreamContent,
+			Local:         &localContent,
+			Fields:        fields,
+			Conflicts:     conflicts,
+			LocallyEdited: len(diffCardContent(base, localContent)) > 0,
+		})
+		state.diff.Changed++
+	}
+
+	for _, localCard := range localCards {
+		if localCard.Origin == nil || state.local[localCard.Origin.CardID].ID != localCard.ID {
+			continue
+		}
+		sourceCardID := localCard.Origin.CardID
+		if _, exists := state.upstream[sourceCardID]; exists {
+			continue
+		}
+		localContent := cardContent(&localCard)
+		locallyEdited := localCard.Origin.Base != nil && len(diffCardContent(*localCard.Origin.Base, localContent)) > 0
+		state.diff.Changes = append(state.diff.Changes, UpstreamChange{
+			Type:          UpstreamRemoved,
+			SourceCardID:  sourceCardID,
+			CardID:        localCard.ID.Hex(),
+			Local:         &localContent,
+			LocallyEdited: locallyEdited,
+		})
+		state.diff.Removed++
+	}
+
+	return state, nil
+}
+
+// syncBase retorna o conteúdo de origem registrado na última sincronização.
+// Forks antigos não têm esse registro: se o card de origem foi alterado depois
+// do local, o conteúdo local é tratado como base (vale o upstream); senão o
+// upstream é a base e nada é considerado alterado pelo autor.
+func syncBase(local, upstream *entities.Flashcard) entities.CardContent {
+	if local.Origin != nil && local.Origin.Base != nil {
+		return *local.Origin.Base
+	}
+	if upstream.UpdatedAt.After(local.UpdatedAt) {
+		return cardContent(local)
+	}
+	return cardContent(upstream)
+}
+
+// SyncUpstream aplica as mudanças escolhidas (ou todas) ao fork. O estado de
+// revisão dos cards locais nunca é alterado e campos editados localmente são
+// mantidos. Com all, cards removidos na origem que foram editados
+// localmente são preservados; para removê-los é preciso escolhê-los.
+func (s *Service) SyncUpstream(userID, deckID string, all bool, sourceCardIDs []string) (*UpstreamSyncResult, error) {
+	state, err := s.loadUpstreamState(userID, deckID)
+	if err != nil {
+		return nil, err
+	}
+	if !all && len(sourceCardIDs) == 0 {
+		return nil, fmt.Errorf("select the changes to apply or use all")
+	}
+
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	selected := make(map[string]bool, len(sourceCardIDs))
+	for _, id := range sourceCardIDs {
+		selected[id] = true
+	}
+
+	var changes []UpstreamChange
+	added := 0
+	for _, change := range state.diff.Changes {
+		if all || selected[change.SourceCardID] {
+			changes = append(changes, change)
+			if change.Type == UpstreamAdded {
+				added++
+			}
+		}
+	}
+
+	forkID := state.fork.ID.Hex()
+	count, err := s.repo.CountCardsByDeckIDString(forkID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count cards: %w", err)
+	}
+	if err := s.validateCardCapacity(userID, int(count), added); err != nil {
+		return nil, err
+	}
+
+	result := &UpstreamSyncResult{}
+	author := s.revisionAuthorFor(userID)
+	now := time.Now()
+	for _, change := range changes {
+		switch change.Type {
+		case UpstreamAdded:
+			upstreamCard := state.upstream[change.SourceCardID]
+			card := &entities.Flashcard{
+				DeckID:     forkID,
+				UserID:     userObjectID,
+				Difficulty: upstreamCard.Difficulty,
+				Origin: &entities.CardOrigin{
+					DeckID:   state.diff.SourceDeckID,
+					CardID:   change.SourceCardID,
+					ForkedAt: now,
+					SyncedAt: &now,
+					Base:     change.Upstream,
+				},
+			}
+			applyCardContent(card, *change.Upstream)
+			if err := s.repo.Create(context.Background(), card); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("card %s: %v", change.SourceCardID, err))
+				continue
+			}
+			s.recordRevision(card, nil, author, entities.RevisionSync, "")
+			result.Added++
+
+		case UpstreamChanged:
+			card := state.local[change.SourceCardID]
+			upstreamCard := state.upstream[change.SourceCardID]
+			merged, applied, conflicts := mergeCardContent(syncBase(&card, &upstreamCard), *change.Local, *change.Upstream)
+			if len(applied) > 0 {
+				applyCardContent(&card, merged)
+				if err := s.repo.Update(context.Background(), &card); err != nil {
+					result.Errors = append(result.Errors, fmt.Sprintf("card %s: %v", change.SourceCardID, err))
+					continue
+				}
+				s.recordRevision(&card, change.Local, author, entities.RevisionSync, "")
+				result.Updated++
+			} else {
+				result.Skipped++
+			}
+			if len(conflicts) > 0 {
+				result.Conflicts = append(result.Conflicts, change)
+			}
+			// A origem atual passa a ser a base: conflitos resolvidos a favor
+			// da edição local não voltam na próxima comparação
+			if err := s.repo.SetCardOriginBase(card.ID, *change.Upstream, now); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("card %s: %v", change.SourceCardID, err))
+			}
+
+		case UpstreamRemoved:
+			if all && change.LocallyEdited {
+				result.Skipped++
+				continue
+			}
+			card := state.local[change.SourceCardID]
+			if err := s.moveCardToTrash(&card, now); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("card %s: %v", change.SourceCardID, err))
+				continue
+			}
+			result.Removed++
+		}
+	}
+
+	s.refreshDeckCardCounts(forkID)
+	return result, nil
+}
@@ -0,0 +1,57 @@
+package flashcards
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetTrash lista os decks e cards apagados do usuário
+// GET /api/trash
+func (h *Handler) GetTrash(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	items, err := h.service.GetTrash(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// RestoreTrashItem devolve um item da lixeira
+// POST /api/trash/:id/restore
+func (h *Handler) RestoreTrashItem(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	item, err := h.service.RestoreTrashItem(userID, c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// PurgeTrashItem apaga um item da lixeira definitivamente
+// DELETE /api/trash/:id
+func (h *Handler) PurgeTrashItem(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.PurgeTrashItem(userID, c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Item deleted permanently"})
+}
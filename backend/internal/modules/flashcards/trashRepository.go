@@ -0,0 +1,192 @@
+package flashcards
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// trashCollection indica de qual coleção veio cada tipo de item
+func trashCollection(itemType string) string {
+	if itemType == entities.TrashDeck {
+		return "decks"
+	}
+	return "cards"
+}
+
+// MoveToTrash copia o documento original para a lixeira e só então o remove
+// da coleção de origem
+func (r *MongoRepository) MoveToTrash(item *entities.TrashItem) error {
+	ctx := context.Background()
+	source := r.db.GetCollection(trashCollection(item.Type))
+
+	document, err := source.FindOne(ctx, bson.M{"_id": item.ItemID}).DecodeBytes()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", item.Type, err)
+	}
+	item.Document = document
+	if item.ID.IsZero() {
+		item.ID = primitive.NewObjectID()
+	}
+
+	if _, err := r.db.GetCollection("trash").InsertOne(ctx, item); err != nil {
+		return fmt.Errorf("failed to move %s to trash: %w", item.Type, err)
+	}
+
+	_, err = source.DeleteOne(ctx, bson.M{"_id": item.ItemID})
+	return err
+}
+
+// MoveDeckCardsToTrash move todos os cards dos decks para a lixeira, usando
+// template para os campos comuns, e retorna quantos foram movidos
+func (r *MongoRepository) MoveDeckCardsToTrash(deckIDs []string, template entities.TrashItem) (int, error) {
+	ctx := context.Background()
+	filter := bson.M{"deckId": bson.M{"$in": deckIDs}}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var documents []bson.Raw
+	if err = cursor.All(ctx, &documents); err != nil {
+		return 0, err
+	}
+	if len(documents) == 0 {
+		return 0, nil
+	}
+
+	items := make([]interface{}, 0, len(documents))
+	ids := make([]primitive.ObjectID, 0, len(documents))
+	for _, document := range documents {
+		item := template
+		item.ID = primitive.NewObjectID()
+		item.Type = entities.TrashCard
+		item.ItemID, _ = document.Lookup("_id").ObjectIDOK()
+		item.Name, _ = document.Lookup("question").StringValueOK()
+		item.DeckID, _ = document.Lookup("deckId").StringValueOK()
+		item.Document = document
+		items = append(items, item)
+		ids = append(ids, item.ItemID)
+	}
+
+	if _, err := r.db.GetCollection("trash").InsertMany(ctx, items); err != nil {
+		return 0, fmt.Errorf("failed to move cards to trash: %w", err)
+	}
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}}); err != nil {
+		return 0, err
+	}
+	return len(documents), nil
+}
+
+// RestoreFromTrash devolve o documento original à sua coleção e remove o item
+// da lixeira. A data de alteração é renovada para a sincronização offline
+// enviar o item de volta, e um card apagado durante uma sessão filtrada volta
+// livre dela.
+func (r *MongoRepository) RestoreFromTrash(item *entities.TrashItem) error {
+	ctx := context.Background()
+	collection := r.db.GetCollection(trashCollection(item.Type))
+	if _, err := collection.InsertOne(ctx, item.Document); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", item.Type, err)
+	}
+	update := bson.M{"$set": bson.M{"updatedAt": time.Now()}}
+	if item.Type == entities.TrashCard {
+		update["$unset"] = bson.M{"filteredSession": ""}
+	}
+	if _, err := collection.UpdateOne(ctx, bson.M{"_id": item.ItemID}, update); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", item.Type, err)
+	}
+	return r.DeleteTrashItem(item.ID)
+}
+
+func (r *MongoRepository) GetTrashItem(itemID primitive.ObjectID) (*entities.TrashItem, error) {
+	var item entities.TrashItem
+	err := r.db.GetCollection("trash").FindOne(context.Background(), bson.M{"_id": itemID}).Decode(&item)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// GetTrashItems lista o que o usuário apagou diretamente; itens apagados
+// junto com um deck ficam dentro dele
+func (r *MongoRepository) GetTrashItems(userID string) ([]entities.TrashItem, error) {
+	filter := bson.M{"user_id": userID, "deleted_with": bson.M{"$exists": false}}
+	return r.findTrashItems(filter, options.Find().SetSort(bson.M{"deleted_at": -1}))
+}
+
+// GetTrashItemsDeletedWith retorna os subdecks e cards apagados junto com um deck
+func (r *MongoRepository) GetTrashItemsDeletedWith(itemID primitive.ObjectID) ([]entities.TrashItem, error) {
+	return r.findTrashItems(bson.M{"deleted_with": itemID.Hex()}, options.Find().SetSort(bson.M{"deleted_at": 1}))
+}
+
+// GetExpiredTrashItems retorna itens de primeiro nível cujo prazo já passou,
+// fora os de skip (que já falharam nesta execução)
+func (r *MongoRepository) GetExpiredTrashItems(now time.Time, skip []primitive.ObjectID, limit int64) ([]entities.TrashItem, error) {
+	filter := bson.M{"expires_at": bson.M{"$lte": now}, "deleted_with": bson.M{"$exists": false}}
+	if len(skip) > 0 {
+		filter["_id"] = bson.M{"$nin": skip}
+	}
+	return r.findTrashItems(filter, options.Find().SetLimit(limit))
+}
+
+func (r *MongoRepository) findTrashItems(filter bson.M, opts ...*options.FindOptions) ([]entities.TrashItem, error) {
+	cursor, err := r.db.GetCollection("trash").Find(context.Background(), filter, opts...)
+	if err != nil {
+		return []entities.TrashItem{}, err
+	}
+	defer cursor.Close(context.Background())
+
+	var items []entities.TrashItem
+	if err = cursor.All(context.Background(), &items); err != nil {
+		return []entities.TrashItem{}, err
+	}
+	if items == nil {
+		items = []entities.TrashItem{}
+	}
+	return items, nil
+}
+
+func (r *MongoRepository) DeleteTrashItem(itemID primitive.ObjectID) error {
+	_, err := r.db.GetCollection("trash").DeleteOne(context.Background(), bson.M{"_id": itemID})
+	return err
+}
+
+func (r *MongoRepository) DeleteFavoritesByDeck(deckID primitive.ObjectID) error {
+	_, err := r.db.GetCollection("favorites").DeleteMany(context.Background(), bson.M{"deck_id": deckID})
+	return err
+}
+
+func (r *MongoRepository) DeleteCardRevisions(cardID string) error {
+	_, err := r.db.GetCollection("card_revisions").DeleteMany(context.Background(), bson.M{"card_id": cardID})
+	return err
+}
+
+// CountMediaReferences conta cards (ativos ou na lixeira) que ainda usam a
+// mídia; forks compartilham as mesmas URLs
+func (r *MongoRepository) CountMediaReferences(url string) (int64, error) {
+	ctx := context.Background()
+	active, err := r.collection.CountDocuments(ctx, bson.M{"$or": []bson.M{{"imageUrl": url}, {"audioUrl": url}}})
+	if err != nil {
+		return 0, err
+	}
+	trashed, err := r.db.GetCollection("trash").CountDocuments(ctx, bson.M{
+		"type": entities.TrashCard,
+		"$or":  []bson.M{{"document.imageUrl": url}, {"document.audioUrl": url}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return active + trashed, nil
+}
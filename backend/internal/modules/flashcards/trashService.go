@@ -0,0 +1,404 @@
+package flashcards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrTrashItemNotFound = errors.New("trash item not found")
+
+const (
+	defaultTrashRetentionDays = 30
+	defaultTrashPurgeInterval = 60 * time.Minute
+	trashPurgeBatch           = 100
+)
+
+// trashExpiry calcula até quando um item apagado agora fica na lixeira
+func (s *Service) trashExpiry(deletedAt time.Time) time.Time {
+	days := defaultTrashRetentionDays
+	if s.cfg != nil && s.cfg.Trash.RetentionDays > 0 {
+		days = s.cfg.Trash.RetentionDays
+	}
+	return deletedAt.AddDate(0, 0, days)
+}
+
+// moveDeckToTrash apaga o deck junto com subdecks e cards. Só o deck aparece
+// na lixeira; o resto fica ligado a ele e volta na restauração.
+func (s *Service) moveDeckToTrash(deck *entities.Deck) error {
+	descendants, err := s.repo.GetDescendantDecks(deck.UserID, deck.Name)
+	if err != nil {
+		return fmt.Errorf("failed to get subdecks: %w", err)
+	}
+
+	deckIDs := []string{deck.ID.Hex()}
+	for _, descendant := range descendants {
+		deckIDs = append(deckIDs, descendant.ID.Hex())
+	}
+	cardCount := 0
+	for _, deckID := range deckIDs {
+		count, err := s.repo.CountCardsByDeckIDString(deckID)
+		if err != nil {
+			return fmt.Errorf("failed to count cards: %w", err)
+		}
+		cardCount += int(count)
+	}
+
+	now := time.Now()
+	root := &entities.TrashItem{
+		UserID:       deck.UserID,
+		Type:         entities.TrashDeck,
+		ItemID:       deck.ID,
+		Name:         deck.Name,
+		CardCount:    cardCount,
+		SubdeckCount: len(descendants),
+		DeletedAt:    now,
+		ExpiresAt:    s.trashExpiry(now),
+	}
+	if err := s.repo.MoveToTrash(root); err != nil {
+		return fmt.Errorf("failed to delete deck: %w", err)
+	}
+
+	for _, descendant := range descendants {
+		item := &entities.TrashItem{
+			UserID:      deck.UserID,
+			Type:        entities.TrashDeck,
+			ItemID:      descendant.ID,
+			Name:        descendant.Name,
+			DeletedWith: root.ID.Hex(),
+			DeletedAt:   now,
+			ExpiresAt:   root.ExpiresAt,
+		}
+		if err := s.repo.MoveToTrash(item); err != nil {
+			return fmt.Errorf("failed to delete subdeck %s: %w", descendant.Name, err)
+		}
+	}
+
+	_, err = s.repo.MoveDeckCardsToTrash(deckIDs, entities.TrashItem{
+		UserID:      deck.UserID,
+		DeletedWith: root.ID.Hex(),
+		DeletedAt:   now,
+		ExpiresAt:   root.ExpiresAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete cards: %w", err)
+	}
+
+	// Membros dos decks apagados perdem o acesso; a sincronização avisa
+	for _, deckID := range deckIDs {
+		members, err := s.repo.GetDeckMembers(deckID)
+		if err == nil {
+			err = s.repo.RecordDeckAccessLost(members, now, root.ExpiresAt)
+		}
+		if err != nil {
+			fmt.Printf("Failed to record lost access to deck %s: %v\n", deckID, err)
+		}
+	}
+	return nil
+}
+
+// moveCardToTrash apaga um card avulso. A lixeira é do dono do deck, que
+// pode não ser quem criou o card.
+func (s *Service) moveCardToTrash(card *entities.Flashcard, now time.Time) error {
+	ownerID := card.UserID.Hex()
+	if deck, err := s.getDeck(card.DeckID); err == nil {
+		ownerID = deck.UserID
+	}
+
+	item := &entities.TrashItem{
+		UserID:    ownerID,
+		Type:      entities.TrashCard,
+		ItemID:    card.ID,
+		Name:      card.Question,
+		DeckID:    card.DeckID,
+		DeletedAt: now,
+		ExpiresAt: s.trashExpiry(now),
+	}
+	if err := s.repo.MoveToTrash(item); err != nil {
+		return fmt.Errorf("failed to delete card: %w", err)
+	}
+	// Cópia de um card de origem: a sincronização do fork não deve trazê-lo
+	// de volta
+	if card.Origin != nil {
+		if err := s.repo.SetOriginCardDeleted(card.DeckID, card.Origin.CardID, true); err != nil {
+			fmt.Printf("Failed to record deleted origin card %s: %v\n", card.Origin.CardID, err)
+		}
+	}
+	return nil
+}
+
+// GetTrash lista os itens da lixeira do usuário, do mais recente ao mais antigo
+func (s *Service) GetTrash(userID string) ([]entities.TrashItem, error) {
+	return s.repo.GetTrashItems(userID)
+}
+
+// getTrashItem retorna um item de primeiro nível da lixeira do usuário
+func (s *Service) getTrashItem(userID, itemID string) (*entities.TrashItem, error) {
+	objectID, err := primitive.ObjectIDFromHex(itemID)
+	if err != nil {
+		return nil, ErrTrashItemNotFound
+	}
+	item, err := s.repo.GetTrashItem(objectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trash item: %w", err)
+	}
+	if item == nil || item.UserID != userID || item.DeletedWith != "" {
+		return nil, ErrTrashItemNotFound
+	}
+	return item, nil
+}
+
+// RestoreTrashItem devolve o item ao lugar de onde foi apagado
+func (s *Service) RestoreTrashItem(userID, itemID string) (*entities.TrashItem, error) {
+	item, err := s.getTrashItem(userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if item.Type == entities.TrashDeck {
+		err = s.restoreDeck(userID, item)
+	} else {
+		err = s.restoreCard(userID, item)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (s *Service) restoreCard(userID string, item *entities.TrashItem) error {
+	if _, err := s.getDeck(item.DeckID); err != nil {
+		return fmt.Errorf("the card's deck no longer exists; restore the deck first")
+	}
+
+	count, err := s.repo.CountCardsByDeckIDString(item.DeckID)
+	if err != nil {
+		return fmt.Errorf("failed to count cards: %w", err)
+	}
+	if err := s.validateCardCapacity(userID, int(count), 1); err != nil {
+		return err
+	}
+
+	if err := s.repo.RestoreFromTrash(item); err != nil {
+		return err
+	}
+	var card entities.Flashcard
+	if err := bson.Unmarshal(item.Document, &card); err == nil && card.Origin != nil {
+		if err := s.repo.SetOriginCardDeleted(item.DeckID, card.Origin.CardID, false); err != nil {
+			fmt.Printf("Failed to clear deleted origin card %s: %v\n", card.Origin.CardID, err)
+		}
+	}
+	s.refreshDeckCardCounts(item.DeckID)
+	return nil
+}
+
+// restoreDeck restaura o deck com os subdecks e cards apagados junto. Se o
+// deck pai não existe mais, o deck volta para o primeiro nível; se o pai foi
+// renomeado, o caminho acompanha o novo nome.
+func (s *Service) restoreDeck(userID string, item *entities.TrashItem) error {
+	var deck entities.Deck
+	if err := bson.Unmarshal(item.Document, &deck); err != nil {
+		return fmt.Errorf("failed to read deleted deck: %w", err)
+	}
+
+	name := deck.Name
+	if deck.ParentID != "" {
+		if parent, err := s.getDeck(deck.ParentID); err != nil {
+			name = deckLeafName(deck.Name)
+		} else {
+			name = parent.Name + DeckPathSeparator + deckLeafName(deck.Name)
+		}
+	}
+	existing, err := s.repo.GetDeckByUserAndName(userID, name)
+	if err != nil {
+		return fmt.Errorf("failed to check deck name: %w", err)
+	}
+	if existing != nil {
+		return fmt.Errorf("a deck named %q already exists", name)
+	}
+
+	// Dono suspenso pela moderação recebe os decks de volta como privados
+	suspended := s.validatePublishing(userID, true) != nil
+	if err := s.validateDeckCapacity(userID, deck.IsPublic && !suspended); err != nil {
+		return err
+	}
+
+	children, err := s.repo.GetTrashItemsDeletedWith(item.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get deleted subdecks: %w", err)
+	}
+	if err := s.repo.RestoreFromTrash(item); err != nil {
+		return err
+	}
+
+	// Subdecks antes dos cards; falhas daqui em diante não desfazem a restauração
+	deckIDs := []string{deck.ID.Hex()}
+	for _, itemType := range []string{entities.TrashDeck, entities.TrashCard} {
+		for i := range children {
+			child := &children[i]
+			if child.Type != itemType {
+				continue
+			}
+			if err := s.repo.RestoreFromTrash(child); err != nil {
+				fmt.Printf("Failed to restore %s %s: %v\n", child.Type, child.ItemID.Hex(), err)
+				continue
+			}
+			if child.Type == entities.TrashDeck {
+				deckIDs = append(deckIDs, child.ItemID.Hex())
+			}
+		}
+	}
+
+	if suspended {
+		if err := s.repo.UnpublishDecks(deckIDs); err != nil {
+			return fmt.Errorf("failed to unpublish restored decks: %w", err)
+		}
+	}
+	if name != deck.Name {
+		if err := s.renameDeckTree(&deck, name); err != nil {
+			return err
+		}
+	}
+	s.refreshDeckCardCounts(deckIDs...)
+	return nil
+}
+
+// PurgeTrashItem apaga definitivamente um item da lixeira
+func (s *Service) PurgeTrashItem(userID, itemID string) error {
+	item, err := s.getTrashItem(userID, itemID)
+	if err != nil {
+		return err
+	}
+	return s.purgeTrashItem(item)
+}
+
+// purgeTrashItem remove o item e o que depende dele: favoritos e membros do
+// deck, histórico e agendamentos do card e mídias que nenhum outro card usa
+func (s *Service) purgeTrashItem(item *entities.TrashItem) error {
+	if item.Type == entities.TrashDeck {
+		children, err := s.repo.GetTrashItemsDeletedWith(item.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get deleted subdecks: %w", err)
+		}
+		for i := range children {
+			if err := s.purgeTrashItem(&children[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := s.repo.DeleteTrashItem(item.ID); err != nil {
+		return fmt.Errorf("failed to purge %s: %w", item.Type, err)
+	}
+
+	switch item.Type {
+	case entities.TrashDeck:
+		if err := s.repo.DeleteFavoritesByDeck(item.ItemID); err != nil {
+			fmt.Printf("Failed to delete favorites of deck %s: %v\n", item.ItemID.Hex(), err)
+		}
+		if err := s.repo.DeleteDeckMembers(item.ItemID.Hex()); err != nil {
+			fmt.Printf("Failed to delete members of deck %s: %v\n", item.ItemID.Hex(), err)
+		}
+		if err := s.repo.DeleteDeckRatings(item.ItemID.Hex()); err != nil {
+			fmt.Printf("Failed to delete ratings of deck %s: %v\n", item.ItemID.Hex(), err)
+		}
+		if err := s.repo.DeleteShareLinks(item.ItemID.Hex()); err != nil {
+			fmt.Printf("Failed to delete share links of deck %s: %v\n", item.ItemID.Hex(), err)
+		}
+	case entities.TrashCard:
+		if err := s.repo.DeleteCardRevisions(item.ItemID.Hex()); err != nil {
+			fmt.Printf("Failed to delete revisions of card %s: %v\n", item.ItemID.Hex(), err)
+		}
+		if err := s.repo.DeleteCardSchedules(item.ItemID.Hex()); err != nil {
+			fmt.Printf("Failed to delete schedules of card %s: %v\n", item.ItemID.Hex(), err)
+		}
+		s.deleteUnusedMedia(item.Document)
+	}
+	return nil
+}
+
+// deleteUnusedMedia apaga do storage as mídias do card que não são mais
+// referenciadas por nenhum outro card
+func (s *Service) deleteUnusedMedia(document bson.Raw) {
+	if s.media == nil || !s.media.IsConfigured() {
+		return
+	}
+	for _, field := range []string{"imageUrl", "audioUrl"} {
+		url, ok := document.Lookup(field).StringValueOK()
+		if !ok || url == "" {
+			continue
+		}
+		count, err := s.repo.CountMediaReferences(url)
+		if err != nil || count > 0 {
+			continue
+		}
+		if err := s.media.DeleteFile(url); err != nil {
+			fmt.Printf("Failed to delete media %s: %v\n", url, err)
+		}
+	}
+}
+
+// PurgeExpiredTrash apaga os itens cujo prazo na lixeira terminou. Um item
+// que falha é registrado e fica para a próxima execução sem impedir os
+// demais; o erro retornado junta todas as falhas.
+func (s *Service) PurgeExpiredTrash(now time.Time) (int, error) {
+	purged := 0
+	var failed []primitive.ObjectID
+	var errs []error
+	for {
+		items, err := s.repo.GetExpiredTrashItems(now, failed, trashPurgeBatch)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get expired trash: %w", err))
+			break
+		}
+		if len(items) == 0 {
+			break
+		}
+		for i := range items {
+			if err := s.purgeTrashItem(&items[i]); err != nil {
+				fmt.Printf("Failed to purge %s %s: %v\n", items[i].Type, items[i].ItemID.Hex(), err)
+				failed = append(failed, items[i].ID)
+				errs = append(errs, err)
+				continue
+			}
+			purged++
+		}
+	}
+	if len(failed) > 0 {
+		return purged, fmt.Errorf("failed to purge %d trash items: %w", len(failed), errors.Join(errs...))
+	}
+	return purged, errors.Join(errs...)
+}
+
+// StartTrashPurge esvazia periodicamente a lixeira até o contexto ser cancelado
+func (s *Service) StartTrashPurge(ctx context.Context) {
+	interval := defaultTrashPurgeInterval
+	if s.cfg != nil && s.cfg.Trash.PurgeIntervalMinutes > 0 {
+		interval = time.Duration(s.cfg.Trash.PurgeIntervalMinutes) * time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.PurgeExpiredTrash(time.Now())
+		if purged > 0 {
+			fmt.Printf("Purged %d expired trash items\n", purged)
+		}
+		if err != nil {
+			fmt.Printf("Failed to purge trash: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
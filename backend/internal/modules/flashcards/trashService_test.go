@@ -0,0 +1,62 @@
+package flashcards
+
+import (
+	"testing"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+	"flashcard-backend/internal/infrastructure/database"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestPurgeExpiredTrashContinuesAfterFailure(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("one item fails", func(mt *mtest.T) {
+		db := &database.MongoDB{Client: mt.Client, Database: mt.DB}
+		service := NewService(NewMongoRepository(db), nil, nil, nil, nil)
+
+		now := time.Now()
+		item := func() entities.TrashItem {
+			return entities.TrashItem{
+				ID:        primitive.NewObjectID(),
+				Type:      entities.TrashCard,
+				ItemID:    primitive.NewObjectID(),
+				Document:  bson.Raw{5, 0, 0, 0, 0},
+				ExpiresAt: now.Add(-time.Hour),
+			}
+		}
+		broken, ok := item(), item()
+
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "db.trash", mtest.FirstBatch, toBSONDocument(mt, broken), toBSONDocument(mt, ok)),
+			mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 1, Message: "delete failed"}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}), // item
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}), // revisões
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}), // agendamentos
+			mtest.CreateCursorResponse(0, "db.trash", mtest.FirstBatch),
+		)
+
+		purged, err := service.PurgeExpiredTrash(now)
+		assert.Equal(mt, 1, purged)
+		assert.ErrorContains(mt, err, "failed to purge 1 trash items")
+
+		// A segunda busca pula o item que falhou em vez de repeti-lo
+		var last *event.CommandStartedEvent
+		for started := mt.GetStartedEvent(); started != nil; started = mt.GetStartedEvent() {
+			if started.CommandName == "find" {
+				last = started
+			}
+		}
+		if assert.NotNil(mt, last) {
+			skipped := last.Command.Lookup("filter", "_id", "$nin").Array().Index(0).Value().ObjectID()
+			assert.Equal(mt, broken.ID, skipped)
+		}
+	})
+}
@@ -133,19 +133,22 @@ func (r *AchievementRepository) UpdateProgress(userID, name string, progress int
 func (r *AchievementRepository) InitializeUserAchievements(userID string) error {
 	collection := r.db.Database.Collection("achievements")
 
-	// Verificar se o usuário já tem conquistas
-	count, err := collection.CountDocuments(context.Background(), bson.M{"user_id": userID})
+	// Conquistas que o usuário já tem, para só criar as dos templates novos
+	existing, err := r.GetUserAchievements(userID)
 	if err != nil {
 		return err
 	}
-
-	if count > 0 {
-		return nil // Já tem conquistas
+	names := make(map[string]bool, len(existing))
+	for _, achievement := range existing {
+		names[achievement.Name] = true
 	}
 
 	// Criar conquistas baseadas nos templates
 	var achievements []interface{}
 	for _, template := range entities.AchievementTemplates {
+		if names[template.Name] {
+			continue
+		}
 		achievement := entities.Achievement{
 			UserID:      userID,
 			Name:        template.Name,
@@ -99,6 +99,8 @@ func (s *AchievementService) checkAchievementCondition(userID string, achievemen
 		currentValue, err = s.getSessionAccuracy(userID)
 	case "cards_per_session":
 		currentValue, err = s.getCardsPerSession(userID)
+	case "goal_days":
+		currentValue, err = s.getGoalDaysMet(userID)
 	default:
 		return false, 0, fmt.Errorf("unknown condition type: %s", condition.Type)
 	}
@@ -182,6 +184,22 @@ func (s *AchievementService) getCardsPerSession(userID string) (int, error) {
 	return stats[0].CardsReviewed, nil
 }
 
+// getGoalDaysMet conta os dias em que o usuário cumpriu a meta diária
+func (s *AchievementService) getGoalDaysMet(userID string) (int, error) {
+	ctx := context.Background()
+	days, err := s.statsRepo.getGoalDays(ctx, userID, s.statsRepo.userLocation(ctx, userID))
+	if err != nil {
+		return 0, err
+	}
+	met := 0
+	for _, day := range days {
+		if day.Met {
+			met++
+		}
+	}
+	return met, nil
+}
+
 // Método para verificar conquistas após ações específicas
 func (s *AchievementService) CheckAchievementsAfterAction(userID, actionType string, actionData map[string]interface{}) ([]entities.Achievement, error) {
 	// Primeiro, verificar conquistas baseadas em estatísticas gerais
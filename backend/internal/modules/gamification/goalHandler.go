@@ -0,0 +1,131 @@
+package gamification
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GoalHandler struct {
+	goalService *GoalService
+}
+
+func NewGoalHandler(goalService *GoalService) *GoalHandler {
+	return &GoalHandler{
+		goalService: goalService,
+	}
+}
+
+// GetDailyGoal retorna a meta diária em vigor
+func (h *GoalHandler) GetDailyGoal(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	goal, err := h.goalService.GetDailyGoal(c.Request.Context(), userID)
+	if err != nil {
+		respondGoalError(c, err, "Failed to get daily goal")
+		return
+	}
+
+	c.JSON(http.StatusOK, goal)
+}
+
+// SetDailyGoal define a meta diária a partir de hoje
+func (h *GoalHandler) SetDailyGoal(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	var req struct {
+		Type   string `json:"type" binding:"required"` // minutes, cards_reviewed ou new_cards
+		Target int    `json:"target" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	goal, err := h.goalService.SetDailyGoal(c.Request.Context(), userID, req.Type, req.Target)
+	if err != nil {
+		respondGoalError(c, err, "Failed to set daily goal")
+		return
+	}
+
+	c.JSON(http.StatusOK, goal)
+}
+
+// DisableDailyGoal desliga a meta diária a partir de hoje
+func (h *GoalHandler) DisableDailyGoal(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	if err := h.goalService.DisableDailyGoal(c.Request.Context(), userID); err != nil {
+		respondGoalError(c, err, "Failed to disable daily goal")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Daily goal disabled successfully"})
+}
+
+// GetTodayGoal retorna o progresso da meta hoje
+func (h *GoalHandler) GetTodayGoal(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	today, err := h.goalService.GetTodayGoal(c.Request.Context(), userID)
+	if err != nil {
+		respondGoalError(c, err, "Failed to get goal progress")
+		return
+	}
+
+	c.JSON(http.StatusOK, today)
+}
+
+// GetGoalHistory retorna o histórico diário da meta
+func (h *GoalHandler) GetGoalHistory(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found"})
+		return
+	}
+
+	days := defaultGoalHistoryDays
+	if daysStr := c.Query("days"); daysStr != "" {
+		if d, err := strconv.Atoi(daysStr); err == nil {
+			days = d
+		}
+	}
+
+	history, err := h.goalService.GetGoalHistory(c.Request.Context(), userID, days)
+	if err != nil {
+		respondGoalError(c, err, "Failed to get goal history")
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// respondGoalError traduz os erros de meta em status HTTP
+func respondGoalError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, ErrNoDailyGoal):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrInvalidGoal):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
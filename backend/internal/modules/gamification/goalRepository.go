@@ -0,0 +1,168 @@
+package gamification
+
+import (
+	"context"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// dailyActivity é o que o usuário estudou em um dia
+type dailyActivity struct {
+	Seconds  int
+	Reviews  int
+	NewCards int
+}
+
+// SaveDailyGoal grava a versão da meta que vale a partir de goal.EffectiveFrom,
+// substituindo uma mudança anterior feita no mesmo dia
+func (r *StatsRepository) SaveDailyGoal(ctx context.Context, goal *entities.DailyGoal) error {
+	goal.UpdatedAt = time.Now()
+
+	collection := r.db.GetCollection("daily_goals")
+	_, err := collection.UpdateOne(ctx,
+		bson.M{"user_id": goal.UserID, "effective_from": goal.EffectiveFrom},
+		bson.M{"$set": bson.M{
+			"type":       goal.Type,
+			"target":     goal.Target,
+			"updated_at": goal.UpdatedAt,
+		}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// GetDailyGoals retorna as versões da meta do usuário, da mais antiga para a
+// mais nova
+func (r *StatsRepository) GetDailyGoals(ctx context.Context, userID string) ([]entities.DailyGoal, error) {
+	collection := r.db.GetCollection("daily_goals")
+
+	opts := options.Find().SetSort(bson.M{"effective_from": 1})
+	cursor, err := collection.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var goals []entities.DailyGoal
+	if err = cursor.All(ctx, &goals); err != nil {
+		return nil, err
+	}
+	return goals, nil
+}
+
+// GetDailyActivity soma as revisões do usuário entre start e end por dia
+// (YYYY-MM-DD) no fuso loc. Um card conta como novo no dia da sua primeira
+// revisão.
+func (r *StatsRepository) GetDailyActivity(ctx context.Context, userID string, start, end time.Time, loc *time.Location) (map[string]*dailyActivity, error) {
+	collection := r.db.GetCollection("study_stats")
+	localDay := bson.M{"$dateToString": bson.M{
+		"format":   "%Y-%m-%d",
+		"date":     "$created_at",
+		"timezone": loc.String(),
+	}}
+	activity := make(map[string]*dailyActivity)
+	day := func(date string) *dailyActivity {
+		if activity[date] == nil {
+			activity[date] = &dailyActivity{}
+		}
+		return activity[date]
+	}
+
+	reviewsPipeline := []bson.M{
+		{"$match": bson.M{
+			"user_id":     userID,
+			"action_type": "card_review",
+			"created_at":  bson.M{"$gte": start, "$lt": end},
+		}},
+		{"$group": bson.M{
+			"_id":     localDay,
+			"reviews": bson.M{"$sum": 1},
+			"seconds": bson.M{"$sum": "$study_time"},
+		}},
+	}
+	cursor, err := collection.Aggregate(ctx, reviewsPipeline)
+	if err != nil {
+		return nil, err
+	}
+	var reviews []struct {
+		Date    string `bson:"_id"`
+		Reviews int    `bson:"reviews"`
+		Seconds int    `bson:"seconds"`
+	}
+	err = cursor.All(ctx, &reviews)
+	cursor.Close(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range reviews {
+		day(result.Date).Reviews = result.Reviews
+		day(result.Date).Seconds = result.Seconds
+	}
+
+	// A primeira revisão de cada card pode ser anterior ao intervalo, então o
+	// agrupamento por card percorre todo o histórico do usuário
+	newCardsPipeline := []bson.M{
+		{"$match": bson.M{
+			"user_id":     userID,
+			"action_type": "card_review",
+			"card_id":     bson.M{"$exists": true, "$ne": ""},
+			"created_at":  bson.M{"$lt": end},
+		}},
+		{"$group": bson.M{
+			"_id":   "$card_id",
+			"first": bson.M{"$min": "$created_at"},
+		}},
+		{"$match": bson.M{"first": bson.M{"$gte": start}}},
+		{"$group": bson.M{
+			"_id": bson.M{"$dateToString": bson.M{
+				"format":   "%Y-%m-%d",
+				"date":     "$first",
+				"timezone": loc.String(),
+			}},
+			"new_cards": bson.M{"$sum": 1},
+		}},
+	}
+	cursor, err = collection.Aggregate(ctx, newCardsPipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var newCards []struct {
+		Date     string `bson:"_id"`
+		NewCards int    `bson:"new_cards"`
+	}
+	if err = cursor.All(ctx, &newCards); err != nil {
+		return nil, err
+	}
+	for _, result := range newCards {
+		day(result.Date).NewCards = result.NewCards
+	}
+
+	return activity, nil
+}
+
+// getGoalDays retorna o progresso diário da meta desde a primeira versão dela
+// até hoje, no fuso loc. Sem meta definida, retorna nil.
+func (r *StatsRepository) getGoalDays(ctx context.Context, userID string, loc *time.Location) ([]entities.GoalDay, error) {
+	goals, err := r.GetDailyGoals(ctx, userID)
+	if err != nil || len(goals) == 0 {
+		return nil, err
+	}
+	from, err := time.ParseInLocation(dateLayout, goals[0].EffectiveFrom, loc)
+	if err != nil {
+		return nil, err
+	}
+	to := localMidnight(time.Now(), loc)
+	if from.After(to) {
+		from = to
+	}
+	activity, err := r.GetDailyActivity(ctx, userID, from, to.AddDate(0, 0, 1), loc)
+	if err != nil {
+		return nil, err
+	}
+	return buildGoalDays(goals, activity, from, to), nil
+}
@@ -0,0 +1,231 @@
+package gamification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+)
+
+var (
+	ErrNoDailyGoal = errors.New("no daily goal set")
+	ErrInvalidGoal = errors.New("invalid daily goal")
+)
+
+const dateLayout = "2006-01-02"
+
+// Limites do histórico da meta
+const (
+	defaultGoalHistoryDays = 365
+	maxGoalHistoryDays     = 730
+)
+
+// maxGoalTargets é o maior alvo aceito para cada tipo de meta
+var maxGoalTargets = map[string]int{
+	entities.GoalMinutes:       1440,
+	entities.GoalCardsReviewed: 10000,
+	entities.GoalNewCards:      1000,
+}
+
+type GoalService struct {
+	repo *StatsRepository
+}
+
+func NewGoalService(repo *StatsRepository) *GoalService {
+	return &GoalService{repo: repo}
+}
+
+// SetDailyGoal define a meta diária a partir de hoje no fuso do usuário. Os
+// dias anteriores continuam medidos pela meta que valia neles.
+func (s *GoalService) SetDailyGoal(ctx context.Context, userID, goalType string, target int) (*entities.DailyGoal, error) {
+	maxTarget, ok := maxGoalTargets[goalType]
+	if !ok {
+		return nil, fmt.Errorf("%w: type must be minutes, cards_reviewed or new_cards", ErrInvalidGoal)
+	}
+	if target <= 0 || target > maxTarget {
+		return nil, fmt.Errorf("%w: target must be between 1 and %d", ErrInvalidGoal, maxTarget)
+	}
+	loc := s.repo.userLocation(ctx, userID)
+
+	goal := &entities.DailyGoal{
+		UserID:        userID,
+		Type:          goalType,
+		Target:        target,
+		EffectiveFrom: time.Now().In(loc).Format(dateLayout),
+	}
+	if err := s.repo.SaveDailyGoal(ctx, goal); err != nil {
+		return nil, fmt.Errorf("failed to save daily goal: %w", err)
+	}
+	return goal, nil
+}
+
+// DisableDailyGoal desliga a meta a partir de hoje, mantendo o histórico
+func (s *GoalService) DisableDailyGoal(ctx context.Context, userID string) error {
+	if _, err := s.GetDailyGoal(ctx, userID); err != nil {
+		return err
+	}
+	loc := s.repo.userLocation(ctx, userID)
+
+	goal := &entities.DailyGoal{
+		UserID:        userID,
+		EffectiveFrom: time.Now().In(loc).Format(dateLayout),
+	}
+	if err := s.repo.SaveDailyGoal(ctx, goal); err != nil {
+		return fmt.Errorf("failed to save daily goal: %w", err)
+	}
+	return nil
+}
+
+// GetDailyGoal retorna a meta em vigor
+func (s *GoalService) GetDailyGoal(ctx context.Context, userID string) (*entities.DailyGoal, error) {
+	goals, err := s.repo.GetDailyGoals(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily goals: %w", err)
+	}
+	if len(goals) == 0 || goals[len(goals)-1].Target == 0 {
+		return nil, ErrNoDailyGoal
+	}
+	return &goals[len(goals)-1], nil
+}
+
+// GetTodayGoal retorna o progresso de hoje no fuso do usuário e o streak de
+// dias com a meta cumprida
+func (s *GoalService) GetTodayGoal(ctx context.Context, userID string) (*entities.GoalToday, error) {
+	loc := s.repo.userLocation(ctx, userID)
+	days, err := s.repo.getGoalDays(ctx, userID, loc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute goal progress: %w", err)
+	}
+	if len(days) == 0 || days[len(days)-1].Target == 0 {
+		return nil, ErrNoDailyGoal
+	}
+
+	return &entities.GoalToday{
+		GoalDay:  days[len(days)-1],
+		TimeZone: loc.String(),
+		Streak:   goalStreak(days),
+	}, nil
+}
+
+// GetGoalHistory retorna os últimos dias (hoje incluído) com a atividade e o
+// progresso da meta de cada um, para o calendário de atividade. Dias antes de
+// existir uma meta trazem só a atividade.
+func (s *GoalService) GetGoalHistory(ctx context.Context, userID string, days int) (*entities.GoalHistory, error) {
+	loc := s.repo.userLocation(ctx, userID)
+	if days <= 0 {
+		days = defaultGoalHistoryDays
+	}
+	if days > maxGoalHistoryDays {
+		days = maxGoalHistoryDays
+	}
+
+	goals, err := s.repo.GetDailyGoals(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily goals: %w", err)
+	}
+	to := localMidnight(time.Now(), loc)
+	from := to.AddDate(0, 0, -(days - 1))
+	// O streak pode começar antes da janela pedida
+	start := from
+	if len(goals) > 0 {
+		if first, err := time.ParseInLocation(dateLayout, goals[0].EffectiveFrom, loc); err == nil && first.Before(start) {
+			start = first
+		}
+	}
+
+	activity, err := s.repo.GetDailyActivity(ctx, userID, start, to.AddDate(0, 0, 1), loc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily activity: %w", err)
+	}
+	all := buildGoalDays(goals, activity, start, to)
+
+	history := &entities.GoalHistory{
+		TimeZone: loc.String(),
+		Streak:   goalStreak(all),
+		Days:     all[len(all)-days:],
+	}
+	for _, day := range history.Days {
+		if day.Met {
+			history.DaysMet++
+		}
+	}
+	return history, nil
+}
+
+// loadLocation carrega o fuso IANA informado; vazio ou inválido é UTC
+func loadLocation(tz string) *time.Location {
+	if tz == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// localMidnight é o início do dia de t no fuso loc
+func localMidnight(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// buildGoalDays monta um GoalDay por dia de from a to, medindo cada dia pela
+// versão da meta que valia nele
+func buildGoalDays(goals []entities.DailyGoal, activity map[string]*dailyActivity, from, to time.Time) []entities.GoalDay {
+	var days []entities.GoalDay
+	next := 0
+	var current *entities.DailyGoal
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		key := date.Format(dateLayout)
+		for next < len(goals) && goals[next].EffectiveFrom <= key {
+			current = &goals[next]
+			next++
+		}
+
+		day := entities.GoalDay{Date: key}
+		if a := activity[key]; a != nil {
+			day.Minutes = a.Seconds / 60
+			day.CardsReviewed = a.Reviews
+			day.NewCards = a.NewCards
+		}
+		if current != nil && current.Target > 0 {
+			day.Type = current.Type
+			day.Target = current.Target
+			day.Progress = goalMetric(day, current.Type)
+			day.Met = day.Progress >= day.Target
+			day.Percent = math.Min(100, float64(day.Progress)/float64(day.Target)*100)
+		}
+		days = append(days, day)
+	}
+	return days
+}
+
+// goalMetric é o valor do dia que conta para o tipo de meta
+func goalMetric(day entities.GoalDay, goalType string) int {
+	switch goalType {
+	case entities.GoalMinutes:
+		return day.Minutes
+	case entities.GoalNewCards:
+		return day.NewCards
+	default:
+		return day.CardsReviewed
+	}
+}
+
+// goalStreak conta os dias seguidos com a meta cumprida até hoje, o último
+// dia. Hoje ainda não cumprido não quebra o streak: ele continua de ontem.
+func goalStreak(days []entities.GoalDay) int {
+	end := len(days)
+	if end > 0 && !days[end-1].Met {
+		end--
+	}
+	streak := 0
+	for i := end - 1; i >= 0 && days[i].Met; i-- {
+		streak++
+	}
+	return streak
+}
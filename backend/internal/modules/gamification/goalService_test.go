@@ -0,0 +1,96 @@
+package gamification
+
+import (
+	"testing"
+	"time"
+
+	"flashcard-backend/internal/domain/entities"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildGoalDays(t *testing.T) {
+	goals := []entities.DailyGoal{
+		{Type: entities.GoalCardsReviewed, Target: 10, EffectiveFrom: "2024-03-02"},
+		{Type: entities.GoalMinutes, Target: 5, EffectiveFrom: "2024-03-04"},
+		{Target: 0, EffectiveFrom: "2024-03-05"}, // meta desligada
+	}
+	activity := map[string]*dailyActivity{
+		"2024-03-01": {Reviews: 30},
+		"2024-03-02": {Reviews: 12, Seconds: 100},
+		"2024-03-03": {Reviews: 4},
+		"2024-03-04": {Reviews: 2, Seconds: 330},
+		"2024-03-05": {Reviews: 50},
+	}
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	days := buildGoalDays(goals, activity, from, from.AddDate(0, 0, 4))
+
+	assert.Len(t, days, 5)
+	// Antes da primeira meta só há atividade
+	assert.Equal(t, "", days[0].Type)
+	assert.False(t, days[0].Met)
+	assert.Equal(t, 30, days[0].CardsReviewed)
+
+	assert.True(t, days[1].Met)
+	assert.Equal(t, 100.0, days[1].Percent)
+	assert.False(t, days[2].Met)
+	assert.Equal(t, 40.0, days[2].Percent)
+
+	// Cada dia é medido pela meta que valia nele
+	assert.Equal(t, entities.GoalMinutes, days[3].Type)
+	assert.Equal(t, 5, days[3].Progress)
+	assert.True(t, days[3].Met)
+
+	assert.Equal(t, 0, days[4].Target)
+	assert.False(t, days[4].Met)
+}
+
+func TestGoalStreak(t *testing.T) {
+	met := func(values ...bool) []entities.GoalDay {
+		days := make([]entities.GoalDay, len(values))
+		for i, value := range values {
+			days[i].Met = value
+		}
+		return days
+	}
+
+	assert.Equal(t, 3, goalStreak(met(false, true, true, true)))
+	// Hoje ainda não cumprido não quebra o streak de ontem
+	assert.Equal(t, 2, goalStreak(met(true, true, false)))
+	assert.Equal(t, 0, goalStreak(met(true, false, false)))
+	assert.Equal(t, 0, goalStreak(nil))
+}
+
+func TestLoadLocation(t *testing.T) {
+	assert.Equal(t, time.UTC, loadLocation(""))
+	assert.Equal(t, time.UTC, loadLocation("Marte/Base"))
+	assert.Equal(t, "America/Sao_Paulo", loadLocation("America/Sao_Paulo").String())
+}
+
+func TestSetStatsDateFields(t *testing.T) {
+	saoPaulo := loadLocation("America/Sao_Paulo")
+	// 23h em São Paulo já é o dia seguinte em UTC
+	stats := &entities.StudyStats{CreatedAt: time.Date(2024, 12, 31, 2, 0, 0, 0, time.UTC)}
+	SetStatsDateFields(stats, saoPaulo)
+	assert.Equal(t, time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC), stats.Date)
+	assert.Equal(t, 12, stats.Month)
+	assert.Equal(t, 1, stats.Week)
+	assert.Equal(t, 2025, stats.Year) // ano ISO da semana
+
+	SetStatsDateFields(stats, time.UTC)
+	assert.Equal(t, time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), stats.Date)
+}
+
+func TestStudyDayStreak(t *testing.T) {
+	today := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	days := map[time.Time]bool{
+		today.AddDate(0, 0, -1): true,
+		today.AddDate(0, 0, -2): true,
+		today.AddDate(0, 0, -4): true,
+	}
+	assert.Equal(t, 2, studyDayStreak(days, today))
+
+	days[today] = true
+	assert.Equal(t, 3, studyDayStreak(days, today))
+	assert.Equal(t, 0, studyDayStreak(days, today.AddDate(0, 0, 3)))
+}
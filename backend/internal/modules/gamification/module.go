@@ -12,6 +12,8 @@ type Module struct {
 	StatsService       *StatsService
 	StatsRepo          *StatsRepository
 	StatsHandler       *StatsHandler
+	GoalService        *GoalService
+	GoalHandler        *GoalHandler
 }
 
 func NewModule(db *database.MongoDB, cfg *config.Config) *Module {
@@ -24,6 +26,9 @@ func NewModule(db *database.MongoDB, cfg *config.Config) *Module {
 	achievementHandler := NewAchievementHandler(achievementService)
 	statsHandler := NewStatsHandler(db)
 
+	goalService := NewGoalService(statsRepo)
+	goalHandler := NewGoalHandler(goalService)
+
 	return &Module{
 		Handler:            achievementHandler,
 		AchievementService: achievementService,
@@ -31,5 +36,7 @@ func NewModule(db *database.MongoDB, cfg *config.Config) *Module {
 		StatsService:       statsService,
 		StatsRepo:          statsRepo,
 		StatsHandler:       statsHandler,
+		GoalService:        goalService,
+		GoalHandler:        goalHandler,
 	}
 }
@@ -20,21 +20,45 @@ func NewStatsRepository(db *database.MongoDB) *StatsRepository {
 	return &StatsRepository{db: db}
 }
 
-// CreateStudyStats cria uma nova entrada de estatística
+// CreateStudyStats cria uma nova entrada de estatística, datada por CreatedAt
+// quando preenchido
 func (r *StatsRepository) CreateStudyStats(ctx context.Context, stats *entities.StudyStats) error {
-	now := time.Now()
-	stats.CreatedAt = now
-	stats.Date = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	year, week := now.ISOWeek()
-	stats.Week = week
-	stats.Month = int(now.Month())
-	stats.Year = year
+	if stats.CreatedAt.IsZero() {
+		stats.CreatedAt = time.Now()
+	}
+	SetStatsDateFields(stats, r.userLocation(ctx, stats.UserID))
 
 	collection := r.db.GetCollection("study_stats")
 	_, err := collection.InsertOne(ctx, stats)
 	return err
 }
 
+// SetStatsDateFields preenche os campos de agrupamento a partir de CreatedAt
+// no fuso do usuário. Date guarda o dia local à meia-noite UTC, para o dia
+// não mudar conforme o fuso de quem lê.
+func SetStatsDateFields(stats *entities.StudyStats, loc *time.Location) {
+	local := stats.CreatedAt.In(loc)
+	stats.Date = time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	year, week := local.ISOWeek()
+	stats.Week = week
+	stats.Month = int(local.Month())
+	stats.Year = year
+}
+
+// userLocation retorna o fuso do usuário; sem fuso válido, UTC
+func (r *StatsRepository) userLocation(ctx context.Context, userID string) *time.Location {
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return time.UTC
+	}
+	var user entities.User
+	opts := options.FindOne().SetProjection(bson.M{"time_zone": 1})
+	if err := r.db.GetCollection("users").FindOne(ctx, bson.M{"_id": id}, opts).Decode(&user); err != nil {
+		return time.UTC
+	}
+	return loadLocation(user.TimeZone)
+}
+
 // GetStudyStatsSummary retorna um resumo das estatísticas do usuário
 func (r *StatsRepository) GetStudyStatsSummary(ctx context.Context, userID string) (*entities.StudyStatsSummary, error) {
 	collection := r.db.GetCollection("study_stats")
@@ -85,9 +109,11 @@ func (r *StatsRepository) GetStudyStatsSummary(ctx context.Context, userID strin
 		}
 	}
 
-	// Buscar streak atual
-	streak, err := r.getCurrentStreak(ctx, userID)
-	if err == nil {
+	// Buscar streak atual; com meta diária, são os dias seguidos com a meta cumprida
+	loc := r.userLocation(ctx, userID)
+	if days, err := r.getGoalDays(ctx, userID, loc); err == nil && len(days) > 0 {
+		summary.StudyStreak = goalStreak(days)
+	} else if streak, err := r.getCurrentStreak(ctx, userID, loc); err == nil {
 		summary.StudyStreak = streak
 	}
 
@@ -104,25 +130,28 @@ func (r *StatsRepository) GetStudyStatsSummary(ctx context.Context, userID strin
 func (r *StatsRepository) GetStudyStatsByPeriod(ctx context.Context, userID string, period string, days int) ([]entities.StudyStatsByPeriod, error) {
 	collection := r.db.GetCollection("study_stats")
 
-	startDate := time.Now().AddDate(0, 0, -days)
+	// Os períodos seguem os campos de data gravados no fuso do usuário; year
+	// é o ano ISO da semana, então dia e mês usam o ano de date
+	today := time.Now().In(r.userLocation(ctx, userID))
+	startDate := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -days)
 
 	var groupBy bson.M
 
 	switch period {
 	case "day":
-		groupBy = bson.M{"year": "$year", "month": "$month", "day": bson.M{"$dayOfMonth": "$created_at"}}
+		groupBy = bson.M{"year": bson.M{"$year": "$date"}, "month": "$month", "day": bson.M{"$dayOfMonth": "$date"}}
 	case "week":
 		groupBy = bson.M{"year": "$year", "week": "$week"}
 	case "month":
-		groupBy = bson.M{"year": "$year", "month": "$month"}
+		groupBy = bson.M{"year": bson.M{"$year": "$date"}, "month": "$month"}
 	default:
-		groupBy = bson.M{"year": "$year", "month": "$month", "day": bson.M{"$dayOfMonth": "$created_at"}}
+		groupBy = bson.M{"year": bson.M{"$year": "$date"}, "month": "$month", "day": bson.M{"$dayOfMonth": "$date"}}
 	}
 
 	pipeline := []bson.M{
 		{"$match": bson.M{
-			"user_id":    userID,
-			"created_at": bson.M{"$gte": startDate},
+			"user_id": userID,
+			"date":    bson.M{"$gte": startDate},
 		}},
 		{"$group": bson.M{
 			"_id":             groupBy,
@@ -319,23 +348,40 @@ func (r *StatsRepository) GetDeckPerformance(ctx context.Context, userID string)
 	return performance, nil
 }
 
-// getCurrentStreak calcula o streak atual do usuário
-func (r *StatsRepository) getCurrentStreak(ctx context.Context, userID string) (int, error) {
+// getCurrentStreak calcula o streak atual do usuário: os dias seguidos, no
+// fuso loc, com ao menos uma revisão
+func (r *StatsRepository) getCurrentStreak(ctx context.Context, userID string, loc *time.Location) (int, error) {
 	collection := r.db.GetCollection("study_stats")
 
-	// Buscar a última entrada de streak
-	opts := options.FindOne().SetSort(bson.M{"created_at": -1})
-	var lastStats bson.M
-	err := collection.FindOne(ctx, bson.M{"user_id": userID, "streak": bson.M{"$exists": true}}, opts).Decode(&lastStats)
+	values, err := collection.Distinct(ctx, "date", bson.M{"user_id": userID, "action_type": "card_review"})
 	if err != nil {
 		return 0, err
 	}
-
-	if streak, ok := lastStats["streak"].(int32); ok {
-		return int(streak), nil
+	days := make(map[time.Time]bool, len(values))
+	for _, value := range values {
+		if date, ok := value.(primitive.DateTime); ok {
+			days[date.Time().UTC()] = true
+		}
 	}
 
-	return 0, nil
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return studyDayStreak(days, today), nil
+}
+
+// studyDayStreak conta os dias seguidos com estudo até hoje. Hoje ainda sem
+// estudo não quebra o streak: ele continua de ontem.
+func studyDayStreak(days map[time.Time]bool, today time.Time) int {
+	day := today
+	if !days[day] {
+		day = day.AddDate(0, 0, -1)
+	}
+	streak := 0
+	for days[day] {
+		streak++
+		day = day.AddDate(0, 0, -1)
+	}
+	return streak
 }
 
 // getCurrentLevel calcula o nível atual do usuário
@@ -367,7 +413,7 @@ func (r *StatsRepository) GetTimeDistribution(ctx context.Context, userID string
 			"action_type": "card_review",
 		}},
 		{"$addFields": bson.M{
-			"hour": bson.M{"$hour": "$created_at"},
+			"hour": bson.M{"$hour": bson.M{"date": "$created_at", "timezone": r.userLocation(ctx, userID).String()}},
 		}},
 		{"$group": bson.M{
 			"_id": nil,
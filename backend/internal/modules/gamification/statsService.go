@@ -2,10 +2,20 @@ package gamification
 
 import (
 	"context"
+	"errors"
+	"time"
+
 	"flashcard-backend/internal/domain/entities"
 	"flashcard-backend/internal/infrastructure/database"
 )
 
+// MaxReviewBackdate é o quanto uma revisão registrada depois de feita, como
+// as offline, pode ser anterior ao registro. Limita quanto do histórico
+// (sequências, metas) um horário informado pelo cliente consegue mudar.
+const MaxReviewBackdate = 7 * 24 * time.Hour
+
+var ErrReviewTooOld = errors.New("review time is too far in the past")
+
 type StatsService struct {
 	repo *StatsRepository
 }
@@ -18,10 +28,16 @@ func NewStatsService(db *database.MongoDB) *StatsService {
 
 // LogStudyAction registra uma ação de estudo
 func (s *StatsService) LogStudyAction(ctx context.Context, userID string, actionType string, metadata map[string]interface{}) error {
+	return s.logStudyActionAt(ctx, userID, actionType, metadata, time.Time{})
+}
+
+// logStudyActionAt registra a ação no horário informado; zero é agora
+func (s *StatsService) logStudyActionAt(ctx context.Context, userID string, actionType string, metadata map[string]interface{}, at time.Time) error {
 	stats := &entities.StudyStats{
 		UserID:     userID,
 		ActionType: actionType,
 		Metadata:   metadata,
+		CreatedAt:  at,
 	}
 
 	// Adicionar campos específicos baseados no tipo de ação
@@ -117,11 +133,18 @@ func (s *StatsService) GetDeckPerformance(ctx context.Context, userID string) ([
 	return s.repo.GetDeckPerformance(ctx, userID)
 }
 
-// LogStudySessionStart registra o início de uma sessão de estudo
-func (s *StatsService) LogStudySessionStart(ctx context.Context, userID, sessionID, deckID string) error {
+// LogStudySessionStart registra o início de uma sessão de estudo. Uma sessão
+// de um deck grava deck_id; uma intercalada grava todos em deck_ids. Sessões
+// filtradas não têm deck: as revisões delas levam o deck de cada card.
+func (s *StatsService) LogStudySessionStart(ctx context.Context, userID, sessionID string, deckIDs []string) error {
 	metadata := map[string]interface{}{
 		"session_id": sessionID,
-		"deck_id":    deckID,
+	}
+	switch {
+	case len(deckIDs) == 1:
+		metadata["deck_id"] = deckIDs[0]
+	case len(deckIDs) > 1:
+		metadata["deck_ids"] = deckIDs
 	}
 	return s.LogStudyAction(ctx, userID, "study_session_start", metadata)
 }
@@ -151,6 +174,28 @@ func (s *StatsService) LogCardReview(ctx context.Context, userID, deckID, cardID
 	return s.LogStudyAction(ctx, userID, "card_review", metadata)
 }
 
+// LogCardReviewAt registra uma revisão feita em outro momento, como as
+// revisões offline recebidas na sincronização. Horários no futuro viram agora
+// e os anteriores a MaxReviewBackdate são recusados.
+func (s *StatsService) LogCardReviewAt(ctx context.Context, userID, deckID, cardID, difficulty string, isCorrect bool, studyTime, xp int, reviewedAt time.Time) error {
+	now := time.Now()
+	if reviewedAt.After(now) {
+		reviewedAt = now
+	}
+	if reviewedAt.Before(now.Add(-MaxReviewBackdate)) {
+		return ErrReviewTooOld
+	}
+	metadata := map[string]interface{}{
+		"deck_id":    deckID,
+		"card_id":    cardID,
+		"difficulty": difficulty,
+		"is_correct": isCorrect,
+		"study_time": studyTime,
+		"xp":         xp,
+	}
+	return s.logStudyActionAt(ctx, userID, "card_review", metadata, reviewedAt)
+}
+
 // LogDeckCreated registra a criação de um deck
 func (s *StatsService) LogDeckCreated(ctx context.Context, userID, deckID string, xp int) error {
 	metadata := map[string]interface{}{
@@ -0,0 +1,20 @@
+package upload
+
+import (
+	"flashcard-backend/internal/config"
+)
+
+type Module struct {
+	Handler *Handler
+	Service *Service
+}
+
+func NewModule(cfg *config.Config) *Module {
+	service := NewService(cfg)
+	handler := NewHandler(service, cfg)
+
+	return &Module{
+		Handler: handler,
+		Service: service,
+	}
+}
@@ -1,7 +1,7 @@
 package upload
 
 import (
-	"context"
+	"bytes"
 	"fmt"
 	"mime/multipart"
 	"path/filepath"
@@ -73,6 +73,40 @@ func (s *Service) UploadFile(file *multipart.FileHeader, folder string) (string,
 	return url, nil
 }
 
+// UploadBytes envia um conteúdo já carregado em memória, como mídias
+// restauradas de um backup
+func (s *Service) UploadBytes(data []byte, folder, ext, contentType string) (string, error) {
+	filename := fmt.Sprintf("%s/%s-%s%s", folder, uuid.New().String(), time.Now().Format("20060102-150405"), ext)
+
+	_, err := s.s3Client.PutObject(&s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(filename),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String(contentType),
+		ACL:         aws.String("public-read"),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload to S3: %w", err)
+	}
+
+	url := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.bucket, s.cfg.AWS.Region, filename)
+	return url, nil
+}
+
+// IsConfigured indica se há um bucket configurado para envio de arquivos
+func (s *Service) IsConfigured() bool {
+	return s.bucket != ""
+}
+
+// MediaHosts são os hosts das URLs públicas geradas pelo envio. Só deles o
+// servidor baixa mídias de volta (backups, verificação de links).
+func (s *Service) MediaHosts() []string {
+	if !s.IsConfigured() {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s.s3.%s.amazonaws.com", s.bucket, s.cfg.AWS.Region)}
+}
+
 func (s *Service) DeleteFile(url string) error {
 	// Extract key from URL
 	key := strings.TrimPrefix(url, fmt.Sprintf("https://%s.s3.%s.amazonaws.com/", s.bucket, s.cfg.AWS.Region))